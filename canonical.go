@@ -0,0 +1,35 @@
+package ledger
+
+import (
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// RegisterCanonical canonicalizes obj, hashes the canonical bytes and appends a
+// register storing both the hash and the canonical JSON.
+//
+// Canonical form is hash.Canonicalize: object keys sorted, no insignificant
+// whitespace, no HTML escaping, no trailing newline.
+// Semantically identical objects therefore always produce the same leaf,
+// regardless of how a client ordered keys or formatted whitespace. The hash is
+// the one merkle.LeafHashFromObject derives for obj.
+//
+// Returns the lowercase hex object hash that was registered (64 chars, or 128 under sha512).
+func RegisterCanonical(obj interface{}) (string, error) {
+	canonical, err := hash.Canonicalize(obj)
+	if err != nil {
+		return "", err
+	}
+
+	hashHex := ComputeObjectHash(canonical)
+	if err := AppendRegister(hashHex, canonical); err != nil {
+		return "", err
+	}
+
+	return hashHex, nil
+}
+
+// ComputeObjectHash returns the hash of canonical bytes as lowercase hex: SHA-256
+// (64 chars) by default, SHA-512 (128 chars) after SetHashAlg("sha512").
+func ComputeObjectHash(canonicalJSON []byte) string {
+	return activeObjectHash.Load().sum(canonicalJSON)
+}
@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+func TestRegisterCanonical_KeyOrderIndependent(t *testing.T) {
+	setupTestLedger(t)
+
+	var a, b map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"user":"alice","score":100,"meta":{"z":1,"a":[3,2,1]}}`), &a); err != nil {
+		t.Fatalf("unmarshal a: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{ "meta" : { "a":[3,2,1], "z":1 }, "score":100, "user":"alice" }`), &b); err != nil {
+		t.Fatalf("unmarshal b: %v", err)
+	}
+
+	hashA, err := RegisterCanonical(a)
+	if err != nil {
+		t.Fatalf("RegisterCanonical(a) failed: %v", err)
+	}
+	hashB, err := RegisterCanonical(b)
+	if err != nil {
+		t.Fatalf("RegisterCanonical(b) failed: %v", err)
+	}
+
+	if hashA != hashB {
+		t.Errorf("hashes differ for semantically identical objects: %s vs %s", hashA, hashB)
+	}
+}
+
+func TestLeafHashFromObject_MatchesComputeObjectHash(t *testing.T) {
+	obj := map[string]interface{}{"user": "alice", "score": 100, "tags": []string{"<a>", "b&c"}}
+	canonical, err := hash.Canonicalize(obj)
+	if err != nil {
+		t.Fatalf("Canonicalize failed: %v", err)
+	}
+
+	for _, alg := range []string{"sha256", "sha512"} {
+		t.Run(alg, func(t *testing.T) {
+			setupTestLedger(t)
+			if err := SetHashAlg(alg); err != nil {
+				t.Fatalf("SetHashAlg failed: %v", err)
+			}
+			t.Cleanup(func() { SetHashAlg(HeaderHashAlg) })
+
+			leaf, err := merkle.LeafHashFromObject(obj)
+			if err != nil {
+				t.Fatalf("LeafHashFromObject failed: %v", err)
+			}
+			if want := ComputeObjectHash(canonical); leaf != want {
+				t.Fatalf("leaf = %s, ComputeObjectHash = %s", leaf, want)
+			}
+
+			registered, err := RegisterCanonical(obj)
+			if err != nil {
+				t.Fatalf("RegisterCanonical failed: %v", err)
+			}
+			if registered != leaf {
+				t.Errorf("registered hash = %s, leaf = %s", registered, leaf)
+			}
+		})
+	}
+}
+
+func TestRegisterCanonical_StoresCanonicalJSON(t *testing.T) {
+	setupTestLedger(t)
+
+	obj := struct {
+		Zeta  string `json:"zeta"`
+		Alpha string `json:"alpha"`
+		HTML  string `json:"html"`
+	}{Zeta: "z", Alpha: "a", HTML: "<b>&</b>"}
+
+	hashHex, err := RegisterCanonical(obj)
+	if err != nil {
+		t.Fatalf("RegisterCanonical failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 1 {
+		t.Fatalf("expected 1 register, got %d", len(registers))
+	}
+
+	stored, err := base64.StdEncoding.DecodeString(registers[0].CanonicalJSONB64)
+	if err != nil {
+		t.Fatalf("failed to decode base64: %v", err)
+	}
+
+	want := `{"alpha":"a","html":"<b>&</b>","zeta":"z"}`
+	if string(stored) != want {
+		t.Errorf("canonical JSON = %s, want %s", stored, want)
+	}
+	if registers[0].ObjectHashHex != hashHex || ComputeObjectHash(stored) != hashHex {
+		t.Errorf("stored hash %s does not match canonical bytes", registers[0].ObjectHashHex)
+	}
+}
+
+func TestRegisterCanonical_Unmarshalable(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, err := RegisterCanonical(make(chan int)); err == nil {
+		t.Fatalf("expected error for unmarshalable object")
+	}
+}
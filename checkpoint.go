@@ -0,0 +1,105 @@
+package ledger
+
+import (
+	"fmt"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// Checkpoint commits to a run of sealed epochs with one hash: TopRoot is the
+// Merkle root over the epochs' merkle_roots, in epoch order (the "root of
+// roots"). Leaf i is Roots[i], the root of epoch EpochIDs[i], so proving that
+// an epoch belongs to the history takes one proof of O(log epochs) nodes
+// instead of rebuilding every epoch tree.
+type Checkpoint struct {
+	EpochIDs []int
+	Roots    []string
+	TopRoot  string
+
+	tree *merkle.LevelTree
+}
+
+// NewCheckpoint builds the checkpoint over seals, which must be in strictly
+// ascending EpochID order (as they appear in the ledger).
+//
+// Returns merkle.ErrEmptyLeaves for no seals, ErrEpochOutOfSequence if the
+// epoch IDs do not ascend, or a merkle error for a malformed merkle_root.
+func NewCheckpoint(seals []SealEntry) (*Checkpoint, error) {
+	c := &Checkpoint{
+		EpochIDs: make([]int, len(seals)),
+		Roots:    make([]string, len(seals)),
+	}
+	for i, seal := range seals {
+		if i > 0 && seal.Manifest.EpochID <= c.EpochIDs[i-1] {
+			return nil, fmt.Errorf("%w: checkpoint epoch %d follows epoch %d", ErrEpochOutOfSequence, seal.Manifest.EpochID, c.EpochIDs[i-1])
+		}
+		c.EpochIDs[i] = seal.Manifest.EpochID
+		c.Roots[i] = seal.Manifest.MerkleRoot
+	}
+
+	tree, err := merkle.BuildLevelTree(c.Roots)
+	if err != nil {
+		return nil, err
+	}
+	c.tree = tree
+	c.TopRoot = tree.Root()
+	return c, nil
+}
+
+// Proof returns the inclusion proof of epochID's root under TopRoot, with the
+// leaf index and total leaves merkle.VerifyProof needs. found is false if the
+// checkpoint does not cover epochID.
+func (c *Checkpoint) Proof(epochID int) (proof []merkle.ProofNode, index int, found bool) {
+	for i, id := range c.EpochIDs {
+		if id == epochID {
+			proof, _ = c.tree.Proof(i) // i is always in range
+			return proof, i, true
+		}
+	}
+	return nil, 0, false
+}
+
+// BuildCheckpoint is NewCheckpoint returning just the top root and a proof
+// lookup. proofFor returns nil for an epoch the checkpoint does not cover; a
+// verifier checks a proof with merkle.RootFromProof(epochRoot, proof) == topRoot.
+func BuildCheckpoint(seals []SealEntry) (topRoot string, proofFor func(epochID int) []merkle.ProofNode, err error) {
+	c, err := NewCheckpoint(seals)
+	if err != nil {
+		return "", nil, err
+	}
+	return c.TopRoot, func(epochID int) []merkle.ProofNode {
+		proof, _, _ := c.Proof(epochID)
+		return proof
+	}, nil
+}
+
+// checkpointSignedFields is what a checkpoint signature commits to, canonicalized
+type checkpointSignedFields struct {
+	CheckpointRoot string `json:"checkpoint_root"`
+	EpochCount     int    `json:"epoch_count"`
+	Timestamp      string `json:"timestamp"`
+}
+
+// CheckpointSigningDigest returns the message a server signs to vouch for a
+// checkpoint: the 64 hex SHA-256 of the canonical JSON of topRoot, epochCount
+// and timestamp (normalized). Signing all three means a genuine signature
+// cannot be replayed with another epoch count or time, and the digest is 32
+// bytes under either hash algorithm.
+//
+// Returns ErrInvalidTimestamp if timestamp is not RFC3339.
+func CheckpointSigningDigest(topRoot string, epochCount int, timestamp string) (string, error) {
+	ts, err := ParseCanonTimestamp(timestamp)
+	if err != nil {
+		return "", err
+	}
+	canon, err := hash.Canonicalize(checkpointSignedFields{
+		CheckpointRoot: topRoot,
+		EpochCount:     epochCount,
+		Timestamp:      NormalizeTimestamp(ts),
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash.Sha256Hex(canon), nil
+}
@@ -0,0 +1,67 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+func TestBuildCheckpoint_FourEpochs(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1, 2, 3, 1)
+	seals := readSeals(t)
+
+	topRoot, proofFor, err := BuildCheckpoint(seals)
+	if err != nil {
+		t.Fatalf("BuildCheckpoint failed: %v", err)
+	}
+
+	roots := make([]string, len(seals))
+	for i, seal := range seals {
+		roots[i] = seal.Manifest.MerkleRoot
+	}
+	if want, _ := merkle.BuildRoot(roots); topRoot != want {
+		t.Fatalf("topRoot = %s, want root over epoch roots %s", topRoot, want)
+	}
+
+	for i, seal := range seals {
+		proof := proofFor(seal.Manifest.EpochID)
+		if len(proof) != 2 {
+			t.Fatalf("epoch %d: proof has %d nodes, want 2", seal.Manifest.EpochID, len(proof))
+		}
+		if ok, err := merkle.VerifyProof(seal.Manifest.MerkleRoot, i, len(seals), proof, topRoot); !ok {
+			t.Errorf("epoch %d: proof does not verify: %v", seal.Manifest.EpochID, err)
+		}
+		// Another epoch's root must not verify with this proof
+		other := seals[(i+1)%len(seals)].Manifest.MerkleRoot
+		if got, _ := merkle.RootFromProof(other, proof); got == topRoot {
+			t.Errorf("epoch %d: proof accepts epoch root %s", seal.Manifest.EpochID, other)
+		}
+	}
+
+	if proof := proofFor(7); proof != nil {
+		t.Errorf("uncovered epoch: proof = %v, want nil", proof)
+	}
+}
+
+func TestNewCheckpoint_Invalid(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1, 1)
+	seals := readSeals(t)
+
+	if _, err := NewCheckpoint(nil); !errors.Is(err, merkle.ErrEmptyLeaves) {
+		t.Errorf("no seals: expected ErrEmptyLeaves, got %v", err)
+	}
+	if _, err := NewCheckpoint([]SealEntry{seals[1], seals[0]}); !errors.Is(err, ErrEpochOutOfSequence) {
+		t.Errorf("descending epochs: expected ErrEpochOutOfSequence, got %v", err)
+	}
+
+	c, err := NewCheckpoint(seals[1:])
+	if err != nil {
+		t.Fatalf("NewCheckpoint failed: %v", err)
+	}
+	if _, index, found := c.Proof(1); !found || index != 0 || c.TopRoot != seals[1].Manifest.MerkleRoot {
+		t.Errorf("single-epoch checkpoint: found=%v index=%d top=%s", found, index, c.TopRoot)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// FORGED-LRO — Ledger query
+// Prints the registers stamped after --since, in file order, as JSONL (one
+// RegisterEntry per line) or as a table. The ledger is only read.
+
+// exitCorrupt is the exit code for a ledger with an unparseable line
+const exitCorrupt = 3
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run queries the ledger and returns the exit code: 0 on success, 1 if the
+// ledger cannot be read, 2 for usage errors, exitCorrupt for a corrupt line.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("ledger_query", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ledgerPath := fs.String("ledger", os.Getenv("RVA_LEDGER_PATH"), "Path to the ledger JSONL file (default $RVA_LEDGER_PATH or "+ledger.GetLedgerPath()+")")
+	sinceFlag := fs.String("since", "", "Only registers strictly after this RFC3339 timestamp (default: all)")
+	format := fs.String("format", "jsonl", "Output format: jsonl or table")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var since time.Time
+	if *sinceFlag != "" {
+		ts, err := ledger.ParseCanonTimestamp(*sinceFlag)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid --since %q: expected RFC3339, e.g. 2026-01-10T00:00:00Z\n", *sinceFlag)
+			return 2
+		}
+		since = ts
+	}
+	if *format != "jsonl" && *format != "table" {
+		fmt.Fprintf(stderr, "invalid --format %q: expected jsonl or table\n", *format)
+		return 2
+	}
+	if *ledgerPath != "" {
+		ledger.SetLedgerPath(*ledgerPath)
+	}
+
+	registers, err := ledger.ListRegistersSince(since)
+	var corrupt *ledger.CorruptError
+	if errors.As(err, &corrupt) {
+		fmt.Fprintf(stderr, "%s: corrupt line %d: %s\n", ledger.GetLedgerPath(), corrupt.LineNum, corrupt.Reason)
+		return exitCorrupt
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cannot read ledger: %v\n", err)
+		return 1
+	}
+
+	if *format == "table" {
+		writeTable(stdout, registers)
+		return 0
+	}
+	enc := json.NewEncoder(stdout)
+	for _, reg := range registers {
+		if err := enc.Encode(reg); err != nil {
+			fmt.Fprintf(stderr, "cannot write output: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// writeTable prints one aligned row per register
+func writeTable(w io.Writer, registers []ledger.RegisterEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIMESTAMP\tOBJECT_HASH_HEX\tPAYLOAD_BYTES\tSIGNED")
+	for _, reg := range registers {
+		payload, _ := base64.StdEncoding.DecodeString(reg.CanonicalJSONB64)
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%t\n", reg.Timestamp, reg.ObjectHashHex, len(payload), reg.Signature != "")
+	}
+	tw.Flush()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// registerHashes appends one register per value and returns their entries
+func registerHashes(t *testing.T, vals ...string) []ledger.RegisterEntry {
+	t.Helper()
+	fixture.Register(t, vals...)
+	registers, err := ledger.ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	return registers
+}
+
+func TestRun_SinceFiltersJSONL(t *testing.T) {
+	path := fixture.TempLedger(t)
+	registers := registerHashes(t, "a", "b", "c")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--ledger", path, "--since", registers[0].Timestamp}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 registers after the first, got %d:\n%s", len(lines), stdout.String())
+	}
+	for i, line := range lines {
+		var reg ledger.RegisterEntry
+		if err := json.Unmarshal([]byte(line), &reg); err != nil {
+			t.Fatalf("line %d is not a register: %v", i, err)
+		}
+		if reg.ObjectHashHex != registers[i+1].ObjectHashHex {
+			t.Errorf("line %d = %s, want %s", i, reg.ObjectHashHex, registers[i+1].ObjectHashHex)
+		}
+	}
+}
+
+func TestRun_TableFormat(t *testing.T) {
+	path := fixture.TempLedger(t)
+	registers := registerHashes(t, "a", "b")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path, "--format", "table"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.HasPrefix(out, "TIMESTAMP") {
+		t.Errorf("missing table header:\n%s", out)
+	}
+	for _, reg := range registers {
+		if !strings.Contains(out, reg.ObjectHashHex) {
+			t.Errorf("table is missing %s:\n%s", reg.ObjectHashHex, out)
+		}
+	}
+}
+
+func TestRun_CorruptLineExitCode(t *testing.T) {
+	path := fixture.TempLedger(t)
+	registerHashes(t, "a", "b")
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	file.WriteString("this is not valid json\n")
+	file.Close()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path}, &stdout, &stderr); code != exitCorrupt {
+		t.Fatalf("exit code %d, want %d", code, exitCorrupt)
+	}
+	if !strings.Contains(stderr.String(), "corrupt line 3") {
+		t.Errorf("stderr does not name the bad line: %s", stderr.String())
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	fixture.TempLedger(t)
+	for _, args := range [][]string{
+		{"--since", "yesterday"},
+		{"--format", "xml"},
+	} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("%v: exit code %d, want 2", args, code)
+		}
+	}
+}
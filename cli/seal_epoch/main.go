@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+)
+
+// FORGED-LRO — Epoch manifest generator
+// Computes the Merkle root over the registers pending since the last seal,
+// signs it and writes the epoch_manifest.json consumed by verify_certificate.
+// The ledger is only read: committing the seal is left to the server (POST /seal).
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run generates the manifest and returns the exit code: 0 on success,
+// 1 if no manifest could be produced, 2 for usage errors.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("seal_epoch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ledgerPath := fs.String("ledger", os.Getenv("RVA_LEDGER_PATH"), "Path to the ledger JSONL file (default $RVA_LEDGER_PATH or "+ledger.GetLedgerPath()+")")
+	seed := fs.String("seed", "", "Seal seed as 64 lowercase hex (default $RVA_SEAL_SEED)")
+	out := fs.String("out", "", "Write the manifest to this path instead of stdout")
+	policyPath := fs.String("policy", os.Getenv("RVA_POLICY_PATH"), "Seal under this rotation policy: stamp its hash and enforce its depth bounds (default $RVA_POLICY_PATH)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	seedHex := *seed
+	if seedHex == "" {
+		seedHex = os.Getenv("RVA_SEAL_SEED")
+	}
+	if seedHex == "" {
+		fmt.Fprintln(stderr, "no seal seed: pass --seed or set RVA_SEAL_SEED")
+		return 2
+	}
+	if *ledgerPath != "" {
+		ledger.SetLedgerPath(*ledgerPath)
+	}
+	if *policyPath != "" {
+		pol, err := policy.LoadPolicy(*policyPath)
+		if err == nil {
+			err = ledger.SetSealPolicy(pol)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "cannot load seal policy: %v\n", err)
+			return 1
+		}
+	}
+
+	manifest, pending, err := ledger.PrepareSeal(seedHex)
+	if errors.Is(err, ledger.ErrNoRegistrations) {
+		fmt.Fprintf(stderr, "refusing to seal %s: %v\n", ledger.GetLedgerPath(), err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cannot prepare seal: %v\n", err)
+		return 1
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		fmt.Fprintf(stderr, "cannot encode manifest: %v\n", err)
+		return 1
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		_, _ = stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		fmt.Fprintf(stderr, "cannot write manifest: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "epoch %d: %d registers, root %s -> %s\n", manifest.EpochID, len(pending), manifest.MerkleRoot, *out)
+	return 0
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+const testSeedHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestRun_WritesManifestTheVerifierAccepts(t *testing.T) {
+	path := fixture.TempLedger(t)
+	leaves := fixture.Register(t, "a", "b", "c")
+
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "epoch_manifest.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path, "--seed", testSeedHex, "--out", manifestPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		t.Fatalf("failed to read manifest: %v", err)
+	}
+	var m ledger.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("invalid manifest JSON: %v", err)
+	}
+	root, err := merkle.BuildRoot(leaves)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	pub, err := sign.PublicKeyFromSeedHex(testSeedHex)
+	if err != nil {
+		t.Fatalf("PublicKeyFromSeedHex failed: %v", err)
+	}
+	if m.MerkleRoot != root || m.PublicKey != pub || m.EpochID != 0 || m.Timestamp == "" {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+
+	// The manifest is only written, never appended to the ledger
+	if seal, found, err := ledger.GetSealByEpoch(0); err != nil || found {
+		t.Errorf("ledger was sealed: %+v, %v", seal, err)
+	}
+
+	// Hand a proof for leaf 1 and the manifest to the offline verifier
+	proof, err := merkle.NewProof(leaves, 1)
+	if err != nil {
+		t.Fatalf("NewProof failed: %v", err)
+	}
+	proofJSON, err := merkle.MarshalProof(proof)
+	if err != nil {
+		t.Fatalf("MarshalProof failed: %v", err)
+	}
+	proofPath := filepath.Join(dir, "proof.json")
+	if err := os.WriteFile(proofPath, proofJSON, 0644); err != nil {
+		t.Fatalf("failed to write proof: %v", err)
+	}
+
+	verifier := buildVerifier(t)
+	cmd := exec.Command(verifier, "--proof", proofPath, "--manifest", manifestPath)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("verifier rejected the manifest: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "VALID: leaf is included under a signed epoch root") {
+		t.Errorf("unexpected verifier output:\n%s", output)
+	}
+}
+
+// buildVerifier compiles cli/verify_certificate into a temp dir
+func buildVerifier(t *testing.T) string {
+	t.Helper()
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available to build the verifier")
+	}
+	bin := filepath.Join(t.TempDir(), "verify_certificate")
+	cmd := exec.Command(goTool, "build", "-o", bin, "..")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build verifier: %v\n%s", err, output)
+	}
+	return bin
+}
+
+func TestRun_NoPendingRegisters(t *testing.T) {
+	path := fixture.TempLedger(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path, "--seed", testSeedHex}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), ledger.ErrNoRegistrations.Error()) {
+		t.Errorf("stderr does not name the cause: %s", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("no manifest expected on stdout, got %s", stdout.String())
+	}
+}
+
+func TestRun_SeedFromEnvironment(t *testing.T) {
+	path := fixture.TempLedger(t)
+	fixture.Register(t, "a")
+	t.Setenv("RVA_SEAL_SEED", testSeedHex)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	var m ledger.Manifest
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		t.Fatalf("stdout is not a manifest: %v", err)
+	}
+	if ok, err := ledger.VerifyManifestSignature(m); !ok {
+		t.Errorf("manifest signature does not verify: %v", err)
+	}
+}
+
+func TestRun_MissingSeed(t *testing.T) {
+	fixture.TempLedger(t)
+	t.Setenv("RVA_SEAL_SEED", "")
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 2 {
+		t.Fatalf("exit code %d, want 2", code)
+	}
+}
+
+func TestRun_PolicyDepthBounds(t *testing.T) {
+	path := fixture.TempLedger(t)
+	t.Cleanup(func() { ledger.SetSealPolicy(nil) })
+	pol := &policy.RotationPolicy{
+		PolicyVersion: "1.0",
+		Issuer:        policy.IssuerInfo{Name: "Alpha", ID: "rva://1"},
+		Epochs:        policy.EpochConfig{IntervalSeconds: 86400, IDFormat: "numeric_ascending"},
+		Constraints: policy.CryptoConstraints{
+			HashAlg:         "sha256",
+			AllowedHashAlgs: []string{"sha256"},
+			DomainSeparator: "RVA_NODE:v1",
+			MinDepth:        2,
+			MaxDepth:        64,
+		},
+		Cutover: policy.CutoverRules{RequirePrevAnchor: true, StrictMonotonicEpoch: true},
+	}
+	policyPath := filepath.Join(t.TempDir(), "rotation_policy.json")
+	if err := policy.WritePolicy(policyPath, pol); err != nil {
+		t.Fatalf("WritePolicy failed: %v", err)
+	}
+	args := []string{"--ledger", path, "--seed", testSeedHex, "--policy", policyPath}
+
+	// Two registers make a one-level tree, below min_depth
+	fixture.Register(t, "a", "b")
+	var stdout, stderr bytes.Buffer
+	if code := run(args, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), ledger.ErrPolicyViolation.Error()) {
+		t.Errorf("stderr does not name the cause: %s", stderr.String())
+	}
+
+	fixture.Register(t, "c")
+	stdout.Reset()
+	stderr.Reset()
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr.String())
+	}
+	var m ledger.Manifest
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		t.Fatalf("stdout is not a manifest: %v", err)
+	}
+	if m.PolicyHash == "" || m.PolicyHash != ledger.SealPolicyHash() {
+		t.Errorf("policy_hash = %q, want %q", m.PolicyHash, ledger.SealPolicyHash())
+	}
+}
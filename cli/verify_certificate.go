@@ -1,25 +1,36 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
 )
 
 // FORGED-LRO — Offline Verification CLI
-// Status: Scaffold only (no logic)
+// Status: Proof envelope verification implemented; certificate checks TBD
 // Canon-safe: Interface defined, behavior TBD
 
 func main() {
 	certPath := flag.String("cert", "", "Path to RVA certificate JSON file")
 	manifestPath := flag.String("manifest", "", "Path to epoch manifest JSON file")
-	verbose := flag.Bool("v", false, "Verbose output")
+	proofPath := flag.String("proof", "", "Path to a versioned Merkle proof envelope JSON file")
+	verbose := flag.Bool("v", false, "Verbose output: explain each verification stage")
 
 	flag.Parse()
 
+	if *proofPath != "" {
+		os.Exit(verifyProofFile(*proofPath, *manifestPath, *verbose, os.Stdout, os.Stderr))
+	}
+
 	if *certPath == "" || *manifestPath == "" {
 		fmt.Println("Usage:")
 		fmt.Println("  verify_certificate --cert certificate.json --manifest epoch_manifest.json")
+		fmt.Println("  verify_certificate --proof proof.json [--manifest epoch_manifest.json] [-v]")
 		os.Exit(1)
 	}
 
@@ -33,3 +44,117 @@ func main() {
 
 	os.Exit(0)
 }
+
+// verifyProofFile checks a proof envelope against the root it carries and, if
+// manifestPath is set, that root against the signed epoch manifest. With verbose
+// every stage is explained on stdout, including computed vs expected roots.
+func verifyProofFile(path, manifestPath string, verbose bool, stdout, stderr io.Writer) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to read proof: %v\n", err)
+		return 1
+	}
+
+	proof, err := merkle.UnmarshalProof(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid proof envelope: %v\n", err)
+		return 1
+	}
+
+	if verbose {
+		fmt.Fprintf(stdout, "canon=%s leaf=%s index=%d total_leaves=%d root=%s\n",
+			proof.Version, proof.Leaf, proof.Index, proof.TotalLeaves, proof.Root)
+	}
+
+	ok, computed, err := merkle.VerifyProofDetailed(proof.Leaf, proof.Index, proof.TotalLeaves, proof.Nodes, proof.Root)
+	if err != nil {
+		fmt.Fprintf(stderr, "proof rejected: %v\n", err)
+		return 1
+	}
+	if verbose {
+		if ok {
+			fmt.Fprintf(stdout, "inclusion proof: OK (reconstructed root %s)\n", short(computed))
+		} else {
+			fmt.Fprintf(stdout, "inclusion proof: FAILED (computed root %s, expected %s)\n", computed, proof.Root)
+		}
+	}
+	if !ok {
+		fmt.Fprintln(stdout, "INVALID: leaf is not included under root")
+		return 1
+	}
+
+	if manifestPath == "" {
+		fmt.Fprintln(stdout, "VALID: leaf is included under root")
+		return 0
+	}
+
+	manifest, err := readManifest(manifestPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	if manifest.MerkleRoot != proof.Root {
+		if verbose {
+			fmt.Fprintf(stdout, "manifest root: FAILED (proof root %s, manifest root %s)\n", proof.Root, manifest.MerkleRoot)
+		}
+		fmt.Fprintln(stdout, "INVALID: proof root is not the manifest's merkle_root")
+		return 1
+	}
+	if verbose {
+		fmt.Fprintf(stdout, "manifest root: OK (epoch %d)\n", manifest.EpochID)
+	}
+
+	// Only the signed tree size stops an internal node posing as a leaf
+	if err := manifest.CheckTreeSize(proof.TotalLeaves); err != nil {
+		if verbose {
+			fmt.Fprintf(stdout, "tree size: FAILED (%v)\n", err)
+		}
+		fmt.Fprintln(stdout, "INVALID: proof total_leaves is not the manifest's leaf_count")
+		return 1
+	}
+	if verbose {
+		if manifest.SigVersion == ledger.SigVersionRoot {
+			fmt.Fprintln(stdout, "tree size: NOT BOUND (manifest signs merkle_root only)")
+		} else {
+			fmt.Fprintf(stdout, "tree size: OK (%d leaves)\n", manifest.LeafCount)
+		}
+	}
+
+	sigOK, err := ledger.VerifyManifestSignature(manifest)
+	if verbose {
+		if sigOK {
+			fmt.Fprintf(stdout, "signature over root: OK (pubkey %s)\n", short(manifest.PublicKey))
+		} else {
+			fmt.Fprintf(stdout, "signature over root: FAILED (pubkey %s: %v)\n", short(manifest.PublicKey), err)
+		}
+	}
+	if !sigOK {
+		fmt.Fprintln(stdout, "INVALID: manifest signature does not verify")
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "VALID: leaf is included under a signed epoch root")
+	return 0
+}
+
+// readManifest loads an epoch manifest JSON file.
+func readManifest(path string) (ledger.Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ledger.Manifest{}, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var m ledger.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return ledger.Manifest{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return m, nil
+}
+
+// short abbreviates a hex value for display.
+func short(h string) string {
+	if len(h) <= 12 {
+		return h
+	}
+	return h[:12] + "…"
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+const testSeedHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+// writeFixtures writes a proof for the second of three leaves and a manifest
+// whose signature covers signedRoot, returning both paths and the real root.
+func writeFixtures(t *testing.T, signedRoot func(root string) string) (proofPath, manifestPath, root string) {
+	t.Helper()
+	leaves := []string{hash.Sha256Hex([]byte("a")), hash.Sha256Hex([]byte("b")), hash.Sha256Hex([]byte("c"))}
+	proof, err := merkle.NewProof(leaves, 1)
+	if err != nil {
+		t.Fatalf("NewProof error: %v", err)
+	}
+	proofJSON, err := merkle.MarshalProof(proof)
+	if err != nil {
+		t.Fatalf("MarshalProof error: %v", err)
+	}
+
+	sig, pub, err := sign.SignHashHex(signedRoot(proof.Root), testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex error: %v", err)
+	}
+	manifestJSON, err := json.Marshal(ledger.Manifest{
+		MerkleRoot: proof.Root,
+		Signature:  sig,
+		PublicKey:  pub,
+		Timestamp:  "2026-01-01T00:00:00Z",
+		EpochID:    3,
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+
+	dir := t.TempDir()
+	proofPath = filepath.Join(dir, "proof.json")
+	manifestPath = filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(proofPath, proofJSON, 0644); err != nil {
+		t.Fatalf("failed to write proof: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, manifestJSON, 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+	return proofPath, manifestPath, proof.Root
+}
+
+func TestVerifyProofFile_VerboseSuccess(t *testing.T) {
+	proofPath, manifestPath, root := writeFixtures(t, func(root string) string { return root })
+
+	var stdout, stderr bytes.Buffer
+	if code := verifyProofFile(proofPath, manifestPath, true, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d, stdout:\n%s\nstderr:\n%s", code, stdout.String(), stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"inclusion proof: OK (reconstructed root " + short(root) + ")",
+		"manifest root: OK (epoch 3)",
+		"signature over root: OK (pubkey ",
+		"VALID: leaf is included under a signed epoch root",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestVerifyProofFile_VerboseSignatureFailure(t *testing.T) {
+	// The manifest claims the real root but its signature covers another one
+	other := hash.Sha256Hex([]byte("other"))
+	proofPath, manifestPath, root := writeFixtures(t, func(string) string { return other })
+
+	var stdout, stderr bytes.Buffer
+	if code := verifyProofFile(proofPath, manifestPath, true, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1; stdout:\n%s", code, stdout.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"inclusion proof: OK (reconstructed root " + short(root) + ")",
+		"signature over root: FAILED (pubkey ",
+		"INVALID: manifest signature does not verify",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestVerifyProofFile_ManifestRootMismatch(t *testing.T) {
+	proofPath, _, root := writeFixtures(t, func(root string) string { return root })
+
+	other := hash.Sha256Hex([]byte("other"))
+	sig, pub, err := sign.SignHashHex(other, testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex error: %v", err)
+	}
+	data, _ := json.Marshal(ledger.Manifest{MerkleRoot: other, Signature: sig, PublicKey: pub})
+	manifestPath := filepath.Join(t.TempDir(), "other.json")
+	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := verifyProofFile(proofPath, manifestPath, true, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1", code)
+	}
+	want := "manifest root: FAILED (proof root " + root + ", manifest root " + other + ")"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("missing %q in output:\n%s", want, stdout.String())
+	}
+}
+
+func TestVerifyProofFile_QuietWithoutManifest(t *testing.T) {
+	proofPath, _, _ := writeFixtures(t, func(root string) string { return root })
+
+	var stdout, stderr bytes.Buffer
+	if code := verifyProofFile(proofPath, "", false, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code %d", code)
+	}
+	if got := stdout.String(); got != "VALID: leaf is included under root\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestVerifyProofFile_InternalNodeForgery(t *testing.T) {
+	leaves := []string{hash.Sha256Hex([]byte("a")), hash.Sha256Hex([]byte("b")), hash.Sha256Hex([]byte("c")), hash.Sha256Hex([]byte("d"))}
+	root, err := merkle.BuildRoot(leaves)
+	if err != nil {
+		t.Fatalf("BuildRoot error: %v", err)
+	}
+	m, err := ledger.Manifest{MerkleRoot: root, Timestamp: "2026-01-01T00:00:00Z", Canon: "v1.0", LeafCount: len(leaves)}.Sign(testSeedHex)
+	if err != nil {
+		t.Fatalf("Sign error: %v", err)
+	}
+
+	// The node over a, b posing as leaf 0 of a 2-leaf tree with the same root
+	left, _ := merkle.HashPair(leaves[0], leaves[1])
+	right, _ := merkle.HashPair(leaves[2], leaves[3])
+	forged, err := merkle.NewProof([]string{left, right}, 0)
+	if err != nil {
+		t.Fatalf("NewProof error: %v", err)
+	}
+
+	dir := t.TempDir()
+	proofPath := filepath.Join(dir, "proof.json")
+	manifestPath := filepath.Join(dir, "manifest.json")
+	proofJSON, _ := merkle.MarshalProof(forged)
+	manifestJSON, _ := json.Marshal(m)
+	if err := os.WriteFile(proofPath, proofJSON, 0644); err != nil {
+		t.Fatalf("failed to write proof: %v", err)
+	}
+	if err := os.WriteFile(manifestPath, manifestJSON, 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := verifyProofFile(proofPath, manifestPath, true, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code %d, want 1:\n%s", code, stdout.String())
+	}
+	if want := "INVALID: proof total_leaves is not the manifest's leaf_count"; !strings.Contains(stdout.String(), want) {
+		t.Errorf("missing %q in output:\n%s", want, stdout.String())
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+)
+
+// Formatos de log soportados por --log-format
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// auditEvent es una línea de auditoría estructurada (modo --log-format=json).
+type auditEvent struct {
+	TS              string       `json:"ts"`
+	Level           string       `json:"level"`
+	Event           string       `json:"event"`
+	Detail          string       `json:"detail,omitempty"`
+	Issuer          *issuerField `json:"issuer,omitempty"`
+	EpochConfig     *epochField  `json:"epoch_config,omitempty"`
+	DomainSeparator string       `json:"domain_separator,omitempty"`
+}
+
+type issuerField struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+type epochField struct {
+	IntervalSeconds int    `json:"interval_seconds"`
+	IDFormat        string `json:"id_format"`
+}
+
+// auditLogger emite cada paso de la auditoría en texto humano o en JSON Lines.
+type auditLogger struct {
+	format string
+	text   *log.Logger
+	enc    *json.Encoder
+}
+
+// newAuditLogger escribe la auditoría en w; stdout queda reservado al veredicto.
+func newAuditLogger(format string, w io.Writer) (*auditLogger, error) {
+	switch format {
+	case logFormatText:
+		return &auditLogger{format: format, text: log.New(w, "", log.LstdFlags)}, nil
+	case logFormatJSON:
+		return &auditLogger{format: format, enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown --log-format %q (expected text or json)", format)
+	}
+}
+
+// Info registra un paso normal del motor de gobernanza.
+func (a *auditLogger) Info(event, detail string) {
+	if a.format == logFormatJSON {
+		a.emit(auditEvent{Level: "info", Event: event, Detail: detail})
+		return
+	}
+	a.text.Printf("[RVA-AUDIT] %s", detail)
+}
+
+// Fail registra una violación o error fatal.
+func (a *auditLogger) Fail(event, detail string) {
+	if a.format == logFormatJSON {
+		a.emit(auditEvent{Level: "error", Event: event, Detail: detail})
+		return
+	}
+	a.text.Printf("[AUDIT_FAIL] %s", detail)
+}
+
+// Verdict registra el veredicto final como un único evento.
+func (a *auditLogger) Verdict(pol *policy.RotationPolicy) {
+	if a.format == logFormatJSON {
+		a.emit(auditEvent{
+			Level:  "info",
+			Event:  "verdict",
+			Detail: "ALLOW_ROTATION",
+			Issuer: &issuerField{Name: pol.Issuer.Name, ID: pol.Issuer.ID},
+			EpochConfig: &epochField{
+				IntervalSeconds: pol.Epochs.IntervalSeconds,
+				IDFormat:        pol.Epochs.IDFormat,
+			},
+			DomainSeparator: pol.Constraints.DomainSeparator,
+		})
+		return
+	}
+
+	a.text.Println("--------------------------------------------------")
+	a.text.Printf("VERDICT: [ALLOW_ROTATION]")
+	a.text.Printf("ISSUER: %s (%s)", pol.Issuer.Name, pol.Issuer.ID)
+	a.text.Printf("EPOCH_CONFIG: Interval %ds | Format: %s",
+		pol.Epochs.IntervalSeconds,
+		pol.Epochs.IDFormat,
+	)
+	a.text.Printf("SECURITY: Domain Separator [%s] is ACTIVE", pol.Constraints.DomainSeparator)
+	a.text.Println("--------------------------------------------------")
+}
+
+func (a *auditLogger) emit(ev auditEvent) {
+	ev.TS = time.Now().UTC().Format(time.RFC3339Nano)
+	_ = a.enc.Encode(ev)
+}
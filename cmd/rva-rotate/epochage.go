@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+)
+
+// defaultMaxEpochAgeFactor: un sello es tardío cuando su antigüedad supera
+// dos intervalos de la política.
+const defaultMaxEpochAgeFactor = 2.0
+
+// errEpochOverdue indica que el sellador lleva parado más de lo que permite la política.
+var errEpochOverdue = errors.New("epoch overdue")
+
+// checkEpochAge compara la antigüedad del último sello del ledger con
+// IntervalSeconds * factor. Un ledger sin sellos no se considera atrasado.
+// Devuelve errEpochOverdue (envuelto) si el último sello es demasiado viejo.
+func checkEpochAge(pol *policy.RotationPolicy, ledgerPath string, factor float64) (string, error) {
+	ledger.SetLedgerPath(ledgerPath)
+
+	seal, found, err := ledger.LastSeal()
+	if err != nil {
+		return "", fmt.Errorf("cannot read ledger %s: %w", ledgerPath, err)
+	}
+	if !found {
+		return fmt.Sprintf("Ledger %s has no seals yet; epoch age not checked", ledgerPath), nil
+	}
+
+	sealedAt, err := ledger.ParseCanonTimestamp(seal.Manifest.Timestamp)
+	if err != nil {
+		return "", fmt.Errorf("last seal has invalid timestamp %q: %w", seal.Manifest.Timestamp, err)
+	}
+
+	age := time.Since(sealedAt)
+	limit := time.Duration(float64(pol.Epochs.IntervalSeconds) * factor * float64(time.Second))
+	if age > limit {
+		return "", fmt.Errorf("%w: last seal (epoch %d at %s) is %s old, limit %s (interval %ds x %g)",
+			errEpochOverdue, seal.Manifest.EpochID, seal.Manifest.Timestamp, age.Round(time.Second), limit, pol.Epochs.IntervalSeconds, factor)
+	}
+	return fmt.Sprintf("Last seal (epoch %d) is %s old, within limit %s", seal.Manifest.EpochID, age.Round(time.Second), limit), nil
+}
@@ -1,47 +1,111 @@
 package main
 
 import (
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
 	"time"
 
-	// Asegúrate de que el path coincida con tu go.mod
-	"rva-core/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+)
+
+// Códigos de salida, para que un pipeline de CI pueda ramificar sin parsear logs
+const (
+	exitAllow       = 0 // ALLOW_ROTATION
+	exitError       = 1 // Uso incorrecto o fallo del propio motor
+	exitViolation   = 2 // DENY_ROTATION: la política o el ledger violan la constitución
+	exitLoadFailure = 3 // DENY_ROTATION: no se pudo cargar la política o el ledger
 )
 
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run ejecuta el motor de gobernanza y devuelve el código de salida.
+// El veredicto se escribe en stdout como una línea JSON; todo el log de
+// auditoría (texto o JSON Lines) va a stderr.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("rva-rotate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	logFormat := fs.String("log-format", logFormatText, "Audit log format: text or json")
+	verdictOut := fs.String("verdict-out", "", "Write the final verdict as JSON to this path")
+	ledgerPath := fs.String("ledger", "", "Ledger to check for an overdue epoch (default: no check)")
+	maxAgeFactor := fs.Float64("max-epoch-age-factor", defaultMaxEpochAgeFactor, "Deny when the last seal is older than interval_seconds times this factor")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if *maxAgeFactor <= 0 {
+		fmt.Fprintf(stderr, "invalid --max-epoch-age-factor %g: must be positive\n", *maxAgeFactor)
+		return exitError
+	}
+
+	audit, err := newAuditLogger(*logFormat, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
 	// 1. Configuración de ruta y entorno
 	policyPath := os.Getenv("RVA_POLICY_PATH")
 	if policyPath == "" {
 		policyPath = "config/rotation_policy.json"
 	}
 
-	log.Printf("[RVA-AUDIT] Starting governance engine at %s", time.Now().UTC().Format(time.RFC3339))
-	log.Printf("[RVA-AUDIT] Target policy: %s", policyPath)
+	audit.Info("engine_start", fmt.Sprintf("Starting governance engine at %s", time.Now().UTC().Format(time.RFC3339)))
+	audit.Info("policy_target", fmt.Sprintf("Target policy: %s", policyPath))
 
 	// 2. Carga de la Constitución (Loader)
 	pol, err := policy.LoadPolicy(policyPath)
 	if err != nil {
-		log.Fatalf("[AUDIT_FAIL] Critical failure during policy loading: %v", err)
+		audit.Fail("policy_load_failed", fmt.Sprintf("Critical failure during policy loading: %v", err))
+		return deny(audit, stdout, *verdictOut, err, exitLoadFailure)
 	}
 
 	// 3. Validación de Invariantes (Validator)
-	// Nota: Si estamos en modo DEV, podríamos saltar ciertas reglas, 
+	// Nota: Si estamos en modo DEV, podríamos saltar ciertas reglas,
 	// pero por ahora mantenemos el rigor total.
 	if err := policy.ValidateInvariants(pol); err != nil {
-		log.Fatalf("[AUDIT_FAIL] Constitution violation detected: %v", err)
-	}
-
-	// 4. Veredicto Final
-	log.Println("--------------------------------------------------")
-	log.Printf("VERDICT: [ALLOW_ROTATION]")
-	log.Printf("ISSUER: %s (%s)", pol.Issuer.Name, pol.Issuer.ID)
-	log.Printf("EPOCH_CONFIG: Interval %ds | Format: %s", 
-		pol.Epochs.IntervalSeconds, 
-		pol.Epochs.IDFormat,
-	)
-	log.Printf("SECURITY: Domain Separator [%s] is ACTIVE", pol.Constraints.DomainSeparator)
-	log.Println("--------------------------------------------------")
-	
-	log.Println("[RVA-AUDIT] Governance check completed successfully. System is irrefutable.")
+		audit.Fail("constitution_violation", fmt.Sprintf("Constitution violation detected: %v", err))
+		return deny(audit, stdout, *verdictOut, err, exitViolation)
+	}
+
+	// 4. Antigüedad de la época: un sellador parado debe hacer saltar las alertas
+	if *ledgerPath != "" {
+		detail, err := checkEpochAge(pol, *ledgerPath, *maxAgeFactor)
+		if err != nil {
+			event, code := "ledger_read_failed", exitLoadFailure
+			if errors.Is(err, errEpochOverdue) {
+				event, code = "epoch_overdue", exitViolation
+			}
+			audit.Fail(event, fmt.Sprintf("Epoch age check failed: %v", err))
+			return deny(audit, stdout, *verdictOut, err, code)
+		}
+		audit.Info("epoch_age", detail)
+	}
+
+	// 5. Veredicto Final
+	audit.Verdict(pol)
+
+	verdict, err := allowVerdict(pol)
+	if err == nil {
+		err = emitVerdict(stdout, *verdictOut, verdict)
+	}
+	if err != nil {
+		audit.Fail("verdict_write_failed", fmt.Sprintf("Cannot write verdict artifact: %v", err))
+		return exitError
+	}
+
+	audit.Info("governance_complete", "Governance check completed successfully. System is irrefutable.")
+	return exitAllow
+}
+
+// deny emite el veredicto DENY_ROTATION y devuelve code. Si el veredicto no se
+// puede escribir, el fallo se registra pero code se mantiene: la denegación manda.
+func deny(audit *auditLogger, stdout io.Writer, verdictOut string, reason error, code int) int {
+	if err := emitVerdict(stdout, verdictOut, denyVerdict(reason)); err != nil {
+		audit.Fail("verdict_write_failed", fmt.Sprintf("Cannot write verdict artifact: %v", err))
+	}
+	return code
 }
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+const validPolicyJSON = `{
+  "policy_version": "1.0",
+  "issuer": {"name": "Alpha", "id": "rva://1"},
+  "epochs": {"interval_seconds": 86400, "epoch_id_format": "numeric_ascending"},
+  "constraints": {
+    "hash_alg": "sha256",
+    "allowed_hash_algs": ["sha256"],
+    "domain_separator": "RVA_NODE:v1",
+    "min_depth": 1,
+    "max_depth": 64
+  },
+  "cutover": {"require_prev_anchor": true, "strict_monotonic_epoch": true}
+}`
+
+// writePolicy stores a policy file in a temp dir and points RVA_POLICY_PATH at it
+func writePolicy(t *testing.T, body string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rotation_policy.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+	t.Setenv("RVA_POLICY_PATH", path)
+}
+
+// parseEvents decodes JSON Lines audit output
+func parseEvents(t *testing.T, out []byte) []auditEvent {
+	t.Helper()
+	var events []auditEvent
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		var ev auditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("non-JSON audit line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestRun_JSONVerdict(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--log-format=json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+
+	var verdict *auditEvent
+	for _, ev := range parseEvents(t, stderr.Bytes()) {
+		if ev.TS == "" || ev.Level == "" || ev.Event == "" {
+			t.Errorf("event missing mandatory fields: %+v", ev)
+		}
+		if ev.Event == "verdict" {
+			ev := ev
+			verdict = &ev
+		}
+	}
+
+	if verdict == nil {
+		t.Fatalf("no verdict event in output:\n%s", stderr.String())
+	}
+	if verdict.Detail != "ALLOW_ROTATION" {
+		t.Errorf("Detail = %s, want ALLOW_ROTATION", verdict.Detail)
+	}
+	if verdict.Issuer == nil || verdict.Issuer.Name != "Alpha" || verdict.Issuer.ID != "rva://1" {
+		t.Errorf("Issuer = %+v", verdict.Issuer)
+	}
+	if verdict.EpochConfig == nil || verdict.EpochConfig.IntervalSeconds != 86400 || verdict.EpochConfig.IDFormat != "numeric_ascending" {
+		t.Errorf("EpochConfig = %+v", verdict.EpochConfig)
+	}
+	if verdict.DomainSeparator != "RVA_NODE:v1" {
+		t.Errorf("DomainSeparator = %s", verdict.DomainSeparator)
+	}
+}
+
+func TestRun_JSONFailureEvent(t *testing.T) {
+	writePolicy(t, `{"policy_version": "1.0"}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--log-format=json"}, &stdout, &stderr); code == 0 {
+		t.Fatalf("expected non-zero exit code for invalid policy")
+	}
+
+	events := parseEvents(t, stderr.Bytes())
+	last := events[len(events)-1]
+	if last.Level != "error" || last.Event != "constitution_violation" {
+		t.Errorf("last event = %+v, want error/constitution_violation", last)
+	}
+}
+
+func TestRun_TextIsDefault(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if v := stdoutVerdict(t, stdout.Bytes()); v.Verdict != "ALLOW_ROTATION" {
+		t.Errorf("stdout verdict = %+v, want ALLOW_ROTATION", v)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("VERDICT: [ALLOW_ROTATION]")) {
+		t.Errorf("text verdict missing from stderr:\n%s", stderr.String())
+	}
+}
+
+func TestRun_UnknownLogFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--log-format=xml"}, &stdout, &stderr); code == 0 {
+		t.Fatalf("expected non-zero exit code for unknown format")
+	}
+}
+
+// stdoutVerdict decodes the single verdict line run writes to stdout
+func stdoutVerdict(t *testing.T, out []byte) verdictFile {
+	t.Helper()
+	lines := bytes.Split(bytes.TrimSpace(out), []byte("\n"))
+	if len(lines) != 1 {
+		t.Fatalf("stdout should hold exactly one verdict line, got %q", out)
+	}
+	var v verdictFile
+	if err := json.Unmarshal(lines[0], &v); err != nil {
+		t.Fatalf("stdout verdict is not JSON: %v\n%s", err, out)
+	}
+	return v
+}
+
+func TestRun_ExitCodesAndStdoutVerdict(t *testing.T) {
+	violation := strings.Replace(validPolicyJSON, `"sha256"]`, `"sha256", "md5"]`, 1)
+
+	cases := []struct {
+		name    string
+		policy  string // empty: RVA_POLICY_PATH points at a missing file
+		code    int
+		verdict string
+	}{
+		{"allow", validPolicyJSON, exitAllow, "ALLOW_ROTATION"},
+		{"invariant violation", violation, exitViolation, "DENY_ROTATION"},
+		{"load failure", "", exitLoadFailure, "DENY_ROTATION"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.policy == "" {
+				t.Setenv("RVA_POLICY_PATH", filepath.Join(t.TempDir(), "missing.json"))
+			} else {
+				writePolicy(t, tc.policy)
+			}
+
+			for _, format := range []string{"text", "json"} {
+				var stdout, stderr bytes.Buffer
+				if code := run([]string{"--log-format=" + format}, &stdout, &stderr); code != tc.code {
+					t.Fatalf("%s: exit code = %d, want %d; stderr: %s", format, code, tc.code, stderr.String())
+				}
+				v := stdoutVerdict(t, stdout.Bytes())
+				if v.Verdict != tc.verdict || v.TS == "" {
+					t.Errorf("%s: stdout verdict = %+v, want %s", format, v, tc.verdict)
+				}
+				if (tc.code == exitAllow) != (v.Reason == "") {
+					t.Errorf("%s: reason = %q", format, v.Reason)
+				}
+				if stderr.Len() == 0 {
+					t.Errorf("%s: audit log missing from stderr", format)
+				}
+			}
+		})
+	}
+}
+
+// readVerdict decodes the verdict artifact written by --verdict-out
+func readVerdict(t *testing.T, path string) verdictFile {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read verdict: %v", err)
+	}
+	var v verdictFile
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("verdict is not JSON: %v\n%s", err, data)
+	}
+	if v.TS == "" {
+		t.Errorf("verdict missing ts: %s", data)
+	}
+	return v
+}
+
+func TestRun_VerdictOutAllow(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+	out := filepath.Join(t.TempDir(), "verdict.json")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--verdict-out", out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+
+	v := readVerdict(t, out)
+	if v.Verdict != "ALLOW_ROTATION" || v.Reason != "" {
+		t.Errorf("Verdict/Reason = %s/%q, want ALLOW_ROTATION with no reason", v.Verdict, v.Reason)
+	}
+	if v.Issuer == nil || v.Issuer.Name != "Alpha" || v.Issuer.ID != "rva://1" {
+		t.Errorf("Issuer = %+v", v.Issuer)
+	}
+	if v.EpochConfig == nil || v.EpochConfig.IntervalSeconds != 86400 || v.EpochConfig.IDFormat != "numeric_ascending" {
+		t.Errorf("EpochConfig = %+v", v.EpochConfig)
+	}
+	if v.DomainSeparator != "RVA_NODE:v1" {
+		t.Errorf("DomainSeparator = %s", v.DomainSeparator)
+	}
+
+	pol, err := policy.LoadPolicy(os.Getenv("RVA_POLICY_PATH"))
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	canonical, err := policy.CanonicalizePolicy(pol)
+	if err != nil {
+		t.Fatalf("CanonicalizePolicy failed: %v", err)
+	}
+	if want := hash.Sha256Hex(canonical); v.PolicyHash != want {
+		t.Errorf("PolicyHash = %s, want %s", v.PolicyHash, want)
+	}
+}
+
+func TestRun_VerdictOutDeny(t *testing.T) {
+	body := strings.Replace(validPolicyJSON, `"sha256"]`, `"sha256", "md5"]`, 1)
+	writePolicy(t, body)
+	out := filepath.Join(t.TempDir(), "verdict.json")
+
+	pol, err := policy.LoadPolicy(os.Getenv("RVA_POLICY_PATH"))
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	wantErr := policy.ValidateInvariants(pol)
+	if wantErr == nil {
+		t.Fatalf("test policy should violate an invariant")
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--verdict-out", out}, &stdout, &stderr); code == 0 {
+		t.Fatalf("expected non-zero exit code for invalid policy")
+	}
+
+	v := readVerdict(t, out)
+	if v.Verdict != "DENY_ROTATION" {
+		t.Errorf("Verdict = %s, want DENY_ROTATION", v.Verdict)
+	}
+	if v.Reason != wantErr.Error() {
+		t.Errorf("Reason = %q, want %q", v.Reason, wantErr.Error())
+	}
+	if v.Issuer != nil || v.PolicyHash != "" {
+		t.Errorf("deny verdict should not carry policy details: %+v", v)
+	}
+}
+
+// writeSealedLedger creates a ledger with one register sealed at sealedAt
+func writeSealedLedger(t *testing.T, sealedAt time.Time) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	prev := ledger.GetLedgerPath()
+	ledger.SetLedgerPath(path)
+	t.Cleanup(func() { ledger.SetLedgerPath(prev) })
+
+	if err := ledger.AppendRegister(strings.Repeat("ab", 32), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	manifest := ledger.Manifest{
+		MerkleRoot: strings.Repeat("ab", 32),
+		Signature:  strings.Repeat("cd", 64),
+		PublicKey:  strings.Repeat("ef", 32),
+		Timestamp:  sealedAt.UTC().Format(time.RFC3339Nano),
+	}
+	if err := ledger.AppendSeal(manifest); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+	return path
+}
+
+func TestRun_EpochAgeFreshSealAllows(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+	path := writeSealedLedger(t, time.Now().Add(-time.Hour))
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--log-format=json", "--ledger", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+	found := false
+	for _, ev := range parseEvents(t, stderr.Bytes()) {
+		found = found || ev.Event == "epoch_age"
+	}
+	if !found {
+		t.Errorf("no epoch_age event in output:\n%s", stderr.String())
+	}
+}
+
+func TestRun_EpochAgeOverdueDenies(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+	// Interval is one day; the default factor allows two
+	path := writeSealedLedger(t, time.Now().Add(-72*time.Hour))
+	out := filepath.Join(t.TempDir(), "verdict.json")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--log-format=json", "--ledger", path, "--verdict-out", out}, &stdout, &stderr); code != exitViolation {
+		t.Fatalf("exit code = %d, want %d for an overdue epoch", code, exitViolation)
+	}
+
+	events := parseEvents(t, stderr.Bytes())
+	if last := events[len(events)-1]; last.Level != "error" || last.Event != "epoch_overdue" {
+		t.Errorf("last event = %+v, want error/epoch_overdue", last)
+	}
+	if v := readVerdict(t, out); v.Verdict != "DENY_ROTATION" || !strings.Contains(v.Reason, "epoch overdue") {
+		t.Errorf("verdict = %+v, want DENY_ROTATION for an overdue epoch", v)
+	}
+
+	// A larger factor tolerates the same seal
+	stderr.Reset()
+	if code := run([]string{"--log-format=json", "--ledger", path, "--max-epoch-age-factor", "4"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("factor 4: exit code = %d, stderr: %s", code, stderr.String())
+	}
+}
+
+func TestRun_EpochAgeNoSeals(t *testing.T) {
+	writePolicy(t, validPolicyJSON)
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	prev := ledger.GetLedgerPath()
+	t.Cleanup(func() { ledger.SetLedgerPath(prev) })
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--ledger", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// Veredictos posibles del motor de gobernanza
+const (
+	verdictAllow = "ALLOW_ROTATION"
+	verdictDeny  = "DENY_ROTATION"
+)
+
+// verdictFile es el artefacto máquina escrito en --verdict-out.
+// En DENY solo se rellenan verdict, reason y ts.
+type verdictFile struct {
+	Verdict         string       `json:"verdict"`
+	Reason          string       `json:"reason,omitempty"`
+	Issuer          *issuerField `json:"issuer,omitempty"`
+	EpochConfig     *epochField  `json:"epoch_config,omitempty"`
+	DomainSeparator string       `json:"domain_separator,omitempty"`
+	PolicyHash      string       `json:"policy_hash,omitempty"` // SHA-256 del JSON canónico de la política
+	TS              string       `json:"ts"`
+}
+
+// allowVerdict construye el veredicto positivo a partir de la política validada.
+func allowVerdict(pol *policy.RotationPolicy) (verdictFile, error) {
+	canonical, err := policy.CanonicalizePolicy(pol)
+	if err != nil {
+		return verdictFile{}, err
+	}
+	return verdictFile{
+		Verdict: verdictAllow,
+		Issuer:  &issuerField{Name: pol.Issuer.Name, ID: pol.Issuer.ID},
+		EpochConfig: &epochField{
+			IntervalSeconds: pol.Epochs.IntervalSeconds,
+			IDFormat:        pol.Epochs.IDFormat,
+		},
+		DomainSeparator: pol.Constraints.DomainSeparator,
+		PolicyHash:      hash.Sha256Hex(canonical),
+	}, nil
+}
+
+// denyVerdict construye el veredicto negativo con el error real como motivo.
+func denyVerdict(reason error) verdictFile {
+	return verdictFile{Verdict: verdictDeny, Reason: reason.Error()}
+}
+
+// emitVerdict sella el veredicto con la hora actual, lo escribe en stdout como
+// una sola línea JSON y, si path no está vacío, también en path.
+func emitVerdict(stdout io.Writer, path string, v verdictFile) error {
+	v.TS = time.Now().UTC().Format(time.RFC3339Nano)
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := stdout.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("cannot write verdict to stdout: %w", err)
+	}
+	return writeVerdict(path, v)
+}
+
+// writeVerdict escribe el veredicto en path. Si path está vacío no hace nada.
+func writeVerdict(path string, v verdictFile) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("cannot write verdict to %s: %w", path, err)
+	}
+	return nil
+}
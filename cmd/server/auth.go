@@ -0,0 +1,26 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// requireWriteToken exige "Authorization: Bearer <writeToken>" antes de next.
+// Sin token configurado las escrituras quedan deshabilitadas (403): el servidor
+// nunca acepta escrituras anónimas. Token ausente o distinto: 401.
+func requireWriteToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if writeToken == "" {
+			writeError(w, http.StatusForbidden, "write endpoints disabled: no write token configured")
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(writeToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="forged-lro"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid write token")
+			return
+		}
+		next(w, r)
+	}
+}
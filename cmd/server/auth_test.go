@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+const testWriteToken = "test-write-token"
+
+// useWriteToken configura el token de escritura durante el test
+func useWriteToken(t *testing.T, token string) {
+	t.Helper()
+	previous := writeToken
+	writeToken = token
+	t.Cleanup(func() { writeToken = previous })
+}
+
+// postWrite hace POST a un endpoint de escritura con el token dado ("" = sin cabecera)
+func postWrite(t *testing.T, url, path, token, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url+path, bytes.NewReader([]byte(body)))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestWriteEndpoints_RequireToken(t *testing.T) {
+	fixture.TempLedger(t)
+	useSealSeed(t, testSeedHex)
+	srv := newTestServer(t)
+	body := `{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"}`
+
+	for _, path := range []string{"/register", "/register/batch", "/seal"} {
+		useWriteToken(t, "")
+		if resp := postWrite(t, srv.URL, path, testWriteToken, body); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s without configured token: status = %d, want 403", path, resp.StatusCode)
+		}
+
+		useWriteToken(t, testWriteToken)
+		if resp := postWrite(t, srv.URL, path, "", body); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s without Authorization: status = %d, want 401", path, resp.StatusCode)
+		}
+		if resp := postWrite(t, srv.URL, path, "wrong", body); resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s with wrong token: status = %d, want 401", path, resp.StatusCode)
+		}
+	}
+
+	if registers, _ := ledger.ListPendingRegisters(); len(registers) != 0 {
+		t.Fatalf("rejected requests wrote %d registers", len(registers))
+	}
+}
+
+func TestSeal_ServerSideManifest(t *testing.T) {
+	fixture.TempLedger(t)
+	useWriteToken(t, testWriteToken)
+	srv := newTestServer(t)
+	const objectHash = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+
+	useSealSeed(t, "")
+	if resp := postWrite(t, srv.URL, "/seal", testWriteToken, ""); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("no seed: status = %d, want 503", resp.StatusCode)
+	}
+
+	useSealSeed(t, testSeedHex)
+	if resp := postWrite(t, srv.URL, "/seal", testWriteToken, ""); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("nothing pending: status = %d, want 409", resp.StatusCode)
+	}
+
+	if resp := postWrite(t, srv.URL, "/register", testWriteToken, `{"object_hash_hex":"`+objectHash+`"}`); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register: status = %d, want 201", resp.StatusCode)
+	}
+	// Un manifiesto enviado por el cliente se ignora: la raíz la calcula el servidor
+	forged := `{"merkle_root":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}`
+	resp := postWrite(t, srv.URL, "/seal", testWriteToken, forged)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("seal: status = %d, want 201", resp.StatusCode)
+	}
+	var m ledger.Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	if m.MerkleRoot != objectHash {
+		t.Errorf("merkle_root = %s, want the single pending register %s", m.MerkleRoot, objectHash)
+	}
+	if ok, err := ledger.VerifyManifestSignature(m); !ok {
+		t.Errorf("manifest signature does not verify: %v", err)
+	}
+	if seal, found, err := ledger.LastSeal(); err != nil || !found || seal.Manifest != m {
+		t.Errorf("stored seal = %+v (found=%v, err=%v), want the returned manifest", seal, found, err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// checkpointResponse es la respuesta de GET /checkpoint. signature es la firma
+// Ed25519 del servidor sobre ledger.CheckpointSigningDigest, que cubre
+// checkpoint_root, epoch_count y timestamp.
+type checkpointResponse struct {
+	CheckpointRoot string `json:"checkpoint_root"`
+	EpochCount     int    `json:"epoch_count"`
+	Timestamp      string `json:"timestamp"`
+	Signature      string `json:"signature"`
+	PublicKey      string `json:"public_key"`
+}
+
+// ledgerStamp identifica el estado del archivo del ledger: cualquier append
+// (de este proceso o de otro, como cli/seal_epoch + AppendSeal) lo cambia.
+type ledgerStamp struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// statLedger devuelve el ledgerStamp del ledger actual; un archivo que aún no
+// existe tiene tamaño cero.
+func statLedger() (ledgerStamp, error) {
+	stamp := ledgerStamp{path: ledger.GetLedgerPath()}
+	info, err := os.Stat(stamp.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return stamp, nil
+	}
+	if err != nil {
+		return stamp, err
+	}
+	stamp.size, stamp.modTime = info.Size(), info.ModTime()
+	return stamp, nil
+}
+
+// checkpointCache guarda el último checkpoint firmado junto con el estado del
+// archivo que lo produjo y el último sello que cubre. Mientras el archivo no
+// cambie se sirve tal cual; si cambió pero los sellos son los mismos (solo
+// llegaron registros) se conserva la firma.
+var checkpointCache struct {
+	sync.Mutex
+	stamp    ledgerStamp
+	lastRoot string
+	current  *checkpointResponse
+}
+
+// handleCheckpoint devuelve la raíz de raíces sobre todos los epochs sellados
+// del ledger actual, firmada con la semilla del servidor. Se recalcula solo
+// cuando cambian los sellos del archivo, sin importar qué proceso selló. Los
+// clientes verifican un epoch contra ella con una prueba de checkpoint
+// (ledger.NewCheckpoint). 404 si nada fue sellado, 503 sin semilla de firma configurada.
+func handleCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if sealSeedHex == "" {
+		writeError(w, http.StatusServiceUnavailable, "checkpoint signing key not configured")
+		return
+	}
+
+	// El estado se toma antes de leer los sellos: si otro sello llega en medio,
+	// el próximo request ve otro estado y vuelve a leer
+	stamp, err := statLedger()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	checkpointCache.Lock()
+	cached, lastRoot := checkpointCache.current, checkpointCache.lastRoot
+	if checkpointCache.stamp.path != stamp.path {
+		cached = nil
+	}
+	fresh := cached != nil && checkpointCache.stamp == stamp
+	checkpointCache.Unlock()
+	if fresh {
+		writeJSON(w, http.StatusOK, cached)
+		return
+	}
+
+	seals, err := ledger.ListSeals()
+	if err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+	if len(seals) == 0 {
+		writeError(w, http.StatusNotFound, "nothing sealed yet")
+		return
+	}
+	last := seals[len(seals)-1].Manifest.MerkleRoot
+
+	resp := cached
+	if resp == nil || resp.EpochCount != len(seals) || lastRoot != last {
+		if resp, err = signCheckpoint(seals); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	checkpointCache.Lock()
+	checkpointCache.stamp, checkpointCache.lastRoot, checkpointCache.current = stamp, last, resp
+	checkpointCache.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// signCheckpoint construye el checkpoint sobre seals y lo firma con sealSeedHex.
+func signCheckpoint(seals []ledger.SealEntry) (*checkpointResponse, error) {
+	cp, err := ledger.NewCheckpoint(seals)
+	if err != nil {
+		return nil, err
+	}
+	resp := &checkpointResponse{
+		CheckpointRoot: cp.TopRoot,
+		EpochCount:     len(seals),
+		Timestamp:      ledger.NormalizeTimestamp(time.Now()),
+	}
+	digest, err := ledger.CheckpointSigningDigest(resp.CheckpointRoot, resp.EpochCount, resp.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Signature, resp.PublicKey, err = sign.SignHashHex(digest, sealSeedHex); err != nil {
+		return nil, errors.New("checkpoint signing failed")
+	}
+	return resp, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// useSealSeed configura la semilla de firma del servidor durante el test
+func useSealSeed(t *testing.T, seedHex string) {
+	t.Helper()
+	previous := sealSeedHex
+	sealSeedHex = seedHex
+	t.Cleanup(func() { sealSeedHex = previous })
+}
+
+// verifyCheckpoint comprueba la firma del checkpoint sobre su digest canónico
+func verifyCheckpoint(t *testing.T, cp checkpointResponse) {
+	t.Helper()
+	digest, err := ledger.CheckpointSigningDigest(cp.CheckpointRoot, cp.EpochCount, cp.Timestamp)
+	if err != nil {
+		t.Fatalf("CheckpointSigningDigest failed: %v", err)
+	}
+	if ok, err := sign.VerifyHashHex(digest, cp.Signature, cp.PublicKey); !ok {
+		t.Fatalf("checkpoint signature does not verify: %v", err)
+	}
+}
+
+// getCheckpoint hace GET /checkpoint y decodifica la respuesta
+func getCheckpoint(t *testing.T, url string) (int, checkpointResponse) {
+	t.Helper()
+	resp, err := http.Get(url + "/checkpoint")
+	if err != nil {
+		t.Fatalf("GET /checkpoint failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out checkpointResponse
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestCheckpoint_SignedAndRefreshedOnSeal(t *testing.T) {
+	fixture.TempLedger(t)
+	useSealSeed(t, testSeedHex)
+	srv := newTestServer(t)
+	first := sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+
+	status, cp := getCheckpoint(t, srv.URL)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if cp.EpochCount != 1 || cp.CheckpointRoot != first.MerkleRoot {
+		t.Fatalf("checkpoint = %+v, want 1 epoch with root %s", cp, first.MerkleRoot)
+	}
+	verifyCheckpoint(t, cp)
+
+	// La firma cubre epoch_count y timestamp, no solo la raíz
+	for _, forged := range []checkpointResponse{
+		{CheckpointRoot: cp.CheckpointRoot, EpochCount: 2, Timestamp: cp.Timestamp},
+		{CheckpointRoot: cp.CheckpointRoot, EpochCount: 1, Timestamp: "2030-01-01T00:00:00Z"},
+	} {
+		digest, _ := ledger.CheckpointSigningDigest(forged.CheckpointRoot, forged.EpochCount, forged.Timestamp)
+		if ok, _ := sign.VerifyHashHex(digest, cp.Signature, cp.PublicKey); ok {
+			t.Errorf("signature verifies for forged %+v", forged)
+		}
+	}
+
+	// Sin sellos nuevos se sirve el mismo checkpoint cacheado, aunque lleguen registros
+	if err := ledger.AppendRegister("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if _, again := getCheckpoint(t, srv.URL); again != cp {
+		t.Fatalf("checkpoint recomputed without a new seal: %+v vs %+v", again, cp)
+	}
+
+	second := sealEpoch(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+	status, next := getCheckpoint(t, srv.URL)
+	if status != http.StatusOK {
+		t.Fatalf("status after seal = %d, want 200", status)
+	}
+	want, err := merkle.BuildRoot([]string{first.MerkleRoot, second.MerkleRoot})
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	if next.EpochCount != 2 || next.CheckpointRoot != want {
+		t.Fatalf("checkpoint after seal = %+v, want 2 epochs with root %s", next, want)
+	}
+	verifyCheckpoint(t, next)
+}
+
+func TestCheckpoint_NothingSealedOrNoKey(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	useSealSeed(t, "")
+	if status, _ := getCheckpoint(t, srv.URL); status != http.StatusServiceUnavailable {
+		t.Errorf("no seed: status = %d, want 503", status)
+	}
+
+	useSealSeed(t, testSeedHex)
+	if status, _ := getCheckpoint(t, srv.URL); status != http.StatusNotFound {
+		t.Errorf("nothing sealed: status = %d, want 404", status)
+	}
+}
+
+func TestCheckpoint_RefreshedOnSealFromAnotherProcess(t *testing.T) {
+	path := fixture.TempLedger(t)
+	useSealSeed(t, testSeedHex)
+	srv := newTestServer(t)
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	if status, cp := getCheckpoint(t, srv.URL); status != http.StatusOK || cp.EpochCount != 1 {
+		t.Fatalf("checkpoint = %d %+v, want 1 epoch", status, cp)
+	}
+
+	// Otro proceso (p. ej. cli/seal_epoch) escribe un sello directo al archivo
+	if err := ledger.AppendRegister("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	m, _, err := ledger.PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+	line, err := json.Marshal(ledger.SealEntry{Type: "seal", Manifest: m})
+	if err != nil {
+		t.Fatalf("failed to encode seal: %v", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		t.Fatalf("failed to append seal: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("failed to close ledger: %v", err)
+	}
+
+	status, cp := getCheckpoint(t, srv.URL)
+	if status != http.StatusOK || cp.EpochCount != 2 {
+		t.Fatalf("checkpoint after external seal = %d %+v, want 2 epochs", status, cp)
+	}
+	verifyCheckpoint(t, cp)
+}
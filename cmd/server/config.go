@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+// loadServerConfig resuelve la configuración: archivo RVA_CONFIG si existe,
+// si no variables de entorno (PORT, RVA_LEDGER_PATH, RVA_SEAL_SEED, RVA_WRITE_TOKEN) y valores por defecto.
+func loadServerConfig() (*config.RuntimeConfig, error) {
+	if path := os.Getenv("RVA_CONFIG"); path != "" {
+		cfg, err := config.LoadRuntimeConfig(path)
+		if err == nil {
+			applyDefaults(cfg)
+			return cfg, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		log.Printf("config file %s not found, falling back to environment", path)
+	}
+
+	cfg := &config.RuntimeConfig{
+		LedgerPath:  os.Getenv("RVA_LEDGER_PATH"),
+		SealSeedHex: os.Getenv("RVA_SEAL_SEED"),
+		WriteToken:  os.Getenv("RVA_WRITE_TOKEN"),
+	}
+	applyDefaults(cfg)
+	return cfg, nil
+}
+
+// applyDefaults completa los campos vacíos (puerto con fallback a 8080).
+func applyDefaults(cfg *config.RuntimeConfig) {
+	if cfg.ListenAddr == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = "8080"
+		}
+		cfg.ListenAddr = "0.0.0.0:" + port
+	}
+	if cfg.LedgerPath == "" {
+		cfg.LedgerPath = ledger.GetLedgerPath()
+	}
+}
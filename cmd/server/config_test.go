@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadServerConfig_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	body := `{"ledger_path":"/tmp/custom.jsonl","listen_addr":"127.0.0.1:9999","seal_seed_hex":"` + testSeedHex + `"}`
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("RVA_CONFIG", path)
+
+	cfg, err := loadServerConfig()
+	if err != nil {
+		t.Fatalf("loadServerConfig failed: %v", err)
+	}
+	if cfg.LedgerPath != "/tmp/custom.jsonl" || cfg.ListenAddr != "127.0.0.1:9999" || cfg.SealSeedHex != testSeedHex {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoadServerConfig_MissingFileFallsBackToEnv(t *testing.T) {
+	t.Setenv("RVA_CONFIG", filepath.Join(t.TempDir(), "absent.json"))
+	t.Setenv("RVA_LEDGER_PATH", "/tmp/env.jsonl")
+	t.Setenv("PORT", "7070")
+
+	cfg, err := loadServerConfig()
+	if err != nil {
+		t.Fatalf("loadServerConfig failed: %v", err)
+	}
+	if cfg.LedgerPath != "/tmp/env.jsonl" {
+		t.Errorf("LedgerPath = %s, want /tmp/env.jsonl", cfg.LedgerPath)
+	}
+	if cfg.ListenAddr != "0.0.0.0:7070" {
+		t.Errorf("ListenAddr = %s, want 0.0.0.0:7070", cfg.ListenAddr)
+	}
+}
+
+func TestLoadServerConfig_BadSeedIsFatal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := os.WriteFile(path, []byte(`{"seal_seed_hex":"zz"}`), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("RVA_CONFIG", path)
+
+	if _, err := loadServerConfig(); err == nil {
+		t.Fatalf("expected error for invalid seed")
+	}
+}
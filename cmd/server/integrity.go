@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// integrityTimeout acota la auditoría completa: un ledger enorme no debe
+// retener el lock de lectura indefinidamente.
+const integrityTimeout = 30 * time.Second
+
+// handleIntegrity audita todo el ledger y devuelve el IntegrityReport con el
+// resultado por epoch. 200 aunque la integridad falle (valid=false en el cuerpo),
+// 503 si la auditoría excede integrityTimeout, 500 ante errores de E/S.
+func handleIntegrity(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), integrityTimeout)
+	defer cancel()
+
+	report, err := ledger.CheckIntegrityCtx(ctx)
+	if errors.Is(err, context.DeadlineExceeded) {
+		writeError(w, http.StatusServiceUnavailable, "integrity audit exceeded "+integrityTimeout.String())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
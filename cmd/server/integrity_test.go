@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// getIntegrity fetches /integrity and decodes the report
+func getIntegrity(t *testing.T, url string) (int, ledger.IntegrityReport) {
+	t.Helper()
+	resp, err := http.Get(url + "/integrity")
+	if err != nil {
+		t.Fatalf("GET /integrity failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var report ledger.IntegrityReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return resp.StatusCode, report
+}
+
+func TestIntegrity_CleanLedger(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	sealEpoch(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+
+	status, report := getIntegrity(t, srv.URL)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if !report.Valid || len(report.Epochs) != 2 {
+		t.Fatalf("expected valid report with 2 epochs, got %+v", report)
+	}
+	for i, e := range report.Epochs {
+		if e.EpochID != i || !e.RootOK || !e.SignatureOK || !e.AnchorOK || e.RegisterCount != 1 {
+			t.Errorf("Epochs[%d] = %+v", i, e)
+		}
+	}
+}
+
+func TestIntegrity_TamperedSeal(t *testing.T) {
+	path := fixture.TempLedger(t)
+	srv := newTestServer(t)
+	first := sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	sealEpoch(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+
+	// Rewrite only the first seal's root; the second seal still anchors to the original
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	forged := strings.Repeat("0", 64)
+	data = []byte(strings.Replace(string(data), `"merkle_root":"`+first.MerkleRoot+`"`, `"merkle_root":"`+forged+`"`, 1))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+
+	status, report := getIntegrity(t, srv.URL)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want 200 even for a failing ledger", status)
+	}
+	if report.Valid || len(report.Violations) == 0 {
+		t.Fatalf("expected invalid report, got %+v", report)
+	}
+	if len(report.Epochs) != 2 {
+		t.Fatalf("Epochs = %+v, want 2 entries", report.Epochs)
+	}
+	if e := report.Epochs[0]; e.RootOK || e.SignatureOK || !e.AnchorOK {
+		t.Errorf("Epochs[0] = %+v, want root and signature failures", e)
+	}
+	if e := report.Epochs[1]; !e.RootOK || !e.SignatureOK || e.AnchorOK {
+		t.Errorf("Epochs[1] = %+v, want anchor failure only", e)
+	}
+}
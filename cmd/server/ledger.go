@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// registerRequest es el cuerpo de POST /register.
+type registerRequest struct {
+	ObjectHashHex string `json:"object_hash_hex"`
+	CanonicalJSON string `json:"canonical_json,omitempty"`
+}
+
+// handleRegister agrega un registro al ledger (201) o rechaza el hash (400).
+func handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed payload: "+err.Error())
+		return
+	}
+
+	var canonical []byte
+	if req.CanonicalJSON != "" {
+		canonical = []byte(req.CanonicalJSON)
+	}
+
+	if err := ledger.AppendRegister(req.ObjectHashHex, canonical); err != nil {
+		metrics.appendErrors.Add(1)
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+
+	metrics.registers.Add(1)
+	writeJSON(w, http.StatusCreated, map[string]string{"status": "registered"})
+}
+
+// maxBatchBytes acota el cuerpo de POST /register/batch.
+const maxBatchBytes = 16 << 20
+
+// handleRegisterBatch agrega un lote de registros bajo un único lock: todo o nada.
+// 201 con la cantidad escrita, 400 si el lote está vacío o algún hash es inválido
+// (sin escribir ninguno).
+func handleRegisterBatch(w http.ResponseWriter, r *http.Request) {
+	var reqs []registerRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBytes)).Decode(&reqs); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed payload: "+err.Error())
+		return
+	}
+	if len(reqs) == 0 {
+		writeError(w, http.StatusBadRequest, "empty batch")
+		return
+	}
+
+	items := make([]ledger.BatchRegister, len(reqs))
+	for i, req := range reqs {
+		items[i].ObjectHashHex = req.ObjectHashHex
+		if req.CanonicalJSON != "" {
+			items[i].CanonicalJSON = []byte(req.CanonicalJSON)
+		}
+	}
+
+	written, err := ledger.AppendRegisterBatch(items)
+	metrics.registers.Add(uint64(written))
+	if err != nil {
+		metrics.appendErrors.Add(1)
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]int{"registered": written})
+}
+
+// registerStatus es la respuesta de GET /register.
+type registerStatus struct {
+	Found                bool   `json:"found"`
+	Timestamp            string `json:"timestamp"`
+	EpochID              int    `json:"epoch_id"` // Epoch del sello que lo cubre, o el que lo sellará si está pendiente
+	Sealed               bool   `json:"sealed"`
+	CanonicalJSONPresent bool   `json:"canonical_json_present"`
+}
+
+// handleRegisterLookup responde "dónde está mi objeto": el primer registro de
+// ?hash=, su epoch y si ya lo cubre un sello (FindSealCovering).
+// 400 si el hash está mal formado, 404 si nunca se registró.
+func handleRegisterLookup(w http.ResponseWriter, r *http.Request) {
+	reg, found, err := ledger.GetRegisterByHash(r.URL.Query().Get("hash"))
+	if err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "register not found")
+		return
+	}
+
+	ts, err := ledger.ParseCanonTimestamp(reg.Timestamp)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	status := registerStatus{
+		Found:                true,
+		Timestamp:            reg.Timestamp,
+		CanonicalJSONPresent: reg.CanonicalJSONB64 != "",
+	}
+
+	seal, sealed, err := ledger.FindSealCovering(ts)
+	if err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+	if sealed {
+		status.Sealed = true
+		status.EpochID = seal.Manifest.EpochID
+	} else if status.EpochID, err = ledger.NextEpochID(); err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleSeal cierra el epoch pendiente: el servidor calcula la raíz sobre los
+// registros pendientes y la firma con su semilla (ledger.SealPending), así un
+// cliente nunca elige la raíz sellada. No lleva cuerpo. 201 con el manifiesto
+// guardado, 409 si no hay registros pendientes o el árbol rompe la política
+// de sellado (loadSealPolicy), 503 sin semilla configurada.
+func handleSeal(w http.ResponseWriter, r *http.Request) {
+	if sealSeedHex == "" {
+		writeError(w, http.StatusServiceUnavailable, "seal signing key not configured")
+		return
+	}
+
+	manifest, err := ledger.SealPending(sealSeedHex)
+	if err != nil {
+		metrics.appendErrors.Add(1)
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+
+	metrics.seals.Add(1)
+	writeJSON(w, http.StatusCreated, manifest)
+}
+
+// statusForLedgerError traduce los errores centinela del ledger a códigos HTTP.
+func statusForLedgerError(err error) int {
+	switch {
+	case errors.Is(err, ledger.ErrInvalidHex), errors.Is(err, ledger.ErrInvalidTimestamp):
+		return http.StatusBadRequest
+	case errors.Is(err, ledger.ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ledger.ErrNoRegistrations), errors.Is(err, ledger.ErrPolicyViolation):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
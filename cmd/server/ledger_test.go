@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// postBatch sends body to /register/batch with the write token and decodes the JSON response
+func postBatch(t *testing.T, url string, body string) (int, map[string]interface{}) {
+	t.Helper()
+	useWriteToken(t, testWriteToken)
+	resp := postWrite(t, url, "/register/batch", testWriteToken, body)
+
+	var out map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestRegisterBatch_Valid(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	status, out := postBatch(t, srv.URL, `[
+		{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"},
+		{"object_hash_hex":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9","canonical_json":"{\"k\":\"v\"}"}
+	]`)
+	if status != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (%v)", status, out)
+	}
+	if out["registered"] != float64(2) {
+		t.Errorf("registered = %v, want 2", out["registered"])
+	}
+
+	registers, err := ledger.ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 2 {
+		t.Fatalf("expected 2 registers, got %d", len(registers))
+	}
+}
+
+func TestRegisterBatch_BadHashWritesNothing(t *testing.T) {
+	path := fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	status, out := postBatch(t, srv.URL, `[
+		{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"},
+		{"object_hash_hex":"nope"}
+	]`)
+	if status != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", status)
+	}
+	if out["error"] == nil {
+		t.Errorf("missing error message")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("ledger written despite rejected batch")
+	}
+}
+
+func TestRegisterBatch_EmptyOrMalformed(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	for _, body := range []string{`[]`, `{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"}`} {
+		if status, _ := postBatch(t, srv.URL, body); status != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, status)
+		}
+	}
+}
+
+// getRegister hace GET /register?hash= y decodifica la respuesta
+func getRegister(t *testing.T, url, objectHashHex string) (int, registerStatus) {
+	t.Helper()
+	resp, err := http.Get(url + "/register?hash=" + objectHashHex)
+	if err != nil {
+		t.Fatalf("GET /register failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out registerStatus
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestRegisterLookup_SealedAndPending(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	const sealedHash = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+	const pendingHash = "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	sealEpoch(t, sealedHash)
+	if err := ledger.AppendRegister(pendingHash, []byte(`{"a":1}`)); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	status, got := getRegister(t, srv.URL, sealedHash)
+	if status != http.StatusOK {
+		t.Fatalf("sealed: status = %d, want 200", status)
+	}
+	if !got.Found || !got.Sealed || got.EpochID != 0 || got.CanonicalJSONPresent || got.Timestamp == "" {
+		t.Errorf("sealed: got %+v, want found and sealed in epoch 0 without canonical JSON", got)
+	}
+
+	status, got = getRegister(t, srv.URL, pendingHash)
+	if status != http.StatusOK {
+		t.Fatalf("pending: status = %d, want 200", status)
+	}
+	if !got.Found || got.Sealed || got.EpochID != 1 || !got.CanonicalJSONPresent {
+		t.Errorf("pending: got %+v, want found, unsealed, epoch 1, canonical JSON present", got)
+	}
+}
+
+func TestRegisterLookup_MissingAndMalformed(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	if status, _ := getRegister(t, srv.URL, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"); status != http.StatusNotFound {
+		t.Errorf("unknown hash: status = %d, want 404", status)
+	}
+	for _, bad := range []string{"xyz", "", "B94D27B9934D3E08A52E52D7DA7DABFAC484EFE37A5380EE9088F7ACE2EFCDE9"} {
+		if status, _ := getRegister(t, srv.URL, bad); status != http.StatusBadRequest {
+			t.Errorf("hash %q: status = %d, want 400", bad, status)
+		}
+	}
+}
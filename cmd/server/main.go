@@ -3,16 +3,29 @@ package main
 import (
 	"log"
 	"net/http"
-	"os"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
 )
 
 const version = "dev" // luego lo amarramos a tags/ldflags
 
+// sealSeedHex es la semilla de firma de sellos (secreta, nunca se loguea)
+var sealSeedHex string
+
+// writeToken habilita los endpoints de escritura (secreto, nunca se loguea)
+var writeToken string
+
 func main() {
-	// Puerto con fallback
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// Configuración: RVA_CONFIG o entorno (el seed nunca se loguea)
+	cfg, err := loadServerConfig()
+	if err != nil {
+		log.Fatalf("config failed: %v", err)
+	}
+	ledger.SetLedgerPath(cfg.LedgerPath)
+	sealSeedHex = cfg.SealSeedHex
+	writeToken = cfg.WriteToken
+	if err := loadSealPolicy(); err != nil {
+		log.Fatalf("seal policy failed: %v", err)
 	}
 
 	// Router mínimo (sin frameworks)
@@ -21,8 +34,8 @@ func main() {
 	// RegisterRoutes (mínimo)
 	registerRoutes(mux)
 
-	addr := "0.0.0.0:" + port
-	log.Printf("FORGED-LRO server starting on %s", addr)
+	addr := cfg.ListenAddr
+	log.Printf("FORGED-LRO server starting on %s (config %s)", addr, cfg)
 
 	// IMPORTANTE: NO goroutine, y el error no se ignora
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -30,15 +43,30 @@ func main() {
 	}
 }
 
+// registerRoutes pasa cada handler por withRequestLog (request ID, log de acceso, recover).
 func registerRoutes(mux *http.ServeMux) {
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+	handle(mux, "/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
 
-	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+	handle(mux, "GET /ready", handleReady)
+
+	handle(mux, "/version", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte(version))
 	})
+
+	handle(mux, "POST /verify", handleVerify)
+	handle(mux, "POST /register", requireWriteToken(handleRegister))
+	handle(mux, "GET /register", handleRegisterLookup)
+	handle(mux, "POST /register/batch", requireWriteToken(handleRegisterBatch))
+	handle(mux, "POST /seal", requireWriteToken(handleSeal))
+	handle(mux, "GET /metrics", handleMetrics)
+	handle(mux, "GET /policy", handlePolicy)
+	handle(mux, "GET /seal/{id}", handleGetSeal)
+	handle(mux, "GET /integrity", handleIntegrity)
+	handle(mux, "GET /proof/{hash}", handleProof)
+	handle(mux, "GET /checkpoint", handleCheckpoint)
 }
 
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// serverMetrics agrupa los contadores expuestos en /metrics (seguros entre goroutines).
+type serverMetrics struct {
+	registers    atomic.Uint64
+	seals        atomic.Uint64
+	appendErrors atomic.Uint64
+}
+
+var metrics serverMetrics
+
+// handleMetrics expone los contadores en formato de texto Prometheus, sin dependencias.
+// El gauge de registros pendientes se calcula en cada scrape.
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	pending, err := ledger.ListPendingRegisters()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var b strings.Builder
+	writeMetric(&b, "forged_lro_registers_total", "counter", "Registers appended through this server.", metrics.registers.Load())
+	writeMetric(&b, "forged_lro_seals_total", "counter", "Seals appended through this server.", metrics.seals.Load())
+	writeMetric(&b, "forged_lro_append_errors_total", "counter", "Register or seal appends that failed.", metrics.appendErrors.Load())
+	writeMetric(&b, "forged_lro_pending_registers", "gauge", "Registers awaiting the next seal.", uint64(len(pending)))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func writeMetric(b *strings.Builder, name, kind, help string, value uint64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(b, "%s %d\n", name, value)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// scrapeMetrics fetches /metrics and parses every sample line into a map
+func scrapeMetrics(t *testing.T, url string) map[string]uint64 {
+	t.Helper()
+	resp, err := http.Get(url + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	samples := map[string]uint64{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			t.Fatalf("malformed sample line %q", line)
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			t.Fatalf("non-numeric sample %q: %v", line, err)
+		}
+		samples[fields[0]] = v
+	}
+	return samples
+}
+
+func TestMetrics_CountersAfterRegister(t *testing.T) {
+	fixture.TempLedger(t)
+	useWriteToken(t, testWriteToken)
+	srv := newTestServer(t)
+
+	before := scrapeMetrics(t, srv.URL)
+	for _, name := range []string{
+		"forged_lro_registers_total",
+		"forged_lro_seals_total",
+		"forged_lro_append_errors_total",
+		"forged_lro_pending_registers",
+	} {
+		if _, ok := before[name]; !ok {
+			t.Errorf("metric %s missing", name)
+		}
+	}
+
+	body := `{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"}`
+	resp := postWrite(t, srv.URL, "/register", testWriteToken, body)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("status = %d, want 201", resp.StatusCode)
+	}
+
+	resp = postWrite(t, srv.URL, "/register", testWriteToken, `{"object_hash_hex":"nope"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+
+	after := scrapeMetrics(t, srv.URL)
+	if got := after["forged_lro_registers_total"] - before["forged_lro_registers_total"]; got != 1 {
+		t.Errorf("registers_total increased by %d, want 1", got)
+	}
+	if got := after["forged_lro_append_errors_total"] - before["forged_lro_append_errors_total"]; got != 1 {
+		t.Errorf("append_errors_total increased by %d, want 1", got)
+	}
+	if after["forged_lro_pending_registers"] != 1 {
+		t.Errorf("pending_registers = %d, want 1", after["forged_lro_pending_registers"])
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// accessLog recibe una línea estructurada (clave=valor) por request; los tests la redirigen.
+var accessLog = log.New(os.Stderr, "", log.LstdFlags)
+
+// handle registra h en el mux siempre envuelto por withRequestLog.
+func handle(mux *http.ServeMux, pattern string, h http.HandlerFunc) {
+	mux.Handle(pattern, withRequestLog(h))
+}
+
+// statusRecorder captura el código de respuesta para el log de acceso.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.status = code
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// withRequestLog asigna un X-Request-ID, registra método, ruta, status y duración,
+// y convierte un panic del handler en 500 sin tumbar el proceso.
+func withRequestLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := newRequestID()
+		w.Header().Set("X-Request-ID", id)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		defer func() {
+			if p := recover(); p != nil {
+				accessLog.Printf("level=error request_id=%s panic=%q", id, p)
+				// Si el handler ya escribió cabeceras no podemos cambiar el status
+				if !rec.wroteHeader {
+					writeError(rec, http.StatusInternalServerError, "internal error")
+				}
+			}
+			accessLog.Printf("request_id=%s method=%s path=%s status=%d duration_ms=%.3f",
+				id, r.Method, r.URL.Path, rec.status, float64(time.Since(start).Microseconds())/1000)
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// newRequestID genera 16 bytes aleatorios en hex.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
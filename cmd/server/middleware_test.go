@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureAccessLog redirige el log de acceso a un buffer durante el test
+func captureAccessLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prev := accessLog
+	accessLog = log.New(&buf, "", 0)
+	t.Cleanup(func() { accessLog = prev })
+	return &buf
+}
+
+func TestRequestLog_SetsRequestIDAndLogs(t *testing.T) {
+	logs := captureAccessLog(t)
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health failed: %v", err)
+	}
+	resp.Body.Close()
+
+	id := resp.Header.Get("X-Request-ID")
+	if len(id) != 32 {
+		t.Fatalf("X-Request-ID = %q, want 32 hex chars", id)
+	}
+
+	line := logs.String()
+	for _, want := range []string{"request_id=" + id, "method=GET", "path=/health", "status=200", "duration_ms="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("access log missing %q: %s", want, line)
+		}
+	}
+}
+
+func TestRequestLog_RecoversPanic(t *testing.T) {
+	logs := captureAccessLog(t)
+
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	handle(mux, "/boom", func(w http.ResponseWriter, r *http.Request) {
+		panic("handler exploded")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL + "/boom")
+	if err != nil {
+		t.Fatalf("GET /boom failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Request-ID") == "" {
+		t.Errorf("panicking request lost its X-Request-ID")
+	}
+	if !strings.Contains(logs.String(), "handler exploded") || !strings.Contains(logs.String(), "status=500") {
+		t.Errorf("panic not logged: %s", logs.String())
+	}
+
+	// The server keeps serving after the panic
+	resp, err = http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health after panic failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status after panic = %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// defaultPolicyPath coincide con el de rva-rotate.
+const defaultPolicyPath = "config/rotation_policy.json"
+
+// policyResponse expone la constitución vigente y su hash verificable.
+type policyResponse struct {
+	Path       string          `json:"path"`
+	PolicyHash string          `json:"policy_hash"` // SHA-256 del JSON canónico
+	Policy     json.RawMessage `json:"policy"`      // JSON canónico, byte a byte lo que se hashea
+}
+
+// handlePolicy carga la política de RVA_POLICY_PATH en cada request, valida sus
+// invariantes y devuelve su forma canónica con hash. 503 si no se puede cargar o es inválida.
+func handlePolicy(w http.ResponseWriter, r *http.Request) {
+	path := policyPath()
+	pol, err := policy.LoadPolicy(path)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if err := policy.ValidateInvariants(pol); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	canonical, err := policy.CanonicalizePolicy(pol)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policyResponse{
+		Path:       path,
+		PolicyHash: hash.Sha256Hex(canonical),
+		Policy:     canonical,
+	})
+}
+
+// policyPath devuelve RVA_POLICY_PATH, o defaultPolicyPath si no está definido.
+func policyPath() string {
+	if path := os.Getenv("RVA_POLICY_PATH"); path != "" {
+		return path
+	}
+	return defaultPolicyPath
+}
+
+// loadSealPolicy hace que POST /seal selle bajo la política de policyPath: su
+// hash va firmado en cada manifiesto y sus límites de profundidad se aplican.
+// Sin archivo se sella sin política; una política inválida es un error.
+func loadSealPolicy() error {
+	path := policyPath()
+	pol, err := policy.LoadPolicy(path)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("no policy at %s, sealing without a policy", path)
+		return ledger.SetSealPolicy(nil)
+	}
+	if err != nil {
+		return err
+	}
+	return ledger.SetSealPolicy(pol)
+}
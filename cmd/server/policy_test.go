@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+const validPolicyJSON = `{
+  "policy_version": "1.0",
+  "issuer": {"name": "Alpha", "id": "rva://1"},
+  "epochs": {"interval_seconds": 86400, "epoch_id_format": "numeric_ascending"},
+  "constraints": {
+    "hash_alg": "sha256",
+    "allowed_hash_algs": ["sha256"],
+    "domain_separator": "RVA_NODE:v1",
+    "min_depth": 1,
+    "max_depth": 64
+  },
+  "cutover": {"require_prev_anchor": true, "strict_monotonic_epoch": true}
+}`
+
+// usePolicy escribe una política temporal y apunta RVA_POLICY_PATH a ella
+func usePolicy(t *testing.T, body string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rotation_policy.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+	t.Setenv("RVA_POLICY_PATH", path)
+}
+
+func TestPolicy_Valid(t *testing.T) {
+	usePolicy(t, validPolicyJSON)
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/policy")
+	if err != nil {
+		t.Fatalf("GET /policy failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var got policyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.PolicyHash != hash.Sha256Hex(got.Policy) {
+		t.Errorf("policy_hash %s does not match returned canonical JSON", got.PolicyHash)
+	}
+	if strings.ContainsAny(string(got.Policy), "\n ") {
+		t.Errorf("policy is not canonical: %s", got.Policy)
+	}
+	if !strings.HasPrefix(string(got.Policy), `{"constraints":`) {
+		t.Errorf("canonical policy keys not sorted: %s", got.Policy)
+	}
+}
+
+func TestPolicy_Invalid(t *testing.T) {
+	usePolicy(t, strings.Replace(validPolicyJSON, `"RVA_NODE:v1"`, `"RVA_NODE:v0"`, 1))
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/policy")
+	if err != nil {
+		t.Fatalf("GET /policy failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.Contains(body["error"], "AUDIT_FAIL") || !strings.Contains(body["error"], "domain_separator") {
+		t.Errorf("error = %q, want AUDIT_FAIL domain_separator reason", body["error"])
+	}
+}
+
+func TestPolicy_Missing(t *testing.T) {
+	t.Setenv("RVA_POLICY_PATH", filepath.Join(t.TempDir(), "absent.json"))
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/policy")
+	if err != nil {
+		t.Fatalf("GET /policy failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestSeal_PolicyDepthBounds(t *testing.T) {
+	fixture.TempLedger(t)
+	useWriteToken(t, testWriteToken)
+	useSealSeed(t, testSeedHex)
+	usePolicy(t, strings.Replace(validPolicyJSON, `"min_depth": 1`, `"min_depth": 2`, 1))
+	if err := loadSealPolicy(); err != nil {
+		t.Fatalf("loadSealPolicy failed: %v", err)
+	}
+	t.Cleanup(func() { ledger.SetSealPolicy(nil) })
+	srv := newTestServer(t)
+
+	// Un solo registro es un árbol de profundidad 0: la política lo rechaza
+	body := `{"object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"}`
+	if resp := postWrite(t, srv.URL, "/register", testWriteToken, body); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("register: status = %d, want 201", resp.StatusCode)
+	}
+	if resp := postWrite(t, srv.URL, "/seal", testWriteToken, ""); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("seal: status = %d, want 409", resp.StatusCode)
+	}
+	if _, found, err := ledger.LastSeal(); err != nil || found {
+		t.Fatalf("a seal breaking the policy was written (found=%v, err=%v)", found, err)
+	}
+}
+
+func TestLoadSealPolicy_MissingAndInvalid(t *testing.T) {
+	t.Cleanup(func() { ledger.SetSealPolicy(nil) })
+
+	t.Setenv("RVA_POLICY_PATH", filepath.Join(t.TempDir(), "missing.json"))
+	if err := loadSealPolicy(); err != nil || ledger.SealPolicyHash() != "" {
+		t.Fatalf("missing policy: err=%v hash=%q, want no policy", err, ledger.SealPolicyHash())
+	}
+
+	usePolicy(t, strings.Replace(validPolicyJSON, `"sha256"`, `"md5"`, 1))
+	if err := loadSealPolicy(); err == nil {
+		t.Fatalf("expected an invalid policy to be rejected")
+	}
+}
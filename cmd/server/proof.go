@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// Cache-Control de /proof: un epoch sellado es inmutable; uno pendiente cambia con cada registro.
+const (
+	cacheSealedProof  = "public, max-age=31536000, immutable"
+	cachePendingProof = "no-store"
+)
+
+// handleProof devuelve la prueba de inclusión de {hash} en su epoch.
+// El ETag depende de object_hash y de la raíz del epoch, así que solo cambia
+// cuando cambia la raíz (nunca, una vez sellado). If-None-Match coincidente
+// responde 304 sin cuerpo. 400 si el hash está mal formado, 404 si no existe.
+//
+// Las pruebas del epoch pendiente salen del árbol que cachea
+// ledger.TreeFromLedger, que solo se reconstruye cuando llega un registro; las
+// de epochs sellados se arman con ledger.ProveRegister y el cliente las cachea.
+func handleProof(w http.ResponseWriter, r *http.Request) {
+	objectHash := r.PathValue("hash")
+	proof, err := pendingProof(objectHash)
+	if errors.Is(err, ledger.ErrRegisterNotFound) {
+		proof, err = ledger.ProveRegister(objectHash)
+	}
+	if errors.Is(err, ledger.ErrRegisterNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+
+	etag := `"` + hash.Sha256Hex([]byte(proof.ObjectHashHex+":"+proof.MerkleRoot)) + `"`
+	w.Header().Set("ETag", etag)
+	if proof.Sealed {
+		w.Header().Set("Cache-Control", cacheSealedProof)
+	} else {
+		w.Header().Set("Cache-Control", cachePendingProof)
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, proof)
+}
+
+// pendingProof arma la prueba de objectHash contra el árbol del epoch pendiente
+// (ledger.TreeFromLedger desde el último sello). ErrRegisterNotFound si el hash
+// no está entre los registros pendientes; un hash mal formado nunca lo está.
+func pendingProof(objectHash string) (ledger.RegisterProof, error) {
+	last, sealed, err := ledger.LastSeal()
+	if err != nil {
+		return ledger.RegisterProof{}, err
+	}
+	var since time.Time
+	epochID := 0
+	if sealed {
+		if since, err = ledger.ParseCanonTimestamp(last.Manifest.Timestamp); err != nil {
+			return ledger.RegisterProof{}, err
+		}
+		epochID = last.Manifest.EpochID + 1
+	}
+
+	tree, registers, err := ledger.TreeFromLedger(since)
+	if errors.Is(err, ledger.ErrNoRegistrations) {
+		return ledger.RegisterProof{}, ledger.ErrRegisterNotFound
+	}
+	if err != nil {
+		return ledger.RegisterProof{}, err
+	}
+	for i, reg := range registers {
+		if reg.ObjectHashHex != objectHash {
+			continue
+		}
+		nodes, err := tree.Proof(i)
+		if err != nil {
+			return ledger.RegisterProof{}, err
+		}
+		return ledger.RegisterProof{
+			ObjectHashHex: objectHash,
+			EpochID:       epochID,
+			LeafIndex:     i,
+			TotalLeaves:   tree.Len(),
+			Proof:         nodes,
+			MerkleRoot:    tree.Root(),
+		}, nil
+	}
+	return ledger.RegisterProof{}, ledger.ErrRegisterNotFound
+}
+
+// etagMatches aplica la comparación débil de If-None-Match (RFC 9110): lista
+// separada por comas, "*" o prefijo W/.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// getProof hace GET /proof/{hash} con un If-None-Match opcional
+func getProof(t *testing.T, url, objectHashHex, ifNoneMatch string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url+"/proof/"+objectHashHex, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /proof failed: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestProof_SealedCachedWithETag(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	const objectHash = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+	m := sealEpoch(t, objectHash)
+
+	resp := getProof(t, srv.URL, objectHash, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != cacheSealedProof {
+		t.Errorf("Cache-Control = %q, want %q", cc, cacheSealedProof)
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatalf("missing ETag")
+	}
+
+	var proof ledger.RegisterProof
+	if err := json.NewDecoder(resp.Body).Decode(&proof); err != nil {
+		t.Fatalf("failed to decode proof: %v", err)
+	}
+	if !proof.Sealed || proof.MerkleRoot != m.MerkleRoot {
+		t.Fatalf("proof = %+v, want sealed under %s", proof, m.MerkleRoot)
+	}
+	if ok, err := merkle.VerifyProof(objectHash, proof.LeafIndex, proof.TotalLeaves, proof.Proof, proof.MerkleRoot); !ok {
+		t.Fatalf("proof does not verify: %v", err)
+	}
+
+	// Same epoch root: 304 with no body, headers repeated
+	for _, inm := range []string{etag, `"other", W/` + etag} {
+		resp := getProof(t, srv.URL, objectHash, inm)
+		if resp.StatusCode != http.StatusNotModified {
+			t.Fatalf("If-None-Match %s: status = %d, want 304", inm, resp.StatusCode)
+		}
+		if resp.Header.Get("ETag") != etag || resp.Header.Get("Cache-Control") != cacheSealedProof {
+			t.Errorf("304 headers = %v", resp.Header)
+		}
+	}
+
+	if resp := getProof(t, srv.URL, objectHash, `"stale"`); resp.StatusCode != http.StatusOK {
+		t.Errorf("stale ETag: status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestProof_PendingNoStore(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	const objectHash = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+	if err := ledger.AppendRegister(objectHash, nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	resp := getProof(t, srv.URL, objectHash, "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != cachePendingProof {
+		t.Errorf("Cache-Control = %q, want %q", cc, cachePendingProof)
+	}
+	etag := resp.Header.Get("ETag")
+
+	// A new register changes the pending root, so the old ETag no longer matches
+	if err := ledger.AppendRegister("b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	resp = getProof(t, srv.URL, objectHash, etag)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("ETag") == etag {
+		t.Fatalf("after append: status = %d, ETag %s, want 200 with a new ETag", resp.StatusCode, resp.Header.Get("ETag"))
+	}
+}
+
+func TestProof_NotFoundAndMalformed(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	if resp := getProof(t, srv.URL, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown hash: status = %d, want 404", resp.StatusCode)
+	}
+	if resp := getProof(t, srv.URL, "xyz", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("malformed hash: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestProof_PendingMatchesProveRegister(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+
+	pending := []string{
+		"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		"486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+	}
+	for _, h := range pending {
+		if err := ledger.AppendRegister(h, nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	// Served from the cached epoch tree, identical to a fresh ProveRegister
+	for _, h := range pending {
+		resp := getProof(t, srv.URL, h, "")
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", h, resp.StatusCode)
+		}
+		var got ledger.RegisterProof
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to decode proof: %v", err)
+		}
+		want, err := ledger.ProveRegister(h)
+		if err != nil {
+			t.Fatalf("ProveRegister failed: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: proof = %+v, want %+v", h, got, want)
+		}
+	}
+}
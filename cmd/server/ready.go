@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// handleReady es la sonda de readiness: 200 solo si el ledger es utilizable
+// (directorio escribible, última línea legible), 503 con el motivo si no.
+// /health sigue siendo la sonda de liveness superficial.
+func handleReady(w http.ResponseWriter, r *http.Request) {
+	if err := ledger.CheckReady(); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
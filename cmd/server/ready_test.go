@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// getReady fetches /ready and decodes its JSON body
+func getReady(t *testing.T, url string) (int, map[string]string) {
+	t.Helper()
+	resp, err := http.Get(url + "/ready")
+	if err != nil {
+		t.Fatalf("GET /ready failed: %v", err)
+	}
+	defer resp.Body.Close()
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode /ready body: %v", err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestReady_HealthyLedger(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+
+	// An empty ledger is ready, and so is one with entries
+	if code, body := getReady(t, srv.URL); code != http.StatusOK {
+		t.Fatalf("empty ledger: status %d, body %v", code, body)
+	}
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	if code, body := getReady(t, srv.URL); code != http.StatusOK || body["status"] != "ready" {
+		t.Fatalf("status %d, body %v", code, body)
+	}
+}
+
+func TestReady_PermissionDeniedDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root bypasses directory permissions")
+	}
+	path := fixture.TempLedger(t)
+	srv := newTestServer(t)
+	if err := ledger.AppendRegister("a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3", nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.Chmod(dir, 0555); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(dir, 0755) })
+
+	code, body := getReady(t, srv.URL)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", code)
+	}
+	if !strings.Contains(body["error"], "not writable") {
+		t.Errorf("unexpected reason %q", body["error"])
+	}
+}
+
+func TestReady_ReadOnlyLedgerFile(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root bypasses file permissions")
+	}
+	path := fixture.TempLedger(t)
+	srv := newTestServer(t)
+	if err := ledger.AppendRegister("a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3", nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// The directory stays writable; only the file itself refuses appends
+	if err := os.Chmod(path, 0444); err != nil {
+		t.Fatalf("chmod failed: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(path, 0644) })
+
+	code, body := getReady(t, srv.URL)
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want 503", code)
+	}
+	if !strings.Contains(body["error"], "ledger "+path+" is not writable") {
+		t.Errorf("unexpected reason %q", body["error"])
+	}
+}
+
+func TestReady_MissingDirectoryAndTornTail(t *testing.T) {
+	srv := newTestServer(t)
+
+	path := fixture.TempLedger(t)
+	ledger.SetLedgerPath(filepath.Join(filepath.Dir(path), "gone", "ledger.jsonl"))
+	if code, body := getReady(t, srv.URL); code != http.StatusServiceUnavailable || !strings.Contains(body["error"], "ledger directory") {
+		t.Errorf("missing directory: status %d, body %v", code, body)
+	}
+
+	path = fixture.TempLedger(t)
+	if err := os.WriteFile(path, []byte(`{"type":"register","canon":"v1.0"`), 0644); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+	if code, body := getReady(t, srv.URL); code != http.StatusServiceUnavailable || !strings.Contains(body["error"], "final line") {
+		t.Errorf("torn tail: status %d, body %v", code, body)
+	}
+
+	// The liveness probe stays shallow
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatalf("GET /health failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/health status %d, want 200", resp.StatusCode)
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// handleGetSeal devuelve el manifiesto del epoch {id}: 400 si id no es numérico, 404 si no existe.
+func handleGetSeal(w http.ResponseWriter, r *http.Request) {
+	epochID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || epochID < 0 {
+		writeError(w, http.StatusBadRequest, "epoch id must be a non-negative integer")
+		return
+	}
+
+	seal, found, err := ledger.GetSealByEpoch(epochID)
+	if err != nil {
+		writeError(w, statusForLedgerError(err), err.Error())
+		return
+	}
+	if !found {
+		writeError(w, http.StatusNotFound, "no seal for epoch "+strconv.Itoa(epochID))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, seal.Manifest)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+)
+
+// sealEpoch registers one object and seals it, returning the stored manifest
+func sealEpoch(t *testing.T, objectHashHex string) ledger.Manifest {
+	t.Helper()
+	if err := ledger.AppendRegister(objectHashHex, nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	m, _, err := ledger.PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+	if err := ledger.AppendSeal(m); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+	return m
+}
+
+func TestGetSeal_Existing(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+	want := sealEpoch(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+
+	resp, err := http.Get(srv.URL + "/seal/1")
+	if err != nil {
+		t.Fatalf("GET /seal/1 failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+
+	var got ledger.Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode manifest: %v", err)
+	}
+	if got != want {
+		t.Errorf("manifest = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetSeal_MissingAndMalformed(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	sealEpoch(t, "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3")
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "/seal/7", want: http.StatusNotFound},
+		{path: "/seal/abc", want: http.StatusBadRequest},
+		{path: "/seal/-1", want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		resp, err := http.Get(srv.URL + tt.path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", tt.path, err)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, resp.StatusCode, tt.want)
+		}
+		if body["error"] == "" {
+			t.Errorf("GET %s: missing error message", tt.path)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// verifyRequest es el certificado completo que envía un cliente ligero: la
+// prueba de inclusión y el manifest del sello tal como se guardó, cuya
+// merkle_root es la raíz de la prueba.
+type verifyRequest struct {
+	Leaf        string             `json:"leaf"`
+	Index       int                `json:"index"`
+	TotalLeaves int                `json:"total_leaves"`
+	Proof       []merkle.ProofNode `json:"proof"`
+	Manifest    ledger.Manifest    `json:"manifest"`
+}
+
+type verifyResponse struct {
+	InclusionOK bool `json:"inclusion_ok"`
+	TreeSizeOK  bool `json:"tree_size_ok"`
+	SignatureOK bool `json:"signature_ok"`
+	Valid       bool `json:"valid"`
+}
+
+// handleVerify replica el verificador offline: inclusión Merkle bajo la raíz
+// del manifest, tamaño de árbol firmado (leaf_count) y firma del manifest según
+// su sig_version. 200 con el resultado (aunque sea inválido), 400 si el
+// payload está mal formado.
+func handleVerify(w http.ResponseWriter, r *http.Request) {
+	var req verifyRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed payload: "+err.Error())
+		return
+	}
+
+	// 1. Inclusión: una prueba estructuralmente incorrecta es simplemente inválida
+	inclusionOK, err := merkle.VerifyProof(req.Leaf, req.Index, req.TotalLeaves, req.Proof, req.Manifest.MerkleRoot)
+	if err != nil && !errors.Is(err, merkle.ErrInvalidProof) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 2. Solo el tamaño firmado impide que un nodo interno se haga pasar por hoja
+	treeSizeOK := req.Manifest.CheckTreeSize(req.TotalLeaves) == nil
+
+	// 3. Firma Ed25519 del manifest (digest firmado, o la raíz en sellos antiguos)
+	signatureOK, err := ledger.VerifyManifestSignature(req.Manifest)
+	if err != nil && !errors.Is(err, sign.ErrVerificationFailed) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, verifyResponse{
+		InclusionOK: inclusionOK,
+		TreeSizeOK:  treeSizeOK,
+		SignatureOK: signatureOK,
+		Valid:       inclusionOK && treeSizeOK && signatureOK,
+	})
+}
+
+// writeJSON serializa v como respuesta JSON con el código indicado.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError responde {"error": msg} con el código indicado.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest/fixture"
+	"github.com/olsencastillo051172/forged-lro/src/config"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+const testSeedHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+// newTestServer wires the production routes into an httptest server
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testLeaves returns the sha256 leaves of A, B and C
+func testLeaves() []string {
+	leaves := make([]string, 3)
+	for i, v := range []string{"A", "B", "C"} {
+		sum := sha256.Sum256([]byte(v))
+		leaves[i] = hex.EncodeToString(sum[:])
+	}
+	return leaves
+}
+
+// validVerifyRequest builds a certificate for leaf 1 of 3 under a manifest
+// signed the way PrepareSeal signs one (SigVersionDigest, leaf_count set)
+func validVerifyRequest(t *testing.T) verifyRequest {
+	t.Helper()
+	leaves := testLeaves()
+	proof, root, err := merkle.BuildProof(leaves, 1)
+	if err != nil {
+		t.Fatalf("BuildProof failed: %v", err)
+	}
+	m, err := ledger.Manifest{
+		MerkleRoot:   root,
+		Timestamp:    ledger.NormalizeTimestamp(time.Now()),
+		Canon:        config.CanonVersion,
+		PrevSealRoot: config.GenesisPrevHash,
+		LeafCount:    len(leaves),
+	}.Sign(testSeedHex)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+
+	return verifyRequest{
+		Leaf:        leaves[1],
+		Index:       1,
+		TotalLeaves: len(leaves),
+		Proof:       proof,
+		Manifest:    m,
+	}
+}
+
+func postVerify(t *testing.T, srv *httptest.Server, body []byte) (*http.Response, verifyResponse) {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/verify", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /verify failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var out verifyResponse
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp, out
+}
+
+func TestVerify_ValidPayload(t *testing.T) {
+	srv := newTestServer(t)
+	body, _ := json.Marshal(validVerifyRequest(t))
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !out.InclusionOK || !out.TreeSizeOK || !out.SignatureOK || !out.Valid {
+		t.Errorf("expected all checks to pass, got %+v", out)
+	}
+}
+
+func TestVerify_SealedCertificate(t *testing.T) {
+	fixture.TempLedger(t)
+	srv := newTestServer(t)
+	const h = "a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"
+	sealEpoch(t, h)
+
+	proof, err := ledger.ProveRegister(h)
+	if err != nil {
+		t.Fatalf("ProveRegister failed: %v", err)
+	}
+	seal, ok, err := ledger.LastSeal()
+	if err != nil || !ok {
+		t.Fatalf("LastSeal failed: ok=%v err=%v", ok, err)
+	}
+	body, _ := json.Marshal(verifyRequest{
+		Leaf:        proof.ObjectHashHex,
+		Index:       proof.LeafIndex,
+		TotalLeaves: proof.TotalLeaves,
+		Proof:       proof.Proof,
+		Manifest:    seal.Manifest,
+	})
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !out.Valid {
+		t.Errorf("stored seal should verify, got %+v", out)
+	}
+}
+
+func TestVerify_LegacyRootSignature(t *testing.T) {
+	srv := newTestServer(t)
+	req := validVerifyRequest(t)
+	sig, pub, err := sign.SignHashHex(req.Manifest.MerkleRoot, testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex failed: %v", err)
+	}
+	req.Manifest.SigVersion = ledger.SigVersionRoot
+	req.Manifest.LeafCount = 0
+	req.Manifest.Signature, req.Manifest.PublicKey = sig, pub
+	body, _ := json.Marshal(req)
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !out.Valid {
+		t.Errorf("root-signed manifest should verify, got %+v", out)
+	}
+}
+
+func TestVerify_InternalNodeAsLeaf(t *testing.T) {
+	srv := newTestServer(t)
+	req := validVerifyRequest(t)
+	leaves := testLeaves()
+
+	// H(A,B) proves against the root of [A,B,C] as leaf 0 of a 2-leaf tree
+	left, err := merkle.HashPair(leaves[0], leaves[1])
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	right, err := merkle.HashPair(leaves[2], leaves[2])
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	req.Leaf, req.Index, req.TotalLeaves = left, 0, 2
+	req.Proof = []merkle.ProofNode{{Hash: right, Position: "right"}}
+	body, _ := json.Marshal(req)
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !out.InclusionOK || !out.SignatureOK {
+		t.Fatalf("forged proof should pass inclusion and signature, got %+v", out)
+	}
+	if out.TreeSizeOK || out.Valid {
+		t.Errorf("expected the signed leaf_count to reject the forgery, got %+v", out)
+	}
+}
+
+func TestVerify_BadProof(t *testing.T) {
+	srv := newTestServer(t)
+	req := validVerifyRequest(t)
+	req.Proof[0].Hash = req.Leaf // well-formed but wrong sibling
+	body, _ := json.Marshal(req)
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if out.InclusionOK || out.Valid {
+		t.Errorf("expected inclusion failure, got %+v", out)
+	}
+	if !out.SignatureOK {
+		t.Errorf("manifest signature should still verify, got %+v", out)
+	}
+}
+
+func TestVerify_BadSignature(t *testing.T) {
+	srv := newTestServer(t)
+	req := validVerifyRequest(t)
+	// A signature over the bare root does not cover a SigVersionDigest manifest
+	otherSig, _, err := sign.SignHashHex(req.Manifest.MerkleRoot, testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex failed: %v", err)
+	}
+	req.Manifest.Signature = otherSig
+	body, _ := json.Marshal(req)
+
+	resp, out := postVerify(t, srv, body)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if !out.InclusionOK {
+		t.Errorf("inclusion should still verify, got %+v", out)
+	}
+	if out.SignatureOK || out.Valid {
+		t.Errorf("expected signature failure, got %+v", out)
+	}
+}
+
+func TestVerify_MalformedPayload(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{name: "not json", body: []byte("not json")},
+		{name: "uppercase leaf", body: func() []byte {
+			req := validVerifyRequest(t)
+			req.Leaf = "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855"
+			b, _ := json.Marshal(req)
+			return b
+		}()},
+		{name: "short signature", body: func() []byte {
+			req := validVerifyRequest(t)
+			req.Manifest.Signature = "abcd"
+			b, _ := json.Marshal(req)
+			return b
+		}()},
+		{name: "unknown sig version", body: func() []byte {
+			req := validVerifyRequest(t)
+			req.Manifest.SigVersion = 7
+			b, _ := json.Marshal(req)
+			return b
+		}()},
+		{name: "bare root fields", body: []byte(`{"leaf":"","index":0,"total_leaves":1,"proof":[],"root":"","signature":"","public_key":""}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _ := postVerify(t, srv, tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package ledger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// CanonicalEncGzip marks a register whose canonical JSON was gzipped before base64 encoding
+const CanonicalEncGzip = "gzip"
+
+// compressCanonical enables gzip storage for new registers (off by default)
+var compressCanonical atomic.Bool
+
+// SetCanonicalCompression turns gzip storage of canonical JSON on or off for
+// subsequent appends. Existing entries are unaffected, and both forms are
+// always readable.
+func SetCanonicalCompression(enabled bool) {
+	compressCanonical.Store(enabled)
+}
+
+// encodeCanonical returns the stored form of canonicalJSON and its encoding
+// marker ("" for plain base64).
+func encodeCanonical(canonicalJSON []byte) (string, string, error) {
+	if !compressCanonical.Load() {
+		return base64.StdEncoding.EncodeToString(canonicalJSON), "", nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(canonicalJSON); err != nil {
+		return "", "", fmt.Errorf("%w: failed to gzip canonical JSON: %v", ErrLedgerIO, err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", "", fmt.Errorf("%w: failed to gzip canonical JSON: %v", ErrLedgerIO, err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), CanonicalEncGzip, nil
+}
+
+// maxInflatedCanonicalBytes bounds what a gzip register may inflate to when
+// read. It is fixed rather than tied to SetMaxCanonicalJSONBytes, so lowering the
+// write cap never turns registers accepted under a higher one into corrupt
+// lines; a plain register is bounded the same way by maxLineBytes.
+const maxInflatedCanonicalBytes = maxLineBytes
+
+// decompressRegister rewrites a gzip-encoded register into the plain form, so
+// readers always see CanonicalJSONB64 as base64 of the original canonical bytes.
+// Decompression stops at maxInflatedCanonicalBytes, so a gzip bomb in a ledger line is reported as corrupt instead of inflated.
+// lineNum and line only feed the CorruptError; pass 0 and nil for an entry not read from a ledger.
+func decompressRegister(lineNum int, line []byte, reg RegisterEntry) (RegisterEntry, error) {
+	switch reg.CanonicalJSONEnc {
+	case "":
+		return reg, nil
+	case CanonicalEncGzip:
+	default:
+		return RegisterEntry{}, corruptLine(lineNum, line, "unknown canonical_json_enc %q", reg.CanonicalJSONEnc)
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(reg.CanonicalJSONB64)
+	if err != nil {
+		return RegisterEntry{}, corruptLine(lineNum, line, "invalid canonical_json_b64: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return RegisterEntry{}, corruptLine(lineNum, line, "invalid gzip canonical JSON: %v", err)
+	}
+	raw, err := io.ReadAll(io.LimitReader(zr, maxInflatedCanonicalBytes+1))
+	if err != nil {
+		return RegisterEntry{}, corruptLine(lineNum, line, "invalid gzip canonical JSON: %v", err)
+	}
+	if len(raw) > maxInflatedCanonicalBytes {
+		return RegisterEntry{}, corruptLine(lineNum, line, "gzip canonical JSON inflates past %d bytes", maxInflatedCanonicalBytes)
+	}
+
+	reg.CanonicalJSONB64 = base64.StdEncoding.EncodeToString(raw)
+	reg.CanonicalJSONEnc = ""
+	return reg, nil
+}
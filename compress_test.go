@@ -0,0 +1,167 @@
+package ledger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// enableCompression turns on gzip storage for the duration of the test
+func enableCompression(t *testing.T) {
+	t.Helper()
+	SetCanonicalCompression(true)
+	t.Cleanup(func() { SetCanonicalCompression(false) })
+}
+
+// largePayload returns a canonical JSON object of roughly n items
+func largePayload(t *testing.T, n int) []byte {
+	t.Helper()
+	items := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		items[fmt.Sprintf("key-%05d", i)] = strings.Repeat("value", 8)
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		t.Fatalf("failed to marshal payload: %v", err)
+	}
+	return payload
+}
+
+func TestCanonicalCompression_RoundTrip(t *testing.T) {
+	setupTestLedger(t)
+	enableCompression(t)
+
+	payload := largePayload(t, 2000)
+	hash := ComputeObjectHash(payload)
+	if err := AppendRegister(hash, payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// On disk: gzip marker, and much smaller than plain base64
+	data, err := os.ReadFile(GetLedgerPath())
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	var stored RegisterEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("failed to unmarshal stored entry: %v", err)
+	}
+	if stored.CanonicalJSONEnc != CanonicalEncGzip {
+		t.Fatalf("canonical_json_enc = %q, want gzip", stored.CanonicalJSONEnc)
+	}
+	if plain := base64.StdEncoding.EncodedLen(len(payload)); len(stored.CanonicalJSONB64) >= plain/2 {
+		t.Errorf("compressed blob is %d bytes, plain base64 would be %d", len(stored.CanonicalJSONB64), plain)
+	}
+
+	// Read back: transparently decompressed
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 1 {
+		t.Fatalf("expected 1 register, got %d", len(registers))
+	}
+	if registers[0].CanonicalJSONEnc != "" {
+		t.Errorf("read-back entry should be decompressed, enc = %q", registers[0].CanonicalJSONEnc)
+	}
+	got, err := base64.StdEncoding.DecodeString(registers[0].CanonicalJSONB64)
+	if err != nil {
+		t.Fatalf("failed to decode base64: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Fatalf("decompressed payload differs from original")
+	}
+
+	// The hash is over the original bytes, so replay finds no mismatch
+	mismatches, err := ReplayAudit()
+	if err != nil || len(mismatches) != 0 {
+		t.Fatalf("ReplayAudit = %+v, %v", mismatches, err)
+	}
+}
+
+func TestCanonicalCompression_MixedEntries(t *testing.T) {
+	setupTestLedger(t)
+
+	plain := []byte(`{"k":"plain"}`)
+	if err := AppendRegister(ComputeObjectHash(plain), plain); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	enableCompression(t)
+	hashHex, err := RegisterCanonical(map[string]string{"k": "gzip"})
+	if err != nil {
+		t.Fatalf("RegisterCanonical failed: %v", err)
+	}
+
+	// A compressed retry is still recognized as a duplicate of itself
+	if err := AppendRegisterIdempotent(hashHex, []byte(`{"k":"gzip"}`)); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 2 {
+		t.Fatalf("expected 2 registers, got %d", len(registers))
+	}
+	for i, want := range []string{`{"k":"plain"}`, `{"k":"gzip"}`} {
+		got, _ := base64.StdEncoding.DecodeString(registers[i].CanonicalJSONB64)
+		if string(got) != want {
+			t.Errorf("register %d payload = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestCanonicalCompression_UnknownEncoding(t *testing.T) {
+	path := setupTestLedger(t)
+	appendRaw(t, path, `{"type":"register","canon":"v1.0","timestamp":"2024-01-01T00:00:00Z","object_hash_hex":"`+validObjectHash()+`","canonical_json_b64":"e30=","canonical_json_enc":"zstd"}`+"\n")
+
+	if _, err := ListRegistersSince(time.Time{}); err == nil || !strings.Contains(err.Error(), "unknown canonical_json_enc") {
+		t.Fatalf("expected unknown encoding error, got %v", err)
+	}
+}
+
+func TestCanonicalCompression_InflationCapped(t *testing.T) {
+	path := setupTestLedger(t)
+
+	// A few KiB on disk that would inflate past the read limit
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(bytes.Repeat([]byte(" "), maxInflatedCanonicalBytes+1))
+	zw.Close()
+	blob := base64.StdEncoding.EncodeToString(buf.Bytes())
+	appendRaw(t, path, `{"type":"register","canon":"v1.0","timestamp":"2024-01-01T00:00:00Z","object_hash_hex":"`+validObjectHash()+`","canonical_json_b64":"`+blob+`","canonical_json_enc":"gzip"}`+"\n")
+
+	var corrupt *CorruptError
+	if _, err := ListRegistersSince(time.Time{}); !errors.As(err, &corrupt) || !strings.Contains(err.Error(), "inflates past") {
+		t.Fatalf("expected a corrupt line past the inflation limit, got %v", err)
+	}
+}
+
+func TestCanonicalCompression_LoweredCapKeepsOldRegisters(t *testing.T) {
+	setupTestLedger(t)
+	enableCompression(t)
+
+	payload := largePayload(t, 200)
+	if err := AppendRegister(ComputeObjectHash(payload), payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// The write cap only limits new appends
+	setMaxPayload(t, 16)
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 1 || registers[0].CanonicalJSONB64 != base64.StdEncoding.EncodeToString(payload) {
+		t.Errorf("register was not read back intact under a lowered cap")
+	}
+}
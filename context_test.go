@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// cancelingStore cancels a context once after lines have been read and counts
+// every line handed out, so tests can see how far a scan got.
+type cancelingStore struct {
+	Store
+	cancel context.CancelFunc
+	after  int
+	read   int
+}
+
+func (s *cancelingStore) Iterate(fn func(line []byte) error) error {
+	return s.Store.Iterate(func(line []byte) error {
+		s.read++
+		if s.read == s.after {
+			s.cancel()
+		}
+		return fn(line)
+	})
+}
+
+// setupCancelingLedger fills a MemStore with n registers and routes the ledger
+// through a cancelingStore that cancels the returned context after 'after' lines
+func setupCancelingLedger(t *testing.T, n, after int) (context.Context, *cancelingStore) {
+	t.Helper()
+	mem := setupMemLedger(t)
+	appendN(t, 0, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	st := &cancelingStore{Store: mem, cancel: cancel, after: after}
+	SetStore(st)
+	return ctx, st
+}
+
+func TestListRegistersSinceCtx_CancelMidScan(t *testing.T) {
+	const total = 2000
+	ctx, st := setupCancelingLedger(t, total, 100)
+
+	registers, err := ListRegistersSinceCtx(ctx, time.Time{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if registers != nil {
+		t.Errorf("expected no registers from an aborted scan, got %d", len(registers))
+	}
+	if st.read > 100+ctxCheckLines {
+		t.Errorf("scan read %d of %d lines after cancellation at line 100", st.read, total)
+	}
+}
+
+func TestListPendingRegistersCtx_CancelMidScan(t *testing.T) {
+	ctx, st := setupCancelingLedger(t, 2000, 100)
+
+	if _, err := ListPendingRegistersCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if st.read > 100+ctxCheckLines {
+		t.Errorf("scan read %d lines after cancellation at line 100", st.read)
+	}
+}
+
+func TestCheckIntegrityCtx_CancelMidScan(t *testing.T) {
+	ctx, st := setupCancelingLedger(t, 2000, 100)
+
+	if _, err := CheckIntegrityCtx(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if st.read > 100+ctxCheckLines {
+		t.Errorf("audit read %d lines after cancellation at line 100", st.read)
+	}
+}
+
+func TestListRegistersSinceCtx_AlreadyCancelled(t *testing.T) {
+	setupMemLedger(t)
+	appendN(t, 0, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ListRegistersSinceCtx(ctx, time.Time{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestListRegistersSinceCtx_LiveContext(t *testing.T) {
+	setupMemLedger(t)
+	appendN(t, 0, 3)
+
+	registers, err := ListRegistersSinceCtx(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSinceCtx failed: %v", err)
+	}
+	if len(registers) != 3 {
+		t.Errorf("got %d registers, want 3", len(registers))
+	}
+}
@@ -0,0 +1,67 @@
+package ledger
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// CorruptionPolicy decides what register listings do with a corrupt line.
+type CorruptionPolicy int32
+
+const (
+	// FailFast aborts the listing with the first CorruptError (the default).
+	FailFast CorruptionPolicy = iota
+
+	// SkipAndCollect skips corrupt lines, still returning every valid register,
+	// and reports the skipped lines through ListRegistersSinceCollect.
+	SkipAndCollect
+)
+
+// corruptionPolicy is the active CorruptionPolicy
+var corruptionPolicy atomic.Int32
+
+// SetCorruptionPolicy selects how ListRegistersSince, ListRegistersSinceCtx and
+// ListRegistersSinceCollect treat corrupt lines. It only affects these read
+// paths: sealing, integrity checks and every other scan still fail on the
+// first corrupt line, so a skipped line can never silently drop out of a seal.
+// That includes the listings that feed a root or a seal decision, which are
+// pinned to FailFast: ComputeEpochRoot, TreeFromLedger, ListPendingRegisters
+// and ShouldSeal.
+func SetCorruptionPolicy(p CorruptionPolicy) {
+	corruptionPolicy.Store(int32(p))
+}
+
+// listRegistersSinceFailFast is ListRegistersSinceCtx under FailFast whatever
+// the active policy, for listings whose result becomes a root or a seal decision.
+func listRegistersSinceFailFast(ctx context.Context, lastSealTS time.Time) ([]RegisterEntry, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	return listRegistersSinceIn(ctx, currentStore(), lastSealTS)
+}
+
+// ListRegistersSinceCollect is ListRegistersSinceCtx that also returns the
+// corrupt lines it skipped, in ledger order. Under FailFast the slice is always
+// nil and the first corrupt line is returned as the error. A torn or garbled
+// line leaves every other register readable under SkipAndCollect; I/O errors
+// and cancellation still fail the call.
+func ListRegistersSinceCollect(ctx context.Context, lastSealTS time.Time) ([]RegisterEntry, []CorruptError, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	if CorruptionPolicy(corruptionPolicy.Load()) != SkipAndCollect {
+		registers, err := listRegistersSinceIn(ctx, currentStore(), lastSealTS)
+		return registers, nil, err
+	}
+
+	var skipped []CorruptError
+	registers, err := listRegistersSinceWith(ctx, currentStore(), lastSealTS, func(ce *CorruptError) error {
+		skipped = append(skipped, *ce)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return registers, skipped, nil
+}
@@ -0,0 +1,88 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// corruptMiddleLedger writes register 0, a non-JSON line, a register with a bad
+// timestamp and register 1, returning the ledger path
+func corruptMiddleLedger(t *testing.T) string {
+	t.Helper()
+	path := setupTestLedger(t)
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, path, "{not json\n")
+	appendRaw(t, path, `{"type":"register","object_hash_hex":"`+testHash(9)+`","timestamp":"yesterday"}`+"\n")
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	return path
+}
+
+func TestCorruptionPolicy_FailFastIsDefault(t *testing.T) {
+	corruptMiddleLedger(t)
+
+	_, err := ListRegistersSince(time.Time{})
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) || corrupt.LineNum != 2 {
+		t.Fatalf("expected CorruptError at line 2, got %v", err)
+	}
+
+	regs, skipped, err := ListRegistersSinceCollect(context.Background(), time.Time{})
+	if !errors.Is(err, ErrLedgerCorrupt) || regs != nil || skipped != nil {
+		t.Fatalf("Collect under FailFast = %v, %v, %v", regs, skipped, err)
+	}
+}
+
+func TestCorruptionPolicy_SkipAndCollect(t *testing.T) {
+	corruptMiddleLedger(t)
+	SetCorruptionPolicy(SkipAndCollect)
+	t.Cleanup(func() { SetCorruptionPolicy(FailFast) })
+
+	regs, skipped, err := ListRegistersSinceCollect(context.Background(), time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSinceCollect failed: %v", err)
+	}
+	if len(regs) != 2 || regs[0].ObjectHashHex != testHash(0) || regs[1].ObjectHashHex != testHash(1) {
+		t.Fatalf("registers = %+v, want 0 and 1", regs)
+	}
+	if len(skipped) != 2 || skipped[0].LineNum != 2 || skipped[1].LineNum != 3 {
+		t.Fatalf("skipped = %+v, want lines 2 and 3", skipped)
+	}
+	if skipped[0].RawLine != "{not json" {
+		t.Errorf("skipped raw line = %q", skipped[0].RawLine)
+	}
+
+	// The plain listing returns the same registers without an error
+	if regs, err := ListRegistersSince(time.Time{}); err != nil || len(regs) != 2 {
+		t.Fatalf("ListRegistersSince = %d registers, %v", len(regs), err)
+	}
+
+	// Sealing never skips: the corrupt lines still block it
+	if _, err := SealPending(testSeedHex); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("SealPending: expected ErrLedgerCorrupt, got %v", err)
+	}
+}
+
+func TestCorruptionPolicy_RootAndSealInputsFailFast(t *testing.T) {
+	corruptMiddleLedger(t)
+	SetCorruptionPolicy(SkipAndCollect)
+	t.Cleanup(func() { SetCorruptionPolicy(FailFast) })
+
+	if _, _, err := ComputeEpochRoot(time.Time{}); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Errorf("ComputeEpochRoot: expected ErrLedgerCorrupt, got %v", err)
+	}
+	if _, _, err := TreeFromLedger(time.Time{}); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Errorf("TreeFromLedger: expected ErrLedgerCorrupt, got %v", err)
+	}
+	if _, err := ListPendingRegisters(); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Errorf("ListPendingRegisters: expected ErrLedgerCorrupt, got %v", err)
+	}
+	if _, _, err := ShouldSeal(); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Errorf("ShouldSeal: expected ErrLedgerCorrupt, got %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// ComputeEpochRoot builds the Merkle root over every register whose timestamp is
+// after since.
+//
+// Leaves are the registers' ObjectHashHex values in file (insertion) order,
+// which is the order a seal commits to. They are NOT sorted. Registers are
+// selected by timestamp, so one appended later but stamped at or before since
+// is not a leaf.
+//
+// Returns:
+//   - root: 64 lowercase hex Merkle root
+//   - count: number of registers covered
+//   - ErrNoRegistrations if no register is stamped after since
+//   - A *CorruptError for the first corrupt line, under any CorruptionPolicy
+func ComputeEpochRoot(since time.Time) (string, int, error) {
+	registers, err := listRegistersSinceFailFast(context.Background(), since)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if len(registers) == 0 {
+		return "", 0, ErrNoRegistrations
+	}
+
+	root, err := merkle.BuildRoot(registerLeaves(registers))
+	if err != nil {
+		return "", 0, err
+	}
+
+	return root, len(registers), nil
+}
+
+// registerLeaves extracts Merkle leaves from registers, preserving order
+func registerLeaves(registers []RegisterEntry) []string {
+	leaves := make([]string, len(registers))
+	for i, reg := range registers {
+		leaves[i] = reg.ObjectHashHex
+	}
+	return leaves
+}
+
+// ListPendingRegisters returns the registers appended after the last seal,
+// i.e. the registers the next seal will cover, in file order. Like sealing, it
+// fails on the first corrupt line under any CorruptionPolicy.
+func ListPendingRegisters() ([]RegisterEntry, error) {
+	return ListPendingRegistersCtx(context.Background())
+}
+
+// ListPendingRegistersCtx is ListPendingRegisters that stops scanning once ctx
+// is done, returning an error that wraps ctx.Err().
+func ListPendingRegistersCtx(ctx context.Context) ([]RegisterEntry, error) {
+	lastSealTS, err := getLastSealTimestampCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return listRegistersSinceFailFast(ctx, lastSealTS)
+}
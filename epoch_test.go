@@ -0,0 +1,117 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+func TestComputeEpochRoot_MatchesManualTree(t *testing.T) {
+	setupTestLedger(t)
+
+	hashes := []string{
+		"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		validObjectHash(),
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	}
+	for _, h := range hashes {
+		if err := AppendRegister(h, nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	root, count, err := ComputeEpochRoot(time.Time{})
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if count != len(hashes) {
+		t.Errorf("count = %d, want %d", count, len(hashes))
+	}
+
+	want, err := merkle.BuildRoot(hashes)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	if root != want {
+		t.Errorf("root = %s, want %s", root, want)
+	}
+
+	// Insertion order, not sorted order, must be committed
+	sorted := []string{hashes[2], hashes[1], hashes[0]}
+	sortedRoot, err := merkle.BuildRoot(sorted)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	if root == sortedRoot {
+		t.Errorf("root should depend on insertion order")
+	}
+}
+
+func TestComputeEpochRoot_SinceLastSeal(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if err := AppendSeal(validManifest()); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+	lastSealTS, err := getLastSealTimestamp()
+	if err != nil {
+		t.Fatalf("getLastSealTimestamp failed: %v", err)
+	}
+
+	pending := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	if err := AppendRegister(pending, nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	root, count, err := ComputeEpochRoot(lastSealTS)
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if count != 1 || root != pending {
+		t.Errorf("got root=%s count=%d, want root=%s count=1", root, count, pending)
+	}
+}
+
+func TestComputeEpochRoot_NoRegistrations(t *testing.T) {
+	setupTestLedger(t)
+
+	_, _, err := ComputeEpochRoot(time.Time{})
+	if !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+}
+
+func TestListPendingRegisters(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if err := AppendSeal(validManifest()); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	pending, err := ListPendingRegisters()
+	if err != nil {
+		t.Fatalf("ListPendingRegisters failed: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Errorf("expected 0 pending after seal, got %d", len(pending))
+	}
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	pending, err = ListPendingRegisters()
+	if err != nil {
+		t.Fatalf("ListPendingRegisters failed: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Errorf("expected 1 pending, got %d", len(pending))
+	}
+}
@@ -0,0 +1,117 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func parseCSV(t *testing.T, data []byte) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, data)
+	}
+	return records
+}
+
+func TestExportCSV_RowsMatchRegisters(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2, 1)
+	if err := AppendRegister(validObjectHash(), []byte(`{"k":"v"}`)); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	regs, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, time.Time{}); err != nil {
+		t.Fatalf("ExportCSV failed: %v", err)
+	}
+	records := parseCSV(t, buf.Bytes())
+
+	if len(records) != len(regs)+1 {
+		t.Fatalf("got %d rows, want header + %d registers", len(records), len(regs))
+	}
+	if got := records[0]; len(got) != 4 || got[0] != "timestamp" || got[3] != "epoch_id" {
+		t.Errorf("unexpected header %v", got)
+	}
+
+	// Two registers in epoch 0, one in epoch 1, one pending for epoch 2
+	wantEpochs := []int{0, 0, 1, 2}
+	for i, reg := range regs {
+		row := records[i+1]
+		if row[0] != reg.Timestamp || row[1] != reg.ObjectHashHex {
+			t.Errorf("row %d = %v, want %s %s", i, row, reg.Timestamp, reg.ObjectHashHex)
+		}
+		if want := strconv.FormatBool(reg.CanonicalJSONB64 != ""); row[2] != want {
+			t.Errorf("row %d has_canonical_json = %s, want %s", i, row[2], want)
+		}
+		if row[3] != strconv.Itoa(wantEpochs[i]) {
+			t.Errorf("row %d epoch_id = %s, want %d", i, row[3], wantEpochs[i])
+		}
+	}
+}
+
+func TestExportCSV_Since(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2)
+	since := mustLastSealTimestamp(t)
+	setClock(t, since.Add(time.Minute))
+	if err := AppendRegisterWithTimestamp(validObjectHash(), since.Add(time.Second), nil); err != nil {
+		t.Fatalf("AppendRegisterWithTimestamp failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, since); err != nil {
+		t.Fatalf("ExportCSV failed: %v", err)
+	}
+	records := parseCSV(t, buf.Bytes())
+	if len(records) != 2 {
+		t.Fatalf("got %d rows, want header + 1 register", len(records))
+	}
+	if records[1][1] != validObjectHash() || records[1][3] != "1" {
+		t.Errorf("unexpected row %v", records[1])
+	}
+}
+
+func TestExportSealsCSV(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1, 2)
+	seals := readSeals(t)
+
+	var buf bytes.Buffer
+	if err := ExportSealsCSV(&buf, time.Time{}); err != nil {
+		t.Fatalf("ExportSealsCSV failed: %v", err)
+	}
+	records := parseCSV(t, buf.Bytes())
+	if len(records) != len(seals)+1 {
+		t.Fatalf("got %d rows, want header + %d seals", len(records), len(seals))
+	}
+	for i, seal := range seals {
+		m := seal.Manifest
+		want := []string{strconv.Itoa(m.EpochID), m.Timestamp, m.MerkleRoot, m.PrevSealRoot, m.PublicKey, m.Signature}
+		for j := range want {
+			if records[i+1][j] != want[j] {
+				t.Errorf("seal row %d column %s = %q, want %q", i, sealCSVHeader[j], records[i+1][j], want[j])
+			}
+		}
+	}
+}
+
+func TestExportCSV_EmptyLedger(t *testing.T) {
+	setupTestLedger(t)
+
+	var buf bytes.Buffer
+	if err := ExportCSV(&buf, time.Time{}); err != nil {
+		t.Fatalf("ExportCSV failed: %v", err)
+	}
+	if records := parseCSV(t, buf.Bytes()); len(records) != 1 {
+		t.Errorf("expected only the header row, got %v", records)
+	}
+}
@@ -0,0 +1,52 @@
+package ledger
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultFileLockTimeout bounds how long an append waits for another process
+// holding the ledger file lock
+const DefaultFileLockTimeout = 5 * time.Second
+
+// fileLockRetryInterval is the pause between non-blocking lock attempts
+const fileLockRetryInterval = 10 * time.Millisecond
+
+// fileLockTimeout is stored as nanoseconds so it can be changed while appends run
+var fileLockTimeout atomic.Int64
+
+func init() {
+	fileLockTimeout.Store(int64(DefaultFileLockTimeout))
+}
+
+// SetFileLockTimeout sets how long FileStore.Append waits for the advisory file
+// lock before giving up with ErrLedgerIO. Non-positive values restore the default.
+func SetFileLockTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultFileLockTimeout
+	}
+	fileLockTimeout.Store(int64(d))
+}
+
+// lockFile takes an exclusive advisory lock on f, retrying until the timeout.
+// ledgerMutex only serialises goroutines; this lock serialises processes that
+// share the same ledger file. The caller must call unlockFile when done.
+func lockFile(f *os.File) error {
+	timeout := time.Duration(fileLockTimeout.Load())
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			return fmt.Errorf("%w: failed to lock ledger: %v", ErrLedgerIO, err)
+		}
+		if locked {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: ledger locked by another process (waited %v)", ErrLedgerIO, timeout)
+		}
+		time.Sleep(fileLockRetryInterval)
+	}
+}
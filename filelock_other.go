@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package ledger
+
+import "os"
+
+// tryLockFile is a no-op where advisory locks are unavailable: only the
+// in-process ledgerMutex applies
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile is a no-op, see tryLockFile
+func unlockFile(f *os.File) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build unix
+
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFileStore_ConcurrentWritersDoNotInterleave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	padding := strings.Repeat("x", 16*1024)
+
+	// Each writer has its own FileStore, as separate processes would, so only
+	// the file lock serialises them
+	const writers, perWriter = 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			st := &FileStore{Path: path}
+			for i := 0; i < perWriter; i++ {
+				line, _ := json.Marshal(map[string]string{"type": "note", "id": fmt.Sprintf("%d-%d", w, i), "pad": padding})
+				if err := st.Append(line); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	lines := 0
+	err := (&FileStore{Path: path}).Iterate(func(line []byte) error {
+		lines++
+		var entry map[string]string
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return fmt.Errorf("line %d is interleaved or partial: %v", lines, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines != writers*perWriter {
+		t.Fatalf("got %d lines, want %d", lines, writers*perWriter)
+	}
+}
+
+func TestFileStore_LockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	SetFileLockTimeout(50 * time.Millisecond)
+	t.Cleanup(func() { SetFileLockTimeout(0) })
+
+	// Another "process" holds the lock
+	holder, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer holder.Close()
+	if err := syscall.Flock(int(holder.Fd()), syscall.LOCK_EX); err != nil {
+		t.Fatalf("flock failed: %v", err)
+	}
+
+	st := &FileStore{Path: path}
+	if err := st.Append([]byte(`{"type":"note"}`)); !errors.Is(err, ErrLedgerIO) {
+		t.Fatalf("expected ErrLedgerIO while locked, got %v", err)
+	}
+
+	if err := syscall.Flock(int(holder.Fd()), syscall.LOCK_UN); err != nil {
+		t.Fatalf("unlock failed: %v", err)
+	}
+	if err := st.Append([]byte(`{"type":"note"}`)); err != nil {
+		t.Fatalf("Append after release failed: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// FuzzListRegisters feeds arbitrary bytes as the ledger file. ListRegistersSince
+// must not panic, and must either succeed or fail with ErrLedgerCorrupt or
+// ErrLedgerIO: a hostile ledger is a corrupt ledger, nothing else.
+func FuzzListRegisters(f *testing.F) {
+	register := `{"type":"register","canon":"v1.0","timestamp":"2024-01-01T00:00:00.000000000Z","object_hash_hex":"` + validObjectHash() + `"}`
+	seal := `{"type":"seal","manifest":{"merkle_root":"` + validObjectHash() + `","timestamp":"2024-01-01T00:00:01.000000000Z"}}`
+
+	// Known-good ledgers
+	f.Add([]byte(""))
+	f.Add([]byte(register + "\n"))
+	f.Add([]byte(register + "\n" + seal + "\n" + register + "\n"))
+	f.Add([]byte(register + "\r\n\n\n" + register))
+	f.Add([]byte(`{"type":"header","canon":"v1.0"}` + "\n" + register + "\n"))
+	f.Add([]byte(`{"type":"register","canon":"v1.0","timestamp":"2024-01-01T00:00:00Z","object_hash_hex":"` + validObjectHash() + `","canonical_json_b64":"H4sIAAAAAAAA/6pWSlSyMjQyqAUAAAD//w==","canonical_json_enc":"gzip"}` + "\n"))
+
+	// Known-bad ledgers
+	f.Add([]byte("not json\n"))
+	f.Add([]byte(register[:40] + "\n"))
+	f.Add([]byte("\x00\x00\x00\n" + register + "\n"))
+	f.Add([]byte(strings.Replace(register, "canon", "can\x00on", 1) + "\n"))
+	f.Add([]byte(`{"type":"register","timestamp":12}` + "\n"))
+	f.Add([]byte(`{"type":"register","timestamp":"yesterday"}` + "\n"))
+	f.Add([]byte(`{"type":"register","timestamp":"2024-01-01T00:00:00Z","canonical_json_enc":"gzip","canonical_json_b64":"%%%"}` + "\n"))
+	f.Add([]byte(`{"type":"register","timestamp":"2024-01-01T00:00:00Z","canonical_json_enc":"gzip","canonical_json_b64":"AAAA"}` + "\n"))
+	f.Add([]byte(`{"type":"register","timestamp":"2024-01-01T00:00:00Z","canonical_json_enc":"zstd"}` + "\n"))
+	f.Add([]byte(`{"type":["register"]}` + "\n"))
+	f.Add([]byte("[1,2,3]\nnull\n\"register\"\n"))
+	f.Add(append([]byte(`{"type":"register","pad":"`), bytes.Repeat([]byte("x"), 100000)...))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		path := filepath.Join(t.TempDir(), "ledger.jsonl")
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+		SetLedgerPath(path)
+
+		registers, err := ListRegistersSince(time.Time{})
+		if err != nil {
+			if !errors.Is(err, ErrLedgerCorrupt) && !errors.Is(err, ErrLedgerIO) {
+				t.Fatalf("unexpected error category: %v", err)
+			}
+			return
+		}
+		for _, reg := range registers {
+			if _, err := ParseCanonTimestamp(reg.Timestamp); err != nil {
+				t.Fatalf("returned register with unparseable timestamp %q", reg.Timestamp)
+			}
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package ledger
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// objectHash is how the ledger hashes and validates object hashes (and the
+// Merkle roots built over them) under one algorithm.
+type objectHash struct {
+	name    string
+	hexLen  int
+	pattern *regexp.Regexp
+	sum     func(canonicalJSON []byte) string
+}
+
+var objectHashes = map[string]*objectHash{
+	"sha256": {name: "sha256", hexLen: 64, pattern: hex64Pattern, sum: hash.Sha256Hex},
+	"sha512": {name: "sha512", hexLen: 128, pattern: hex128Pattern, sum: sha512Hex},
+}
+
+// activeObjectHash is the ledger hash algorithm (sha256 unless SetHashAlg changed it)
+var activeObjectHash atomic.Pointer[objectHash]
+
+func init() {
+	activeObjectHash.Store(objectHashes[HeaderHashAlg])
+}
+
+// SetHashAlg selects the object hash algorithm for subsequent calls: "sha256"
+// (the default, 64 hex chars) or "sha512" (128 hex chars). It switches
+// ComputeObjectHash, the object_hash_hex and merkle_root validation, the hash_alg
+// written to and expected in the ledger header, and the Merkle node hash
+// (merkle.SetHashAlg), so leaves and roots always agree. Entries already in the
+// ledger are not rewritten. Seals sign the 32-byte SigningDigest of their
+// manifest, so they work under either algorithm; only legacy SigVersionRoot
+// signatures need a 32-byte root and cannot be made or checked under sha512.
+// Returns merkle.ErrUnsupportedHashAlg for any other name.
+func SetHashAlg(alg string) error {
+	// merkle supports exactly the algorithms in objectHashes
+	if err := merkle.SetHashAlg(alg); err != nil {
+		return err
+	}
+	activeObjectHash.Store(objectHashes[alg])
+	return nil
+}
+
+// HashAlg returns the active object hash algorithm name.
+func HashAlg() string {
+	return activeObjectHash.Load().name
+}
+
+func sha512Hex(data []byte) string {
+	sum := sha512.Sum512(data)
+	return hex.EncodeToString(sum[:])
+}
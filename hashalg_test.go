@@ -0,0 +1,147 @@
+package ledger
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// useHashAlg switches the ledger to alg for the duration of the test
+func useHashAlg(t *testing.T, alg string) {
+	t.Helper()
+	if err := SetHashAlg(alg); err != nil {
+		t.Fatalf("SetHashAlg(%q) failed: %v", alg, err)
+	}
+	t.Cleanup(func() { SetHashAlg(HeaderHashAlg) })
+}
+
+func TestSetHashAlg_SHA512AppendAndList(t *testing.T) {
+	path := setupTestLedger(t)
+	useHashAlg(t, "sha512")
+
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	canonical := []byte(`{"k":"v"}`)
+	objectHash := ComputeObjectHash(canonical)
+	sum := sha512.Sum512(canonical)
+	if objectHash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("ComputeObjectHash = %s, want SHA-512 of the canonical bytes", objectHash)
+	}
+	if err := AppendRegister(objectHash, canonical); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if err := AppendRegister(ComputeObjectHash([]byte(`{"k":"w"}`)), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 2 || registers[0].ObjectHashHex != objectHash {
+		t.Fatalf("unexpected registers: %+v", registers)
+	}
+
+	// Merkle leaves agree with the object hash width
+	root, _, err := ComputeEpochRoot(time.Time{})
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if len(root) != 128 {
+		t.Errorf("epoch root %q is not a SHA-512 digest", root)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"hash_alg":"sha512"`) {
+		t.Errorf("header does not record sha512: %s", data)
+	}
+}
+
+func TestSetHashAlg_SHA512RejectsSHA256Hash(t *testing.T) {
+	setupTestLedger(t)
+	useHashAlg(t, "sha512")
+
+	if err := AppendRegister(validObjectHash(), nil); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex for a 64-char hash, got %v", err)
+	}
+	if _, _, err := GetRegisterByHash(validObjectHash()); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex from lookup, got %v", err)
+	}
+}
+
+func TestSetHashAlg_Unsupported(t *testing.T) {
+	if err := SetHashAlg("md5"); !errors.Is(err, merkle.ErrUnsupportedHashAlg) {
+		t.Fatalf("expected ErrUnsupportedHashAlg, got %v", err)
+	}
+	if HashAlg() != "sha256" || merkle.HashAlg() != "sha256" {
+		t.Errorf("failed switch changed the algorithm: ledger %s, merkle %s", HashAlg(), merkle.HashAlg())
+	}
+	if len(ComputeObjectHash([]byte("x"))) != 64 {
+		t.Errorf("default ComputeObjectHash is not SHA-256")
+	}
+}
+
+func TestSetHashAlg_SHA512SealAndVerify(t *testing.T) {
+	setupTestLedger(t)
+	useHashAlg(t, "sha512")
+	SetSealSignatureEnforcement(true)
+	t.Cleanup(func() { SetSealSignatureEnforcement(false) })
+
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	var hashes []string
+	for _, v := range []string{`{"k":1}`, `{"k":2}`, `{"k":3}`} {
+		h := ComputeObjectHash([]byte(v))
+		if err := AppendRegister(h, nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+		hashes = append(hashes, h)
+	}
+
+	// Two-step seal: PrepareSeal, then AppendSeal with signature enforcement
+	prepared, _, err := PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+	if len(prepared.MerkleRoot) != 128 {
+		t.Fatalf("merkle_root %q is not a SHA-512 root", prepared.MerkleRoot)
+	}
+	if err := AppendSeal(prepared); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	// One-step seal
+	h := ComputeObjectHash([]byte(`{"k":4}`))
+	if err := AppendRegister(h, nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	hashes = append(hashes, h)
+	sealed, err := SealPending(testSeedHex)
+	if err != nil {
+		t.Fatalf("SealPending failed: %v", err)
+	}
+
+	for _, m := range []Manifest{prepared, sealed} {
+		if ok, err := VerifyManifestSignature(m); !ok {
+			t.Errorf("epoch %d: signature does not verify: %v", m.EpochID, err)
+		}
+	}
+	for _, h := range hashes {
+		result, err := VerifyRegisterSealed(h)
+		if err != nil || !result.Valid {
+			t.Errorf("VerifyRegisterSealed(%s) = %+v, %v", h, result, err)
+		}
+	}
+	report, err := CheckIntegrity()
+	if err != nil || !report.Valid || report.Seals != 2 {
+		t.Fatalf("CheckIntegrity = %+v, %v", report, err)
+	}
+}
@@ -0,0 +1,82 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+// HeaderHashAlg is the default hash algorithm recorded in ledger headers.
+// SetHashAlg changes what new headers record and what existing ones must say.
+const HeaderHashAlg = "sha256"
+
+// HeaderEntry is the optional first line of a ledger file. It makes the file
+// self-describing: a reader can tell which canon and hash algorithm wrote it.
+type HeaderEntry struct {
+	Type    string `json:"type"`     // Always "header"
+	Canon   string `json:"canon"`    // Canon version of the writer (e.g., "v1.0")
+	HashAlg string `json:"hash_alg"` // Leaf and object hash algorithm
+}
+
+// InitLedger writes the header line if the ledger is empty. It is a no-op for a
+// ledger that already has entries, with or without a header.
+func InitLedger() error {
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+	empty := true
+	err := scanStore(st, func(lineNum int, entryType string, line []byte) error {
+		empty = false
+		return errStopScan
+	})
+	if err != nil || !empty {
+		return err
+	}
+
+	return appendEntryTo(st, HeaderEntry{
+		Type:    "header",
+		Canon:   config.CanonVersion,
+		HashAlg: HashAlg(),
+	})
+}
+
+// checkHeaderIn refuses writes to a ledger whose header was written by an
+// incompatible canon (different major version) or hash algorithm. Ledgers
+// without a header are accepted; a garbled first line is left for scans to
+// report. The caller must hold ledgerMutex.
+func checkHeaderIn(st Store) error {
+	var header HeaderEntry
+	err := st.Iterate(func(line []byte) error {
+		if len(line) == 0 {
+			return nil
+		}
+		if json.Unmarshal(line, &header) != nil {
+			header = HeaderEntry{}
+		}
+		return errStopScan
+	})
+	if err != nil && !errors.Is(err, errStopScan) {
+		return err
+	}
+	if header.Type != "header" {
+		return nil
+	}
+
+	if canonMajor(header.Canon) != canonMajor(config.CanonVersion) {
+		return fmt.Errorf("%w: ledger header canon %q is incompatible with %q", ErrCanonMismatch, header.Canon, config.CanonVersion)
+	}
+	if alg := HashAlg(); header.HashAlg != alg {
+		return fmt.Errorf("%w: ledger header hash_alg %q, expected %q", ErrCanonMismatch, header.HashAlg, alg)
+	}
+	return nil
+}
+
+// canonMajor returns the major component of a canon version: "v1.0" -> "1"
+func canonMajor(version string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	return major
+}
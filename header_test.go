@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInitLedger_WritesHeaderOnce(t *testing.T) {
+	path := setupTestLedger(t)
+
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	if err := InitLedger(); err != nil {
+		t.Fatalf("second InitLedger failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected a single header line, got %d lines", len(lines))
+	}
+	var header HeaderEntry
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("failed to unmarshal header: %v", err)
+	}
+	if header != (HeaderEntry{Type: "header", Canon: "v1.0", HashAlg: "sha256"}) {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+}
+
+func TestInitLedger_NoopOnExistingLedger(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"header"`) {
+		t.Fatalf("header written into a non-empty ledger:\n%s", data)
+	}
+}
+
+func TestHeader_SkippedByQueries(t *testing.T) {
+	setupTestLedger(t)
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	buildSealedLedger(t, 2)
+	if err := AppendRegister(testHash(10), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	regs, err := ListRegistersSince(time.Time{})
+	if err != nil || len(regs) != 3 {
+		t.Fatalf("ListRegistersSince = %d, %v; want 3 registers", len(regs), err)
+	}
+	if pending, err := ListPendingRegisters(); err != nil || len(pending) != 1 {
+		t.Fatalf("ListPendingRegisters = %d, %v; want 1 register", len(pending), err)
+	}
+	if ts, err := getLastSealTimestamp(); err != nil || ts.IsZero() {
+		t.Fatalf("getLastSealTimestamp = %v, %v", ts, err)
+	}
+	if report, err := CheckIntegrity(); err != nil || !report.Valid {
+		t.Fatalf("expected valid ledger with header, got %+v err=%v", report.Violations, err)
+	}
+}
+
+func TestHeader_RejectsIncompatibleCanon(t *testing.T) {
+	tests := []string{
+		`{"type":"header","canon":"v2.0","hash_alg":"sha256"}`,
+		`{"type":"header","canon":"v1.0","hash_alg":"sha512"}`,
+	}
+	for _, header := range tests {
+		path := setupTestLedger(t)
+		if err := os.WriteFile(path, []byte(header+"\n"), 0644); err != nil {
+			t.Fatalf("failed to write header: %v", err)
+		}
+
+		if err := AppendRegister(validObjectHash(), nil); !errors.Is(err, ErrCanonMismatch) {
+			t.Errorf("%s: expected ErrCanonMismatch, got %v", header, err)
+		}
+		data, _ := os.ReadFile(path)
+		if string(data) != header+"\n" {
+			t.Errorf("%s: ledger modified despite incompatible header", header)
+		}
+	}
+
+	// A later minor version is compatible
+	path := setupTestLedger(t)
+	if err := os.WriteFile(path, []byte(`{"type":"header","canon":"v1.3","hash_alg":"sha256"}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write header: %v", err)
+	}
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister under a v1.x header failed: %v", err)
+	}
+}
@@ -0,0 +1,317 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// indexBlockBytes is the minimum size of an indexed block (shortened in tests)
+var indexBlockBytes int64 = 64 * 1024
+
+// indexing enables index maintenance on FileStore appends (off by default)
+var indexing atomic.Bool
+
+// SetIndexing turns maintenance of the .idx sidecar on or off for subsequent
+// appends. Queries use an existing, consistent sidecar either way; BuildIndex
+// creates one for a ledger that already has entries.
+func SetIndexing(enabled bool) {
+	indexing.Store(enabled)
+}
+
+// IndexPath returns the sidecar index path for a ledger file
+func IndexPath(ledgerPath string) string {
+	return ledgerPath + ".idx"
+}
+
+// indexBlock describes one closed block of whole ledger lines.
+//
+// MaxTS is the latest timestamp of any entry in the block, or empty if some
+// line has no parseable timestamp; such a block is never skipped, so a corrupt
+// line is reported exactly as the linear scan would report it.
+type indexBlock struct {
+	Offset int64  `json:"offset"` // Byte offset of the block's first line
+	End    int64  `json:"end"`    // Byte offset just past the block's last newline
+	Line   int    `json:"line"`   // Line number of the first line
+	Lines  int    `json:"lines"`  // Number of lines in the block, empty lines included
+	MaxTS  string `json:"max_ts"` // RFC3339Nano, see above
+}
+
+// closed reports whether the block reached indexBlockBytes
+func (b indexBlock) closed() bool {
+	return b.End-b.Offset >= indexBlockBytes
+}
+
+// skippable reports whether every entry in the block is at or before since
+func (b indexBlock) skippable(since time.Time) bool {
+	if b.MaxTS == "" {
+		return false
+	}
+	ts, err := ParseCanonTimestamp(b.MaxTS)
+	return err == nil && !ts.After(since)
+}
+
+// BuildIndex rebuilds the .idx sidecar of the current file ledger from scratch,
+// e.g. after it was deleted or the ledger was rewritten by an external tool.
+// With any Store other than FileStore it is a no-op.
+func BuildIndex() error {
+	ledgerMutex.Lock()
+	defer ledgerMutex.Unlock()
+
+	fs, ok := currentStore().(*FileStore)
+	if !ok {
+		return nil
+	}
+	return fs.buildIndex()
+}
+
+// Seek returns where a scan for entries after since may start: the byte offset
+// and line number of the first indexed block that can hold a newer entry. Without
+// a consistent index it returns (0, 1): a scan from the top.
+func (s *FileStore) Seek(since time.Time) (offset int64, lineNum int, err error) {
+	blocks, ok, err := s.readIndex()
+	if err != nil || !ok || len(blocks) == 0 {
+		return 0, 1, err
+	}
+
+	offset, lineNum = 0, 1
+	for _, b := range blocks {
+		if !b.skippable(since) {
+			break
+		}
+		offset, lineNum = b.End, b.Line+b.Lines
+	}
+	if offset == 0 {
+		return 0, 1, nil
+	}
+
+	// The ledger must still be at least as long as the index says, with a line
+	// break right before the seek point; otherwise the index is stale
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return 0, 1, nil
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || info.Size() < blocks[len(blocks)-1].End {
+		return 0, 1, nil
+	}
+	prev := make([]byte, 1)
+	if _, err := file.ReadAt(prev, offset-1); err != nil || prev[0] != '\n' {
+		return 0, 1, nil
+	}
+	return offset, lineNum, nil
+}
+
+// readIndex loads the sidecar. ok is false if it is missing, or stale because
+// its blocks are not contiguous from the top of the ledger.
+func (s *FileStore) readIndex() (blocks []indexBlock, ok bool, err error) {
+	data, err := os.ReadFile(IndexPath(s.Path))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: failed to read index: %v", ErrLedgerIO, err)
+	}
+
+	var end int64
+	next := 1
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var b indexBlock
+		if err := json.Unmarshal(line, &b); err != nil || b.Offset != end || b.Line != next || b.End <= b.Offset {
+			return nil, false, nil
+		}
+		blocks = append(blocks, b)
+		end, next = b.End, b.Line+b.Lines
+	}
+	return blocks, true, nil
+}
+
+// updateIndex closes a block if the bytes appended since the last one reach
+// indexBlockBytes. size is the ledger size after an append of appended bytes.
+// The caller holds the file lock. A consistent sidecar is extended; a new one
+// is started only when this append opened an empty ledger. A missing or stale
+// sidecar means linear scans until BuildIndex is run.
+func (s *FileStore) updateIndex(size int64, appended int) error {
+	blocks, ok, err := s.readIndex()
+	if err != nil {
+		return err
+	}
+	fresh := size == int64(appended)
+	if !ok && !fresh {
+		return nil
+	}
+	if fresh {
+		blocks = nil
+	}
+
+	start, line := int64(0), 1
+	if len(blocks) > 0 {
+		last := blocks[len(blocks)-1]
+		start, line = last.End, last.Line+last.Lines
+	}
+	if size-start < indexBlockBytes {
+		if fresh {
+			return writeIndexBlocks(IndexPath(s.Path), nil, false)
+		}
+		return nil
+	}
+
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	block, err := scanIndexBlock(io.NewSectionReader(file, start, size-start), start, line)
+	if err != nil || !block.closed() {
+		return err
+	}
+	return writeIndexBlocks(IndexPath(s.Path), []indexBlock{block}, !fresh)
+}
+
+// buildIndex rescans the whole ledger and rewrites the sidecar
+func (s *FileStore) buildIndex() error {
+	file, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		return writeIndexBlocks(IndexPath(s.Path), nil, false)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+
+	// Hold off appends from other processes while the ledger is summarised
+	if err := lockFile(file); err != nil {
+		return err
+	}
+	defer unlockFile(file)
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: failed to stat ledger: %v", ErrLedgerIO, err)
+	}
+
+	var blocks []indexBlock
+	start, line := int64(0), 1
+	for info.Size()-start >= indexBlockBytes {
+		block, err := scanIndexBlock(io.NewSectionReader(file, start, info.Size()-start), start, line)
+		if err != nil {
+			return err
+		}
+		if !block.closed() {
+			break
+		}
+		blocks = append(blocks, block)
+		start, line = block.End, block.Line+block.Lines
+	}
+	return writeIndexBlocks(IndexPath(s.Path), blocks, false)
+}
+
+// scanIndexBlock reads whole lines from r (positioned at offset, line lineNum)
+// until at least indexBlockBytes are covered, and summarises them as a block.
+// The block is not closed if r ran out first.
+func scanIndexBlock(r io.Reader, offset int64, lineNum int) (indexBlock, error) {
+	block := indexBlock{Offset: offset, End: offset, Line: lineNum}
+	var maxTS time.Time
+	bounded := true
+
+	reader := bufio.NewReader(r)
+	for block.End-block.Offset < indexBlockBytes {
+		line, err := reader.ReadBytes('\n')
+		if !bytes.HasSuffix(line, []byte("\n")) {
+			// A line without its newline is not complete yet: leave it to the open block
+			if err != nil && !errors.Is(err, io.EOF) {
+				return indexBlock{}, fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+			}
+			break
+		}
+		block.End += int64(len(line))
+		block.Lines++
+
+		if content := bytes.TrimSuffix(line, []byte("\n")); len(content) > 0 {
+			ts, ok := entryTimestamp(content)
+			if !ok {
+				bounded = false
+			} else if ts.After(maxTS) {
+				maxTS = ts
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	if bounded {
+		block.MaxTS = NormalizeTimestamp(maxTS)
+	}
+	return block, nil
+}
+
+// entryTimestamp extracts the timestamp of any ledger entry: registers and
+// anchors carry it at the top level, seals inside their manifest. The header
+// has none and counts as the zero time.
+func entryTimestamp(line []byte) (time.Time, bool) {
+	var entry struct {
+		Type      string `json:"type"`
+		Timestamp string `json:"timestamp"`
+		Manifest  struct {
+			Timestamp string `json:"timestamp"`
+		} `json:"manifest"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return time.Time{}, false
+	}
+	if entry.Type == "header" {
+		// Undated, and never matched by a time-filtered query
+		return time.Time{}, true
+	}
+	raw := entry.Timestamp
+	if raw == "" {
+		raw = entry.Manifest.Timestamp
+	}
+	ts, err := ParseCanonTimestamp(raw)
+	return ts, err == nil
+}
+
+// writeIndexBlocks appends blocks to the sidecar, or replaces it when appendTo is false
+func writeIndexBlocks(path string, blocks []indexBlock, appendTo bool) error {
+	var buf bytes.Buffer
+	for _, b := range blocks {
+		line, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	if !appendTo {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, buf.Bytes(), os.FileMode(ledgerFileMode.Load())); err != nil {
+			return fmt.Errorf("%w: failed to write index: %v", ErrLedgerIO, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return fmt.Errorf("%w: failed to replace index: %v", ErrLedgerIO, err)
+		}
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("%w: failed to open index: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("%w: failed to write index: %v", ErrLedgerIO, err)
+	}
+	return nil
+}
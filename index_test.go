@@ -0,0 +1,163 @@
+package ledger
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// setupIndexedLedger enables index maintenance with small blocks
+func setupIndexedLedger(t *testing.T) string {
+	t.Helper()
+	path := setupTestLedger(t)
+
+	prevBlock := indexBlockBytes
+	indexBlockBytes = 1024
+	SetIndexing(true)
+	t.Cleanup(func() {
+		indexBlockBytes = prevBlock
+		SetIndexing(false)
+	})
+	return path
+}
+
+// listLinear runs ListRegistersSince with the sidecar moved out of the way
+func listLinear(t *testing.T, path string, since time.Time) []RegisterEntry {
+	t.Helper()
+	idx := IndexPath(path)
+	if err := os.Rename(idx, idx+".off"); err != nil {
+		t.Fatalf("failed to hide index: %v", err)
+	}
+	defer os.Rename(idx+".off", idx)
+
+	regs, err := ListRegistersSince(since)
+	if err != nil {
+		t.Fatalf("linear ListRegistersSince failed: %v", err)
+	}
+	return regs
+}
+
+func TestIndex_MatchesLinearScan(t *testing.T) {
+	path := setupIndexedLedger(t)
+	buildSealedLedger(t, 10, 7, 12)
+
+	for i := 100; i < 104; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	// A register older than the ones before it in the file, as a ledger written
+	// before AppendRegisterWithTimestamp enforced ordering may hold
+	sealTS := mustLastSealTimestamp(t)
+	entry, err := newRegisterEntry(testHash(104), nil, sealTS.Add(time.Nanosecond))
+	if err != nil {
+		t.Fatalf("newRegisterEntry failed: %v", err)
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("failed to marshal register: %v", err)
+	}
+	appendRaw(t, path, string(line)+"\n")
+
+	all, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(all) != 34 {
+		t.Fatalf("expected 34 registers, got %d", len(all))
+	}
+
+	fs := &FileStore{Path: path}
+	skipped := false
+	for _, reg := range all {
+		since, _ := time.Parse(time.RFC3339Nano, reg.Timestamp)
+
+		indexed, err := ListRegistersSince(since)
+		if err != nil {
+			t.Fatalf("indexed ListRegistersSince failed: %v", err)
+		}
+		if linear := listLinear(t, path, since); !reflect.DeepEqual(indexed, linear) {
+			t.Fatalf("since %s: indexed %d registers, linear %d", reg.Timestamp, len(indexed), len(linear))
+		}
+
+		if offset, _, err := fs.Seek(since); err != nil {
+			t.Fatalf("Seek failed: %v", err)
+		} else if offset > 0 {
+			skipped = true
+		}
+	}
+	if !skipped {
+		t.Fatalf("index never allowed a scan to skip ahead")
+	}
+}
+
+func TestIndex_RebuildDeleted(t *testing.T) {
+	path := setupIndexedLedger(t)
+	buildSealedLedger(t, 15, 15)
+
+	built, err := os.ReadFile(IndexPath(path))
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	if len(built) == 0 {
+		t.Fatalf("expected appends to maintain a non-empty index")
+	}
+
+	if err := os.Remove(IndexPath(path)); err != nil {
+		t.Fatalf("failed to delete index: %v", err)
+	}
+	// Without a sidecar, appends do not start one mid-ledger and queries still work
+	if err := AppendRegister(testHash(200), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if _, err := os.Stat(IndexPath(path)); !os.IsNotExist(err) {
+		t.Fatalf("index should stay missing until rebuilt, stat err=%v", err)
+	}
+	if regs, err := ListPendingRegisters(); err != nil || len(regs) != 1 {
+		t.Fatalf("ListPendingRegisters = %d, %v; want 1 register", len(regs), err)
+	}
+
+	if err := BuildIndex(); err != nil {
+		t.Fatalf("BuildIndex failed: %v", err)
+	}
+	rebuilt, err := os.ReadFile(IndexPath(path))
+	if err != nil {
+		t.Fatalf("failed to read rebuilt index: %v", err)
+	}
+	if string(rebuilt[:len(built)]) != string(built) {
+		t.Fatalf("rebuilt index does not extend the incrementally built one:\n%s\nvs\n%s", rebuilt, built)
+	}
+
+	since := time.Now().Add(-time.Hour)
+	indexed, err := ListRegistersSince(since)
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if linear := listLinear(t, path, since); !reflect.DeepEqual(indexed, linear) {
+		t.Fatalf("rebuilt index: indexed %d registers, linear %d", len(indexed), len(linear))
+	}
+}
+
+func TestIndex_StaleIsIgnored(t *testing.T) {
+	path := setupIndexedLedger(t)
+	buildSealedLedger(t, 20)
+
+	// Replace the ledger behind the index's back with a shorter one
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatalf("failed to truncate ledger: %v", err)
+	}
+	SetIndexing(false)
+	if err := AppendRegister(testHash(300), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	offset, lineNum, err := (&FileStore{Path: path}).Seek(time.Now())
+	if err != nil || offset != 0 || lineNum != 1 {
+		t.Fatalf("Seek on stale index = %d, %d, %v; want 0, 1, nil", offset, lineNum, err)
+	}
+	if regs, err := ListRegistersSince(time.Time{}); err != nil || len(regs) != 1 {
+		t.Fatalf("ListRegistersSince = %d, %v; want 1 register", len(regs), err)
+	}
+}
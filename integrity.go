@@ -0,0 +1,308 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// IntegrityCategory classifies an integrity violation
+type IntegrityCategory string
+
+const (
+	// IntegrityCorrupt marks a line that is not a parseable ledger entry
+	IntegrityCorrupt IntegrityCategory = "corrupt"
+
+	// IntegrityOutOfOrder marks an entry whose timestamp precedes the previous entry's
+	IntegrityOutOfOrder IntegrityCategory = "out_of_order"
+
+	// IntegrityRootMismatch marks a seal whose merkle_root differs from the root
+	// rebuilt over the registers of its epoch
+	IntegrityRootMismatch IntegrityCategory = "root_mismatch"
+
+	// IntegrityBadSignature marks a seal whose signature does not verify over its merkle_root
+	IntegrityBadSignature IntegrityCategory = "bad_signature"
+
+	// IntegrityBrokenAnchor marks a seal whose prev_seal_root is not the previous seal's merkle_root
+	IntegrityBrokenAnchor IntegrityCategory = "broken_anchor"
+
+	// IntegrityUncoveredRegister marks a register that sits inside a sealed epoch
+	// but was left out of the tree the seal signed ("silent drop")
+	IntegrityUncoveredRegister IntegrityCategory = "uncovered_register"
+)
+
+// IntegrityViolation describes a single failed check
+type IntegrityViolation struct {
+	Category IntegrityCategory `json:"category"`
+	LineNum  int               `json:"line"`
+	Detail   string            `json:"detail"`
+}
+
+// EpochIntegrity is the outcome of the checks run against a single seal
+type EpochIntegrity struct {
+	EpochID       int  `json:"epoch_id"`
+	LineNum       int  `json:"line"`
+	RootOK        bool `json:"root_ok"`
+	SignatureOK   bool `json:"signature_ok"`
+	AnchorOK      bool `json:"anchor_ok"`
+	RegisterCount int  `json:"register_count"`
+}
+
+// IntegrityReport is the result of a full ledger audit
+type IntegrityReport struct {
+	Valid      bool                 `json:"valid"`
+	Registers  int                  `json:"registers"`
+	Seals      int                  `json:"seals"`
+	Epochs     []EpochIntegrity     `json:"epochs"`
+	Violations []IntegrityViolation `json:"violations"`
+}
+
+// CheckIntegrity audits the whole ledger in a single pass.
+//
+// For every seal it rebuilds the Merkle root over the registers appended since
+// the previous seal (file order) and verifies the manifest signature over that
+// root, and that its prev_seal_root anchors it to the previous seal. A rotated
+// ledger starts from its anchor entry instead of genesis. When a root does not
+// match, each register of the epoch is tested for exclusion from the signed
+// tree and flagged if the seal covers the epoch without it. It also flags
+// unparseable lines and timestamps that go backwards.
+// Registers after the last seal are pending and are not checked against a root.
+// Every seal also gets an EpochIntegrity entry in file order.
+//
+// Violations are reported in the IntegrityReport, not as an error: the error
+// is reserved for I/O failures that prevent the audit from running.
+func CheckIntegrity() (IntegrityReport, error) {
+	return CheckIntegrityCtx(context.Background())
+}
+
+// CheckIntegrityCtx is CheckIntegrity that stops the audit once ctx is done,
+// returning the partial report and an error that wraps ctx.Err().
+func CheckIntegrityCtx(ctx context.Context) (IntegrityReport, error) {
+	report := IntegrityReport{Epochs: []EpochIntegrity{}, Violations: []IntegrityViolation{}}
+
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	flag := func(category IntegrityCategory, lineNum int, format string, args ...interface{}) {
+		report.Violations = append(report.Violations, IntegrityViolation{
+			Category: category,
+			LineNum:  lineNum,
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	var epochLeaves []string
+	var epochLines []int
+	var prevTS time.Time
+	prevRoot := config.GenesisPrevHash
+	nextEpoch := 0
+	lineNum := 0
+	entries := 0
+	hasHeader := false
+
+	err := currentStore().Iterate(func(line []byte) error {
+		if lineNum%ctxCheckLines == 0 {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("scan aborted at line %d: %w", lineNum+1, err)
+			}
+		}
+		lineNum++
+		if len(line) == 0 {
+			return nil
+		}
+
+		var entry struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			flag(IntegrityCorrupt, lineNum, "invalid JSON: %v", err)
+			return nil
+		}
+		entries++
+
+		switch entry.Type {
+		case "register":
+			reg, ts, err := parseRegister(lineNum, line)
+			if err != nil {
+				flag(IntegrityCorrupt, lineNum, "%v", err)
+				return nil
+			}
+			if ts.Before(prevTS) {
+				flag(IntegrityOutOfOrder, lineNum, "register timestamp %s precedes previous entry", reg.Timestamp)
+			}
+			prevTS = ts
+			report.Registers++
+			epochLeaves = append(epochLeaves, reg.ObjectHashHex)
+			epochLines = append(epochLines, lineNum)
+
+		case "seal":
+			var seal SealEntry
+			if err := json.Unmarshal(line, &seal); err != nil {
+				flag(IntegrityCorrupt, lineNum, "invalid seal entry: %v", err)
+				return nil
+			}
+			ts, err := ParseCanonTimestamp(seal.Manifest.Timestamp)
+			if err != nil {
+				flag(IntegrityCorrupt, lineNum, "invalid seal timestamp: %v", err)
+				return nil
+			}
+			if ts.Before(prevTS) {
+				flag(IntegrityOutOfOrder, lineNum, "seal timestamp %s precedes previous entry", seal.Manifest.Timestamp)
+			}
+			prevTS = ts
+			if seal.Manifest.EpochID != nextEpoch {
+				flag(IntegrityOutOfOrder, lineNum, "seal epoch_id %d, expected %d", seal.Manifest.EpochID, nextEpoch)
+			}
+			nextEpoch = seal.Manifest.EpochID + 1
+			anchorOK := seal.Manifest.PrevSealRoot == prevRoot
+			if !anchorOK {
+				flag(IntegrityBrokenAnchor, lineNum, "prev_seal_root %q, previous seal root %q", seal.Manifest.PrevSealRoot, prevRoot)
+			}
+			prevRoot = seal.Manifest.MerkleRoot
+			report.Seals++
+
+			rootOK, signatureOK := checkSeal(seal.Manifest, epochLeaves, epochLines, lineNum, flag)
+			report.Epochs = append(report.Epochs, EpochIntegrity{
+				EpochID:       seal.Manifest.EpochID,
+				LineNum:       lineNum,
+				RootOK:        rootOK,
+				SignatureOK:   signatureOK,
+				AnchorOK:      anchorOK,
+				RegisterCount: len(epochLeaves),
+			})
+			epochLeaves, epochLines = nil, nil
+
+		case "header":
+			if entries != 1 {
+				flag(IntegrityCorrupt, lineNum, "header is not the first entry of the ledger")
+			}
+			hasHeader = true
+
+		case "anchor":
+			// A rotation anchor continues the previous file's chain and must open
+			// the file, after the header if there is one
+			anchor, ts, err := parseAnchor(lineNum, line)
+			if err != nil {
+				flag(IntegrityCorrupt, lineNum, "%v", err)
+				return nil
+			}
+			if first := entries == 1 || (hasHeader && entries == 2); !first {
+				flag(IntegrityBrokenAnchor, lineNum, "anchor is not the first entry of the ledger")
+			}
+			prevTS = ts
+			prevRoot = anchor.PrevHash
+			nextEpoch = anchor.PrevEpochID + 1
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Valid = len(report.Violations) == 0
+	return report, nil
+}
+
+// checkSeal verifies a seal's root against its epoch leaves and its signature over that root.
+// leafLines holds the line number of each leaf. It reports whether the root and the signature held.
+func checkSeal(m Manifest, leaves []string, leafLines []int, lineNum int, flag func(IntegrityCategory, int, string, ...interface{})) (rootOK, signatureOK bool) {
+	if len(leaves) == 0 {
+		flag(IntegrityRootMismatch, lineNum, "seal covers no registers")
+	} else if root, err := merkle.BuildRoot(leaves); err != nil {
+		flag(IntegrityRootMismatch, lineNum, "cannot rebuild epoch root: %v", err)
+	} else if root != m.MerkleRoot {
+		flag(IntegrityRootMismatch, lineNum, "merkle_root %s, rebuilt %s over %d registers", m.MerkleRoot, root, len(leaves))
+		checkCoverage(m, leaves, leafLines, lineNum, flag)
+	} else if err := m.CheckTreeSize(len(leaves)); err != nil {
+		flag(IntegrityRootMismatch, lineNum, "%v", err)
+	} else {
+		rootOK = true
+	}
+
+	signatureOK, err := VerifyManifestSignature(m)
+	if !signatureOK {
+		flag(IntegrityBadSignature, lineNum, "signature does not verify over merkle_root: %v", err)
+	}
+	return rootOK, signatureOK
+}
+
+// maxCoverageGap is how many dropped registers checkCoverage looks for when the
+// seal does not sign its leaf count, and coverageSearchBudget caps the root
+// rebuilds it spends on one seal
+const (
+	maxCoverageGap       = 3
+	coverageSearchBudget = 4096
+)
+
+// checkCoverage looks for registers the seal silently dropped: a set of
+// registers is uncovered if the epoch without them rebuilds exactly the sealed
+// merkle_root. A seal that signs its leaf_count fixes how many were dropped;
+// otherwise sets of up to maxCoverageGap are tried, smallest first. The search
+// gives up after coverageSearchBudget rebuilds, so a large gap in a large epoch
+// is reported as a root mismatch only. Only called after a root mismatch, so
+// the cost is paid on tampered ledgers only.
+func checkCoverage(m Manifest, leaves []string, leafLines []int, lineNum int, flag func(IntegrityCategory, int, string, ...interface{})) {
+	minGap, maxGap := 1, maxCoverageGap
+	if m.LeafCount > 0 {
+		minGap, maxGap = len(leaves)-m.LeafCount, len(leaves)-m.LeafCount
+	}
+	if maxGap > len(leaves)-1 {
+		maxGap = len(leaves) - 1
+	}
+	if minGap < 1 {
+		// Registers went missing rather than slipped in
+		return
+	}
+
+	budget := coverageSearchBudget
+	without := make([]string, 0, len(leaves))
+	for gap := minGap; gap <= maxGap && budget > 0; gap++ {
+		dropped := make([]int, gap)
+		for i := range dropped {
+			dropped[i] = i
+		}
+		for budget > 0 {
+			budget--
+			without = without[:0]
+			next := 0
+			for i, leaf := range leaves {
+				if next < gap && dropped[next] == i {
+					next++
+					continue
+				}
+				without = append(without, leaf)
+			}
+			if root, err := merkle.BuildRoot(without); err == nil && root == m.MerkleRoot {
+				for _, i := range dropped {
+					flag(IntegrityUncoveredRegister, leafLines[i], "register %s is not covered by the seal at line %d", leaves[i], lineNum)
+				}
+				return
+			}
+			if !nextCombination(dropped, len(leaves)) {
+				break
+			}
+		}
+	}
+}
+
+// nextCombination advances idx, a strictly ascending k-subset of [0, n), to the
+// next one in lexicographic order. It reports false after the last subset.
+func nextCombination(idx []int, n int) bool {
+	k := len(idx)
+	i := k - 1
+	for i >= 0 && idx[i] == n-k+i {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	idx[i]++
+	for j := i + 1; j < k; j++ {
+		idx[j] = idx[j-1] + 1
+	}
+	return true
+}
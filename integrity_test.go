@@ -0,0 +1,392 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/internal/ledgertest"
+	"github.com/olsencastillo051172/forged-lro/src/config"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// testSeedHex is the Ed25519 seed used to produce genuinely signed seals in tests
+const testSeedHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+// testHash returns a distinct valid 64-char hex hash for index i
+func testHash(i int) string {
+	return ComputeObjectHash([]byte(fmt.Sprintf("object-%d", i)))
+}
+
+// signedManifest computes the root over pending registers and signs the manifest
+func signedManifest(t *testing.T) Manifest {
+	t.Helper()
+	lastSeal, err := getLastSeal()
+	if err != nil {
+		t.Fatalf("getLastSeal failed: %v", err)
+	}
+	lastSealTS, err := getLastSealTimestamp()
+	if err != nil {
+		t.Fatalf("getLastSealTimestamp failed: %v", err)
+	}
+	root, count, err := ComputeEpochRoot(lastSealTS)
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	m, err := Manifest{
+		MerkleRoot:   root,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		Canon:        config.CanonVersion,
+		EpochID:      nextEpochID(lastSeal),
+		PrevSealRoot: prevSealRoot(lastSeal),
+		LeafCount:    count,
+	}.Sign(testSeedHex)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	return m
+}
+
+// buildSealedLedger appends epochs of registers, each closed by a genuinely signed seal
+func buildSealedLedger(t *testing.T, epochSizes ...int) {
+	t.Helper()
+	n := 0
+	for _, size := range epochSizes {
+		for i := 0; i < size; i++ {
+			if err := AppendRegister(testHash(n), nil); err != nil {
+				t.Fatalf("AppendRegister failed: %v", err)
+			}
+			n++
+		}
+		if err := AppendSeal(signedManifest(t)); err != nil {
+			t.Fatalf("AppendSeal failed: %v", err)
+		}
+	}
+}
+
+// hasCategory reports whether the report contains a violation of the given category
+func hasCategory(report IntegrityReport, category IntegrityCategory) bool {
+	for _, v := range report.Violations {
+		if v.Category == category {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCheckIntegrity_CleanLedger(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 3, 2)
+
+	// A pending register is not a violation
+	if err := AppendRegister(testHash(99), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !report.Valid {
+		t.Fatalf("expected valid ledger, got %+v", report.Violations)
+	}
+	if report.Registers != 6 || report.Seals != 2 {
+		t.Errorf("Registers/Seals = %d/%d, want 6/2", report.Registers, report.Seals)
+	}
+}
+
+func TestCheckIntegrity_PerEpochResults(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 3, 2)
+	if err := AppendRegister(testHash(99), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	// Epoch 2 signs over a root that leaves its register out
+	m := signedManifest(t)
+	m.MerkleRoot = testHash(100)
+	m, _ = m.Sign(testSeedHex)
+	if err := AppendSeal(m); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	want := []EpochIntegrity{
+		{EpochID: 0, LineNum: 4, RootOK: true, SignatureOK: true, AnchorOK: true, RegisterCount: 3},
+		{EpochID: 1, LineNum: 7, RootOK: true, SignatureOK: true, AnchorOK: true, RegisterCount: 2},
+		{EpochID: 2, LineNum: 9, RootOK: false, SignatureOK: true, AnchorOK: true, RegisterCount: 1},
+	}
+	if len(report.Epochs) != len(want) {
+		t.Fatalf("Epochs = %+v, want %d entries", report.Epochs, len(want))
+	}
+	for i := range want {
+		if report.Epochs[i] != want[i] {
+			t.Errorf("Epochs[%d] = %+v, want %+v", i, report.Epochs[i], want[i])
+		}
+	}
+}
+
+func TestCheckIntegrity_MissingLedger(t *testing.T) {
+	setupTestLedger(t)
+
+	report, err := CheckIntegrity()
+	if err != nil || !report.Valid {
+		t.Fatalf("expected valid empty report, got %+v err=%v", report, err)
+	}
+}
+
+func TestCheckIntegrity_BadSignature(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	// validManifest carries a placeholder signature and an unrelated root
+	if err := AppendSeal(validManifest()); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if report.Valid || !hasCategory(report, IntegrityBadSignature) || !hasCategory(report, IntegrityRootMismatch) {
+		t.Errorf("expected bad_signature and root_mismatch, got %+v", report.Violations)
+	}
+}
+
+func TestCheckIntegrity_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		kind ledgertest.TamperKind
+		want IntegrityCategory
+	}{
+		{kind: ledgertest.TamperFlipHashByte, want: IntegrityRootMismatch},
+		{kind: ledgertest.TamperReorderLines, want: IntegrityOutOfOrder},
+		{kind: ledgertest.TamperDeleteLine, want: IntegrityRootMismatch},
+		{kind: ledgertest.TamperGarbleLine, want: IntegrityCorrupt},
+	}
+
+	for _, tt := range tests {
+		for seed := int64(0); seed < 5; seed++ {
+			t.Run(fmt.Sprintf("%s/seed=%d", tt.kind, seed), func(t *testing.T) {
+				path := setupTestLedger(t)
+				buildSealedLedger(t, 4, 3)
+
+				if err := ledgertest.SimulateTamper(path, tt.kind, seed); err != nil {
+					t.Fatalf("SimulateTamper failed: %v", err)
+				}
+
+				report, err := CheckIntegrity()
+				if err != nil {
+					t.Fatalf("CheckIntegrity failed: %v", err)
+				}
+				if report.Valid {
+					t.Fatalf("tampering went undetected")
+				}
+				if !hasCategory(report, tt.want) {
+					t.Errorf("expected %s violation, got %+v", tt.want, report.Violations)
+				}
+			})
+		}
+	}
+}
+
+// readSeals returns every seal in the ledger, in file order
+func readSeals(t *testing.T) []SealEntry {
+	t.Helper()
+	var seals []SealEntry
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "seal" {
+			return nil
+		}
+		seal, _, err := parseSeal(lineNum, line)
+		seals = append(seals, seal)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("scanLedger failed: %v", err)
+	}
+	return seals
+}
+
+func TestAppendSeal_AnchorsChain(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2, 1, 3)
+
+	seals := readSeals(t)
+	if len(seals) != 3 {
+		t.Fatalf("expected 3 seals, got %d", len(seals))
+	}
+	if seals[0].Manifest.PrevSealRoot != config.GenesisPrevHash {
+		t.Errorf("first seal prev_seal_root = %q, want genesis %q", seals[0].Manifest.PrevSealRoot, config.GenesisPrevHash)
+	}
+	for i := 1; i < len(seals); i++ {
+		if seals[i].Manifest.PrevSealRoot != seals[i-1].Manifest.MerkleRoot {
+			t.Errorf("seal %d prev_seal_root = %s, want %s", i, seals[i].Manifest.PrevSealRoot, seals[i-1].Manifest.MerkleRoot)
+		}
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil || !report.Valid {
+		t.Fatalf("expected valid chain, got %+v err=%v", report.Violations, err)
+	}
+}
+
+func TestAppendSeal_RejectsWrongAnchor(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1)
+
+	if err := AppendRegister(testHash(50), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	m := signedManifest(t)
+	m.PrevSealRoot = testHash(51)
+	if err := AppendSeal(m); !errors.Is(err, ErrBrokenAnchor) {
+		t.Fatalf("expected ErrBrokenAnchor, got %v", err)
+	}
+}
+
+func TestCheckIntegrity_BrokenAnchor(t *testing.T) {
+	path := setupTestLedger(t)
+	buildSealedLedger(t, 2, 1, 3)
+
+	// Rewrite the middle seal so it anchors to an unrelated root
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	sealLines := 0
+	for i, line := range lines {
+		var seal SealEntry
+		if err := json.Unmarshal([]byte(line), &seal); err != nil || seal.Type != "seal" {
+			continue
+		}
+		sealLines++
+		if sealLines == 2 {
+			seal.Manifest.PrevSealRoot = testHash(77)
+			rewritten, err := json.Marshal(seal)
+			if err != nil {
+				t.Fatalf("failed to marshal seal: %v", err)
+			}
+			lines[i] = string(rewritten)
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if report.Valid || !hasCategory(report, IntegrityBrokenAnchor) {
+		t.Fatalf("expected broken_anchor violation, got %+v", report.Violations)
+	}
+	// Only the rewritten seal is flagged; the third still anchors to the real second root
+	for _, v := range report.Violations {
+		if v.Category == IntegrityBrokenAnchor && v.LineNum != 5 {
+			t.Errorf("unexpected broken_anchor at line %d", v.LineNum)
+		}
+	}
+}
+
+// insertRegisters rewrites the ledger with, for each hash, a copy of the
+// register on line after carrying that hash, placed right after it, as if
+// slipped in after sealing
+func insertRegisters(t *testing.T, path string, after int, hashes ...string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	var reg RegisterEntry
+	if err := json.Unmarshal([]byte(lines[after-1]), &reg); err != nil {
+		t.Fatalf("failed to unmarshal register: %v", err)
+	}
+	var inserted []string
+	for _, h := range hashes {
+		reg.ObjectHashHex = h
+		raw, err := json.Marshal(reg)
+		if err != nil {
+			t.Fatalf("failed to marshal register: %v", err)
+		}
+		inserted = append(inserted, string(raw))
+	}
+	lines = append(lines[:after], append(inserted, lines[after:]...)...)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+}
+
+// uncoveredLines returns the lines reported as uncovered_register
+func uncoveredLines(t *testing.T) []int {
+	t.Helper()
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if report.Valid || !hasCategory(report, IntegrityRootMismatch) {
+		t.Fatalf("expected root_mismatch, got %+v", report.Violations)
+	}
+	var uncovered []int
+	for _, v := range report.Violations {
+		if v.Category == IntegrityUncoveredRegister {
+			uncovered = append(uncovered, v.LineNum)
+		}
+	}
+	return uncovered
+}
+
+func TestCheckIntegrity_UncoveredRegister(t *testing.T) {
+	path := setupTestLedger(t)
+	buildSealedLedger(t, 3, 2)
+
+	// Slip a register into the first epoch, after the tree was signed
+	insertRegisters(t, path, 2, testHash(66))
+
+	if uncovered := uncoveredLines(t); len(uncovered) != 1 || uncovered[0] != 3 {
+		t.Fatalf("expected uncovered_register at line 3 only, got %v", uncovered)
+	}
+}
+
+func TestCheckIntegrity_TwoUncoveredRegisters(t *testing.T) {
+	t.Run("signed leaf_count", func(t *testing.T) {
+		path := setupTestLedger(t)
+		buildSealedLedger(t, 4, 2)
+
+		// Lines 1-4 are the first epoch: slip one register in after line 1 and one after line 3
+		insertRegisters(t, path, 3, testHash(67))
+		insertRegisters(t, path, 1, testHash(66))
+
+		if uncovered := uncoveredLines(t); !reflect.DeepEqual(uncovered, []int{2, 5}) {
+			t.Fatalf("expected uncovered_register at lines 2 and 5, got %v", uncovered)
+		}
+	})
+
+	t.Run("root-only signature", func(t *testing.T) {
+		path := setupTestLedger(t)
+		appendN(t, 0, 4)
+		m := signedManifest(t)
+		m.SigVersion, m.LeafCount = SigVersionRoot, 0
+		m.Signature, m.PublicKey, _ = sign.SignHashHex(m.MerkleRoot, testSeedHex)
+		if err := AppendSeal(m); err != nil {
+			t.Fatalf("AppendSeal failed: %v", err)
+		}
+
+		insertRegisters(t, path, 3, testHash(67))
+		insertRegisters(t, path, 1, testHash(66))
+
+		if uncovered := uncoveredLines(t); !reflect.DeepEqual(uncovered, []int{2, 5}) {
+			t.Fatalf("expected uncovered_register at lines 2 and 5, got %v", uncovered)
+		}
+	})
+}
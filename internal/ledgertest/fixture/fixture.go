@@ -0,0 +1,38 @@
+// Package fixture holds the ledger fixtures shared by the tests of the
+// commands built on the ledger package (cli/..., cmd/server).
+//
+// It lives apart from ledgertest because it imports the ledger package, which
+// the ledger's own tests cannot do through ledgertest without an import cycle.
+package fixture
+
+import (
+	"path/filepath"
+	"testing"
+
+	ledger "github.com/olsencastillo051172/forged-lro"
+)
+
+// TempLedger points the ledger at a fresh file and returns its path. The
+// previous path is restored when the test ends.
+func TempLedger(t testing.TB) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	previous := ledger.GetLedgerPath()
+	ledger.SetLedgerPath(path)
+	t.Cleanup(func() { ledger.SetLedgerPath(previous) })
+	return path
+}
+
+// Register appends one register per value, keyed by the value's object hash,
+// and returns those hashes in order.
+func Register(t testing.TB, vals ...string) []string {
+	t.Helper()
+	hashes := make([]string, len(vals))
+	for i, v := range vals {
+		hashes[i] = ledger.ComputeObjectHash([]byte(v))
+		if err := ledger.AppendRegister(hashes[i], nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,129 @@
+// Package ledgertest provides test-only helpers for exercising ledger integrity checks.
+//
+// The helpers operate on the raw JSONL file and never import the ledger package,
+// so they can be used from the ledger's own tests without an import cycle.
+// Fixtures that drive the ledger API itself are in ledgertest/fixture.
+package ledgertest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"regexp"
+)
+
+// TamperKind selects the mutation SimulateTamper applies.
+type TamperKind int
+
+const (
+	// TamperFlipHashByte changes one hex digit of a register's object_hash_hex.
+	TamperFlipHashByte TamperKind = iota
+
+	// TamperReorderLines swaps two lines of the ledger.
+	TamperReorderLines
+
+	// TamperDeleteLine removes one register line.
+	TamperDeleteLine
+
+	// TamperGarbleLine overwrites one line with bytes that are not JSON.
+	TamperGarbleLine
+)
+
+// ErrNothingToTamper is returned when the ledger has no line the mutation can target.
+var ErrNothingToTamper = errors.New("ledgertest: no eligible line to tamper")
+
+var objectHashField = regexp.MustCompile(`"object_hash_hex":"([a-f0-9]{64})"`)
+
+// String returns the mutation name.
+func (k TamperKind) String() string {
+	switch k {
+	case TamperFlipHashByte:
+		return "flip_hash_byte"
+	case TamperReorderLines:
+		return "reorder_lines"
+	case TamperDeleteLine:
+		return "delete_line"
+	case TamperGarbleLine:
+		return "garble_line"
+	default:
+		return fmt.Sprintf("TamperKind(%d)", int(k))
+	}
+}
+
+// SimulateTamper applies mutation to the ledger file at path.
+// The targeted line(s) are chosen by a PRNG seeded with seed, so the same
+// (ledger, mutation, seed) triple always produces the same tampered file.
+//
+// Hash flips and deletions only target register lines: removing or altering
+// the very last seal is indistinguishable from a shorter honest ledger.
+func SimulateTamper(path string, mutation TamperKind, seed int64) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ledgertest: read %s: %w", path, err)
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	rng := rand.New(rand.NewSource(seed))
+
+	var registers []int
+	for i, line := range lines {
+		if objectHashField.Match(line) {
+			registers = append(registers, i)
+		}
+	}
+
+	switch mutation {
+	case TamperFlipHashByte:
+		if len(registers) == 0 {
+			return ErrNothingToTamper
+		}
+		i := registers[rng.Intn(len(registers))]
+		loc := objectHashField.FindSubmatchIndex(lines[i])
+		pos := loc[2] + rng.Intn(64)
+
+		line := append([]byte(nil), lines[i]...)
+		line[pos] = flipHexDigit(line[pos])
+		lines[i] = line
+
+	case TamperReorderLines:
+		if len(lines) < 2 {
+			return ErrNothingToTamper
+		}
+		i := rng.Intn(len(lines) - 1)
+		j := i + 1 + rng.Intn(len(lines)-i-1)
+		lines[i], lines[j] = lines[j], lines[i]
+
+	case TamperDeleteLine:
+		if len(registers) == 0 {
+			return ErrNothingToTamper
+		}
+		i := registers[rng.Intn(len(registers))]
+		lines = append(lines[:i], lines[i+1:]...)
+
+	case TamperGarbleLine:
+		if len(lines) == 0 {
+			return ErrNothingToTamper
+		}
+		i := rng.Intn(len(lines))
+		lines[i] = []byte(fmt.Sprintf("garbage-%d", rng.Int63()))
+
+	default:
+		return fmt.Errorf("ledgertest: unknown mutation %v", mutation)
+	}
+
+	out := append(bytes.Join(lines, []byte("\n")), '\n')
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("ledgertest: write %s: %w", path, err)
+	}
+	return nil
+}
+
+// flipHexDigit returns a different lowercase hex digit.
+func flipHexDigit(c byte) byte {
+	if c == '0' {
+		return '1'
+	}
+	return '0'
+}
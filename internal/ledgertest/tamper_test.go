@@ -0,0 +1,53 @@
+package ledgertest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleLedger = `{"type":"register","canon":"v1.0","timestamp":"2026-01-10T00:00:01Z","object_hash_hex":"a665a45920422f9d417e4867efdc4fb8a04a1f3fff1fa07e998e86f7f7a27ae3"}
+{"type":"register","canon":"v1.0","timestamp":"2026-01-10T00:00:02Z","object_hash_hex":"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"}
+{"type":"register","canon":"v1.0","timestamp":"2026-01-10T00:00:03Z","object_hash_hex":"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"}
+`
+
+func tamperCopy(t *testing.T, kind TamperKind, seed int64) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	if err := os.WriteFile(path, []byte(sampleLedger), 0644); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+	if err := SimulateTamper(path, kind, seed); err != nil {
+		t.Fatalf("SimulateTamper(%s) failed: %v", kind, err)
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	return out
+}
+
+func TestSimulateTamper_DeterministicPerSeed(t *testing.T) {
+	for _, kind := range []TamperKind{TamperFlipHashByte, TamperReorderLines, TamperDeleteLine, TamperGarbleLine} {
+		a := tamperCopy(t, kind, 42)
+		b := tamperCopy(t, kind, 42)
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s: same seed produced different output", kind)
+		}
+		if bytes.Equal(a, []byte(sampleLedger)) {
+			t.Errorf("%s: ledger was not modified", kind)
+		}
+	}
+}
+
+func TestSimulateTamper_NothingToTamper(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	if err := os.WriteFile(path, []byte(`{"type":"seal"}`+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write ledger: %v", err)
+	}
+	if err := SimulateTamper(path, TamperDeleteLine, 1); !errors.Is(err, ErrNothingToTamper) {
+		t.Fatalf("expected ErrNothingToTamper, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// CanonicalizePolicy returns the deterministic byte representation of a policy.
+// It is a thin wrapper over hash.Canonicalize: keys are emitted in sorted order,
+// without insignificant whitespace and without a trailing newline, so two
+// semantically identical policies always hash the same.
+func CanonicalizePolicy(p *RotationPolicy) ([]byte, error) {
+	if p == nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: cannot canonicalize a nil policy")
+	}
+
+	b, err := hash.Canonicalize(p)
+	if err != nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: policy could not be canonicalized: %w", err)
+	}
+	return b, nil
+}
+
+// WritePolicy writes the CanonicalizePolicy bytes of p to path with 0644
+// permissions, exactly as canonicalized: no indentation, no trailing newline.
+// The file is replaced atomically (temp file in the same directory + rename),
+// so readers see either the old policy or the new one, never a partial write.
+// LoadPolicy -> WritePolicy -> LoadPolicy is byte-stable.
+func WritePolicy(path string, p *RotationPolicy) error {
+	b, err := CanonicalizePolicy(p)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".policy-*.tmp")
+	if err != nil {
+		return fmt.Errorf("AUDIT_FAIL: could not write policy file at %s: %w", path, err)
+	}
+	// Removing after a successful rename is a harmless no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("AUDIT_FAIL: could not write policy file at %s: %w", path, err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("AUDIT_FAIL: could not write policy file at %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("AUDIT_FAIL: could not write policy file at %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("AUDIT_FAIL: could not write policy file at %s: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("AUDIT_FAIL: could not replace policy file at %s: %w", path, err)
+	}
+	return nil
+}
@@ -2,7 +2,11 @@ package policy
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
 )
 
 func TestCanonicalizePolicy_Determinism(t *testing.T) {
@@ -37,3 +41,85 @@ func TestCanonicalizePolicy_Determinism(t *testing.T) {
 		t.Error("Format error: canonical bytes must not have a trailing newline")
 	}
 }
+
+func TestCanonicalizePolicy_MatchesGenericCanonicalizer(t *testing.T) {
+	p := validPolicy()
+
+	specific, err := CanonicalizePolicy(p)
+	if err != nil {
+		t.Fatalf("CanonicalizePolicy failed: %v", err)
+	}
+
+	generic, err := hash.Canonicalize(p)
+	if err != nil {
+		t.Fatalf("hash.Canonicalize failed: %v", err)
+	}
+
+	if !bytes.Equal(specific, generic) {
+		t.Errorf("outputs differ\nspecific: %s\ngeneric:  %s", specific, generic)
+	}
+}
+
+func TestWritePolicy_RoundTripIsByteStable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := WritePolicy(path, validPolicy()); err != nil {
+		t.Fatalf("WritePolicy failed: %v", err)
+	}
+	first, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read policy: %v", err)
+	}
+	if bytes.HasSuffix(first, []byte("\n")) {
+		t.Error("written policy must not end with a newline")
+	}
+	want, _ := CanonicalizePolicy(validPolicy())
+	if !bytes.Equal(first, want) {
+		t.Errorf("written bytes differ from CanonicalizePolicy\ngot:  %s\nwant: %s", first, want)
+	}
+
+	// Load -> Write -> Load must not change a single byte
+	loaded, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if err := WritePolicy(path, loaded); err != nil {
+		t.Fatalf("second WritePolicy failed: %v", err)
+	}
+	second, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read policy: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("round trip changed the file\nfirst:  %s\nsecond: %s", first, second)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 {
+		t.Errorf("permissions = %o, want 644", perm)
+	}
+}
+
+func TestWritePolicy_NoTempFilesLeft(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.json")
+	if err := WritePolicy(path, validPolicy()); err != nil {
+		t.Fatalf("WritePolicy failed: %v", err)
+	}
+	if err := WritePolicy(path, nil); err == nil {
+		t.Fatal("expected error for nil policy")
+	}
+	if err := WritePolicy(filepath.Join(dir, "missing", "policy.json"), validPolicy()); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "policy.json" {
+		t.Errorf("unexpected directory contents: %v", entries)
+	}
+}
@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldChange records a single difference between two policy versions.
+// Path is the dotted JSON field path (e.g. "epochs.interval_seconds").
+type FieldChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// DiffPolicies compares two policy versions field by field, reporting each
+// change as Old (from before) and New (from after).
+// Changes are reported in struct declaration order, so the output is stable
+// across runs and suitable for an approval trail. Identical policies yield an empty slice.
+func DiffPolicies(before, after *RotationPolicy) ([]FieldChange, error) {
+	if before == nil || after == nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: cannot diff a nil policy")
+	}
+
+	changes := []FieldChange{}
+	diffStruct("", reflect.ValueOf(*before), reflect.ValueOf(*after), &changes)
+	return changes, nil
+}
+
+// diffStruct walks every exported field, descending into nested structs and
+// treating any other kind (scalars, slices) as a single comparable value.
+func diffStruct(prefix string, a, b reflect.Value, out *[]FieldChange) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		path := jsonName(field)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+
+		av, bv := a.Field(i), b.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			diffStruct(path, av, bv, out)
+			continue
+		}
+
+		if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			*out = append(*out, FieldChange{Path: path, Old: av.Interface(), New: bv.Interface()})
+		}
+	}
+}
+
+// jsonName returns the JSON key of a struct field, falling back to the Go name.
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return f.Name
+}
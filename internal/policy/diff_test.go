@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"testing"
+)
+
+func TestDiffPolicies_Identical(t *testing.T) {
+	changes, err := DiffPolicies(validPolicy(), validPolicy())
+	if err != nil {
+		t.Fatalf("DiffPolicies failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected empty diff, got %+v", changes)
+	}
+}
+
+func TestDiffPolicies_ChangedInterval(t *testing.T) {
+	old := validPolicy()
+	updated := validPolicy()
+	updated.Epochs.IntervalSeconds = 172800
+
+	changes, err := DiffPolicies(old, updated)
+	if err != nil {
+		t.Fatalf("DiffPolicies failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d: %+v", len(changes), changes)
+	}
+
+	c := changes[0]
+	if c.Path != "epochs.interval_seconds" {
+		t.Errorf("Path = %s, want epochs.interval_seconds", c.Path)
+	}
+	if c.Old != 86400 || c.New != 172800 {
+		t.Errorf("Old/New = %v/%v, want 86400/172800", c.Old, c.New)
+	}
+}
+
+func TestDiffPolicies_ChangedDomainSeparator(t *testing.T) {
+	old := validPolicy()
+	updated := validPolicy()
+	updated.Constraints.DomainSeparator = "RVA_NODE:v2"
+
+	changes, err := DiffPolicies(old, updated)
+	if err != nil {
+		t.Fatalf("DiffPolicies failed: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %d: %+v", len(changes), changes)
+	}
+	if changes[0].Path != "constraints.domain_separator" {
+		t.Errorf("Path = %s, want constraints.domain_separator", changes[0].Path)
+	}
+	if changes[0].Old != "RVA_NODE:v1" || changes[0].New != "RVA_NODE:v2" {
+		t.Errorf("Old/New = %v/%v", changes[0].Old, changes[0].New)
+	}
+}
+
+func TestDiffPolicies_StableOrder(t *testing.T) {
+	old := validPolicy()
+	updated := validPolicy()
+	updated.Cutover.StrictMonotonicEpoch = false
+	updated.Issuer.Name = "Beta"
+	updated.Constraints.AllowedHashAlgs = []string{"sha256", "sha512"}
+
+	changes, err := DiffPolicies(old, updated)
+	if err != nil {
+		t.Fatalf("DiffPolicies failed: %v", err)
+	}
+
+	want := []string{"issuer.name", "constraints.allowed_hash_algs", "cutover.strict_monotonic_epoch"}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, path := range want {
+		if changes[i].Path != path {
+			t.Errorf("changes[%d].Path = %s, want %s", i, changes[i].Path, path)
+		}
+	}
+}
+
+func TestDiffPolicies_Nil(t *testing.T) {
+	if _, err := DiffPolicies(nil, validPolicy()); err == nil {
+		t.Fatalf("expected error for nil policy")
+	}
+}
@@ -3,16 +3,43 @@ package policy
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
 )
 
+// DefaultMaxPolicySize is the largest policy file LoadPolicy accepts unless overridden.
+const DefaultMaxPolicySize int64 = 1 << 20 // 1 MiB
+
+var (
+	maxPolicySize        = DefaultMaxPolicySize
+	rejectPolicySymlinks = false
+)
+
+// SetMaxPolicySize changes the size limit enforced by LoadPolicy (bytes, must be positive).
+func SetMaxPolicySize(n int64) {
+	if n > 0 {
+		maxPolicySize = n
+	}
+}
+
+// SetRejectPolicySymlinks makes LoadPolicy refuse a policy path that is a symbolic link.
+func SetRejectPolicySymlinks(reject bool) {
+	rejectPolicySymlinks = reject
+}
+
 // LoadPolicy reads the rotation policy from a file and deserializes it.
 // It performs basic syntax and structural checks during the JSON unmarshaling process.
+//
+// The path may come from the environment, so only regular files up to the
+// configured size limit are read, and symlinks are refused if so configured.
 func LoadPolicy(path string) (*RotationPolicy, error) {
-	// 1. Physical Read: Ensure the file is accessible
-	data, err := os.ReadFile(path)
+	// 1. Physical Read: Ensure the file is accessible, regular and bounded
+	data, err := readPolicyFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("AUDIT_FAIL: could not read policy file at %s: %w", path, err)
+		return nil, err
 	}
 
 	// 2. Deserialization: Map JSON to our strictly typed structs
@@ -26,5 +53,67 @@ func LoadPolicy(path string) (*RotationPolicy, error) {
 		return nil, fmt.Errorf("AUDIT_FAIL: policy file is empty or missing mandatory version field")
 	}
 
+	// 4. Canon Check: A policy written for another canon major must not be processed by this binary
+	if err := RequireCanonVersion(pol.PolicyVersion); err != nil {
+		return nil, err
+	}
+
 	return &pol, nil
 }
+
+// readPolicyFile reads path without ever loading more than maxPolicySize bytes.
+func readPolicyFile(path string) ([]byte, error) {
+	if rejectPolicySymlinks {
+		info, err := os.Lstat(path)
+		if err != nil {
+			return nil, fmt.Errorf("AUDIT_FAIL: could not read policy file at %s: %w", path, err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil, fmt.Errorf("AUDIT_FAIL: policy file at %s is a symlink, refusing to follow it", path)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: could not read policy file at %s: %w", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: could not read policy file at %s: %w", path, err)
+	}
+	// Devices and pipes (e.g. /dev/zero) have no meaningful size and may never end
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("AUDIT_FAIL: policy path %s is not a regular file", path)
+	}
+	if info.Size() > maxPolicySize {
+		return nil, fmt.Errorf("AUDIT_FAIL: policy file at %s is %d bytes, limit is %d", path, info.Size(), maxPolicySize)
+	}
+
+	// The file may grow between Stat and Read: never read past the limit
+	data, err := io.ReadAll(io.LimitReader(f, maxPolicySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("AUDIT_FAIL: could not read policy file at %s: %w", path, err)
+	}
+	if int64(len(data)) > maxPolicySize {
+		return nil, fmt.Errorf("AUDIT_FAIL: policy file at %s exceeds the %d byte limit", path, maxPolicySize)
+	}
+	return data, nil
+}
+
+// RequireCanonVersion rejects a version whose major differs from config.CanonVersion.
+// Both "1.0" and "v1.0" forms are accepted; minor revisions within the same major are allowed.
+func RequireCanonVersion(version string) error {
+	got, want := majorVersion(version), majorVersion(config.CanonVersion)
+	if got == "" || got != want {
+		return fmt.Errorf("AUDIT_FAIL: policy_version %q targets canon major %q, this binary implements %s", version, got, config.CanonVersion)
+	}
+	return nil
+}
+
+// majorVersion returns the component before the first dot, without a leading "v"
+func majorVersion(version string) string {
+	major, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	return major
+}
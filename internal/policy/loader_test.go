@@ -0,0 +1,108 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePolicyFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rotation_policy.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write policy: %v", err)
+	}
+	return path
+}
+
+func TestLoadPolicy_CanonVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{"matching major", "1.0", false},
+		{"matching major with v prefix", "v1.0", false},
+		{"minor revision", "1.3", false},
+		{"mismatched major", "2.0", true},
+		{"mismatched major with v prefix", "v2.1", true},
+		{"non-numeric", "beta", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePolicyFile(t, `{"policy_version": "`+tt.version+`"}`)
+			_, err := LoadPolicy(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error for policy_version %q", tt.version)
+				}
+				if !strings.Contains(err.Error(), "AUDIT_FAIL") || !strings.Contains(err.Error(), "canon major") {
+					t.Errorf("unexpected error message: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy_NormalPolicy(t *testing.T) {
+	path := writePolicyFile(t, `{"policy_version": "1.0", "issuer": {"name": "Alpha", "id": "rva://1"}}`)
+
+	pol, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy failed: %v", err)
+	}
+	if pol.Issuer.Name != "Alpha" {
+		t.Errorf("Issuer.Name = %q, want Alpha", pol.Issuer.Name)
+	}
+}
+
+func TestLoadPolicy_Oversized(t *testing.T) {
+	padding := strings.Repeat(" ", int(DefaultMaxPolicySize))
+	path := writePolicyFile(t, `{"policy_version": "1.0"}`+padding)
+
+	_, err := LoadPolicy(path)
+	if err == nil || !strings.Contains(err.Error(), "AUDIT_FAIL") || !strings.Contains(err.Error(), "limit") {
+		t.Fatalf("expected AUDIT_FAIL size error, got %v", err)
+	}
+}
+
+func TestLoadPolicy_ConfigurableLimit(t *testing.T) {
+	SetMaxPolicySize(16)
+	t.Cleanup(func() { SetMaxPolicySize(DefaultMaxPolicySize) })
+
+	path := writePolicyFile(t, `{"policy_version": "1.0"}`)
+	if _, err := LoadPolicy(path); err == nil || !strings.Contains(err.Error(), "limit is 16") {
+		t.Fatalf("expected size error with 16 byte limit, got %v", err)
+	}
+}
+
+func TestLoadPolicy_RejectsNonRegularFile(t *testing.T) {
+	if _, err := LoadPolicy(t.TempDir()); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Fatalf("expected non-regular file error, got %v", err)
+	}
+}
+
+func TestLoadPolicy_Symlinks(t *testing.T) {
+	target := writePolicyFile(t, `{"policy_version": "1.0"}`)
+	link := filepath.Join(t.TempDir(), "link.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	// Followed by default
+	if _, err := LoadPolicy(link); err != nil {
+		t.Fatalf("LoadPolicy through symlink failed: %v", err)
+	}
+
+	SetRejectPolicySymlinks(true)
+	t.Cleanup(func() { SetRejectPolicySymlinks(false) })
+	if _, err := LoadPolicy(link); err == nil || !strings.Contains(err.Error(), "symlink") {
+		t.Fatalf("expected symlink rejection, got %v", err)
+	}
+}
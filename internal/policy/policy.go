@@ -0,0 +1,43 @@
+// Package policy loads and validates the RVA rotation policy ("the constitution").
+// The policy is a governance document: it declares who issues epochs, how often
+// they rotate and which cryptographic constraints every seal must respect.
+package policy
+
+// RotationPolicy is the strictly typed representation of the rotation policy file.
+type RotationPolicy struct {
+	PolicyVersion string            `json:"policy_version"`
+	Issuer        IssuerInfo        `json:"issuer"`
+	Epochs        EpochConfig       `json:"epochs"`
+	Constraints   CryptoConstraints `json:"constraints"`
+	Cutover       CutoverRules      `json:"cutover"`
+}
+
+// IssuerInfo identifies the authority that signs epoch manifests.
+type IssuerInfo struct {
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// EpochConfig governs epoch timing and identifier format.
+type EpochConfig struct {
+	IntervalSeconds int    `json:"interval_seconds"`
+	IDFormat        string `json:"epoch_id_format"`
+}
+
+// CryptoConstraints pins the hashing rules and Merkle tree boundaries.
+type CryptoConstraints struct {
+	HashAlg         string   `json:"hash_alg"`
+	AllowedHashAlgs []string `json:"allowed_hash_algs"`
+	DomainSeparator string   `json:"domain_separator"`
+	MinDepth        int      `json:"min_depth"`
+	MaxDepth        int      `json:"max_depth"`
+
+	// AllowedIssuers whitelists Issuer.ID; empty allows any issuer
+	AllowedIssuers []string `json:"allowed_issuers,omitempty"`
+}
+
+// CutoverRules defines how one epoch hands over to the next.
+type CutoverRules struct {
+	RequirePrevAnchor    bool `json:"require_prev_anchor"`
+	StrictMonotonicEpoch bool `json:"strict_monotonic_epoch"`
+}
@@ -3,51 +3,120 @@ package policy
 import (
 	"errors"
 	"fmt"
+	"slices"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
 )
 
+// canonVersion is the canon the domain separator is derived from (replaced in tests)
+var canonVersion = config.CanonVersion
+
+// AllowedAlgorithms is the maintained set of hash algorithms a policy may activate.
+// Extending it is a protocol decision, not a policy one.
+var AllowedAlgorithms = map[string]bool{
+	"sha256": true,
+	"sha512": true,
+}
+
+// weakHashAlgorithms are rejected by name so they can never slip in through a typo-tolerant reader.
+var weakHashAlgorithms = map[string]bool{
+	"md5":  true,
+	"sha1": true,
+}
+
 // ValidateInvariants enforces the technical and legal boundaries of the policy.
 // It ensures that the loaded configuration strictly adheres to RVA standards.
+// It fails fast: only the first violation is returned.
 func ValidateInvariants(p *RotationPolicy) error {
+	if errs := ValidateInvariantsAll(p); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// ValidateInvariantsAll runs every invariant check and returns all violations,
+// in the same order ValidateInvariants would encounter them (empty if clean).
+// Checks that only restate an earlier failure of the same field are skipped.
+func ValidateInvariantsAll(p *RotationPolicy) []error {
+	errs := []error{}
+	fail := func(err error) { errs = append(errs, err) }
+
 	// 1. Cryptographic Invariants
-	if p.Constraints.HashAlg != "sha256" {
-		return fmt.Errorf("AUDIT_FAIL: hash_alg '%s' is not supported (required: sha256)", p.Constraints.HashAlg)
+	if weakHashAlgorithms[p.Constraints.HashAlg] {
+		fail(fmt.Errorf("AUDIT_FAIL: hash_alg '%s' is cryptographically broken and explicitly rejected", p.Constraints.HashAlg))
+	} else if !AllowedAlgorithms[p.Constraints.HashAlg] {
+		fail(fmt.Errorf("AUDIT_FAIL: hash_alg '%s' is not supported (approved: sha256, sha512)", p.Constraints.HashAlg))
 	}
 
-	foundAlg := false
 	for _, alg := range p.Constraints.AllowedHashAlgs {
-		if alg == "sha256" {
-			foundAlg = true
-			break
+		if weakHashAlgorithms[alg] {
+			fail(fmt.Errorf("AUDIT_FAIL: allowed_hash_algs contains broken algorithm '%s'", alg))
 		}
 	}
-	if !foundAlg {
-		return errors.New("AUDIT_FAIL: sha256 must be present in allowed_hash_algs")
+	if !containsAlg(p.Constraints.AllowedHashAlgs, "sha256") {
+		fail(errors.New("AUDIT_FAIL: sha256 must be present in allowed_hash_algs"))
+	}
+	if !containsAlg(p.Constraints.AllowedHashAlgs, p.Constraints.HashAlg) {
+		fail(fmt.Errorf("AUDIT_FAIL: active hash_alg '%s' is not declared in allowed_hash_algs", p.Constraints.HashAlg))
 	}
 
-	if p.Constraints.DomainSeparator != "RVA_NODE:v1" {
-		return fmt.Errorf("AUDIT_FAIL: domain_separator '%s' violates protocol version (required: RVA_NODE:v1)", p.Constraints.DomainSeparator)
+	if want := requiredDomainSeparator(); p.Constraints.DomainSeparator != want {
+		fail(fmt.Errorf("AUDIT_FAIL: domain_separator '%s' violates protocol version (required: %s)", p.Constraints.DomainSeparator, want))
 	}
 
 	// 2. Merkle Tree Boundaries
 	if p.Constraints.MinDepth < 1 || p.Constraints.MaxDepth > 64 {
-		return fmt.Errorf("AUDIT_FAIL: invalid Merkle depth boundaries (min:1, max:64)")
+		fail(fmt.Errorf("AUDIT_FAIL: invalid Merkle depth boundaries (min:1, max:64)"))
+	} else if p.Constraints.MaxDepth < 1 {
+		fail(fmt.Errorf("AUDIT_FAIL: max_depth %d must be at least 1", p.Constraints.MaxDepth))
+	} else if p.Constraints.MinDepth > p.Constraints.MaxDepth {
+		fail(fmt.Errorf("AUDIT_FAIL: min_depth %d exceeds max_depth %d", p.Constraints.MinDepth, p.Constraints.MaxDepth))
 	}
 
 	// 3. Epoch & Timing Discipline
 	// NOTE: We enforce the 24h production limit here. 
 	// Developer overrides should be handled via environment variables, not by weakening the policy.
 	if p.Epochs.IntervalSeconds < 86400 {
-		return fmt.Errorf("AUDIT_FAIL: rotation interval %d is below production safety limit (86400s)", p.Epochs.IntervalSeconds)
+		fail(fmt.Errorf("AUDIT_FAIL: rotation interval %d is below production safety limit (86400s)", p.Epochs.IntervalSeconds))
 	}
 
 	if p.Epochs.IDFormat != "numeric_ascending" {
-		return fmt.Errorf("AUDIT_FAIL: epoch_id_format '%s' is not recognized", p.Epochs.IDFormat)
+		fail(fmt.Errorf("AUDIT_FAIL: epoch_id_format '%s' is not recognized", p.Epochs.IDFormat))
 	}
 
 	// 4. Governance Rules (Cutover)
 	if !p.Cutover.RequirePrevAnchor || !p.Cutover.StrictMonotonicEpoch {
-		return errors.New("AUDIT_FAIL: cutover rules must enforce previous_anchor and strict_monotonicity")
+		fail(errors.New("AUDIT_FAIL: cutover rules must enforce previous_anchor and strict_monotonicity"))
 	}
 
+	// 5. Issuer Whitelist (an empty list allows any issuer)
+	if allowed := p.Constraints.AllowedIssuers; len(allowed) > 0 && !slices.Contains(allowed, p.Issuer.ID) {
+		fail(fmt.Errorf("AUDIT_FAIL: issuer '%s' (%s) is not in allowed_issuers", p.Issuer.ID, p.Issuer.Name))
+	}
+
+	return errs
+}
+
+// CheckTreeDepth enforces the policy's Merkle depth bounds on a sealed epoch
+// tree, where depth is the number of hashing levels (see merkle.BuildRootWithStats).
+func CheckTreeDepth(p *RotationPolicy, depth int) error {
+	if depth < p.Constraints.MinDepth || depth > p.Constraints.MaxDepth {
+		return fmt.Errorf("AUDIT_FAIL: tree depth %d outside policy bounds [%d, %d]", depth, p.Constraints.MinDepth, p.Constraints.MaxDepth)
+	}
 	return nil
 }
+
+// requiredDomainSeparator is the separator the canon major pins: "RVA_NODE:v1" for v1.x.
+func requiredDomainSeparator() string {
+	return "RVA_NODE:v" + majorVersion(canonVersion)
+}
+
+// containsAlg reports whether alg is declared in the list.
+func containsAlg(algs []string, alg string) bool {
+	for _, a := range algs {
+		if a == alg {
+			return true
+		}
+	}
+	return false
+}
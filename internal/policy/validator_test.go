@@ -0,0 +1,241 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+// validPolicy returns a policy that satisfies every invariant.
+func validPolicy() *RotationPolicy {
+	return &RotationPolicy{
+		PolicyVersion: "1.0",
+		Issuer:        IssuerInfo{Name: "Alpha", ID: "rva://1"},
+		Epochs: EpochConfig{
+			IntervalSeconds: 86400,
+			IDFormat:        "numeric_ascending",
+		},
+		Constraints: CryptoConstraints{
+			HashAlg:         "sha256",
+			AllowedHashAlgs: []string{"sha256"},
+			DomainSeparator: "RVA_NODE:v1",
+			MinDepth:        1,
+			MaxDepth:        64,
+		},
+		Cutover: CutoverRules{
+			RequirePrevAnchor:    true,
+			StrictMonotonicEpoch: true,
+		},
+	}
+}
+
+func TestValidateInvariants_Valid(t *testing.T) {
+	if err := ValidateInvariants(validPolicy()); err != nil {
+		t.Fatalf("ValidateInvariants failed on valid policy: %v", err)
+	}
+}
+
+func TestValidateInvariants_HashAlgorithms(t *testing.T) {
+	tests := []struct {
+		name    string
+		hashAlg string
+		allowed []string
+		errMsg  string
+	}{
+		{
+			name:    "sha512 active",
+			hashAlg: "sha512",
+			allowed: []string{"sha256", "sha512"},
+		},
+		{
+			name:    "md5 rejected",
+			hashAlg: "md5",
+			allowed: []string{"sha256", "md5"},
+			errMsg:  "explicitly rejected",
+		},
+		{
+			name:    "sha1 in allowed list rejected",
+			hashAlg: "sha256",
+			allowed: []string{"sha256", "sha1"},
+			errMsg:  "broken algorithm 'sha1'",
+		},
+		{
+			name:    "unknown algorithm",
+			hashAlg: "blake3",
+			allowed: []string{"sha256", "blake3"},
+			errMsg:  "not supported",
+		},
+		{
+			name:    "sha256 missing from allowed",
+			hashAlg: "sha512",
+			allowed: []string{"sha512"},
+			errMsg:  "sha256 must be present",
+		},
+		{
+			name:    "active algorithm not declared",
+			hashAlg: "sha512",
+			allowed: []string{"sha256"},
+			errMsg:  "not declared in allowed_hash_algs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validPolicy()
+			p.Constraints.HashAlg = tt.hashAlg
+			p.Constraints.AllowedHashAlgs = tt.allowed
+
+			err := ValidateInvariants(p)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("expected nil, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestValidateInvariants_DepthBounds(t *testing.T) {
+	tests := []struct {
+		name     string
+		minDepth int
+		maxDepth int
+		errMsg   string
+	}{
+		{name: "valid range", minDepth: 4, maxDepth: 32},
+		{name: "equal bounds", minDepth: 10, maxDepth: 10},
+		{name: "min greater than max", minDepth: 40, maxDepth: 10, errMsg: "min_depth 40 exceeds max_depth 10"},
+		{name: "max below one", minDepth: 1, maxDepth: 0, errMsg: "max_depth 0 must be at least 1"},
+		{name: "min below one", minDepth: 0, maxDepth: 10, errMsg: "invalid Merkle depth boundaries"},
+		{name: "max above 64", minDepth: 1, maxDepth: 65, errMsg: "invalid Merkle depth boundaries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validPolicy()
+			p.Constraints.MinDepth = tt.minDepth
+			p.Constraints.MaxDepth = tt.maxDepth
+
+			err := ValidateInvariants(p)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("expected nil, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error containing %q, got nil", tt.errMsg)
+			}
+			if !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error containing %q, got: %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestValidateInvariantsAll_Clean(t *testing.T) {
+	if errs := ValidateInvariantsAll(validPolicy()); len(errs) != 0 {
+		t.Fatalf("expected no violations, got %v", errs)
+	}
+}
+
+func TestValidateInvariantsAll_ReportsEveryViolation(t *testing.T) {
+	p := validPolicy()
+	p.Constraints.DomainSeparator = "RVA_NODE:v0"
+	p.Epochs.IntervalSeconds = 60
+	p.Cutover.RequirePrevAnchor = false
+
+	errs := ValidateInvariantsAll(p)
+	want := []string{"domain_separator", "rotation interval 60", "cutover rules"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d violations, got %d: %v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		if !strings.Contains(errs[i].Error(), w) {
+			t.Errorf("violation %d = %q, want it to mention %q", i, errs[i], w)
+		}
+	}
+
+	// The fail-fast path reports the first of them
+	if err := ValidateInvariants(p); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("ValidateInvariants = %v, want %v", err, errs[0])
+	}
+}
+
+func TestValidateInvariants_DomainSeparatorFollowsCanon(t *testing.T) {
+	if got := requiredDomainSeparator(); got != "RVA_NODE:v1" {
+		t.Fatalf("requiredDomainSeparator = %q, want RVA_NODE:v1 for canon %s", got, canonVersion)
+	}
+
+	// Simulate a canon bump: the v1 separator must now fail, naming both values
+	previous := canonVersion
+	canonVersion = "v2.0"
+	defer func() { canonVersion = previous }()
+
+	if got := requiredDomainSeparator(); got != "RVA_NODE:v2" {
+		t.Fatalf("requiredDomainSeparator = %q after bump, want RVA_NODE:v2", got)
+	}
+	err := ValidateInvariants(validPolicy())
+	if err == nil || !strings.Contains(err.Error(), "'RVA_NODE:v1'") || !strings.Contains(err.Error(), "required: RVA_NODE:v2") {
+		t.Fatalf("expected domain_separator AUDIT_FAIL naming both separators, got %v", err)
+	}
+
+	p := validPolicy()
+	p.Constraints.DomainSeparator = "RVA_NODE:v2"
+	if err := ValidateInvariants(p); err != nil {
+		t.Errorf("v2 separator under canon v2.0: %v", err)
+	}
+}
+
+func TestCheckTreeDepth(t *testing.T) {
+	p := validPolicy()
+	p.Constraints.MinDepth = 2
+	p.Constraints.MaxDepth = 4
+
+	for _, depth := range []int{2, 3, 4} {
+		if err := CheckTreeDepth(p, depth); err != nil {
+			t.Errorf("depth %d: expected nil, got %v", depth, err)
+		}
+	}
+	for _, depth := range []int{0, 1, 5} {
+		err := CheckTreeDepth(p, depth)
+		if err == nil || !strings.Contains(err.Error(), "outside policy bounds [2, 4]") {
+			t.Errorf("depth %d: expected bounds violation, got %v", depth, err)
+		}
+	}
+}
+
+func TestValidateInvariants_AllowedIssuers(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		wantErr bool
+	}{
+		{name: "empty list allows all", allowed: nil},
+		{name: "issuer listed", allowed: []string{"rva://0", "rva://1"}},
+		{name: "issuer not listed", allowed: []string{"rva://2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := validPolicy()
+			p.Constraints.AllowedIssuers = tt.allowed
+
+			err := ValidateInvariants(p)
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "AUDIT_FAIL") || !strings.Contains(err.Error(), "rva://1") {
+				t.Fatalf("expected AUDIT_FAIL naming issuer rva://1, got %v", err)
+			}
+		})
+	}
+}
@@ -3,16 +3,16 @@
 package ledger
 
 import (
-	"bufio"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"regexp"
 	"sync"
 	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
 )
 
 var (
@@ -30,31 +30,105 @@ var (
 
 	// ErrInvalidTimestamp is returned when timestamp validation fails
 	ErrInvalidTimestamp = errors.New("invalid timestamp format")
+
+	// ErrDuplicate is returned by idempotent appends when an identical register
+	// already exists in the current epoch. Callers may treat it as success.
+	ErrDuplicate = errors.New("duplicate register in current epoch")
+
+	// ErrTimestampOutOfRange is returned when a client-supplied timestamp is outside
+	// the submission tolerance window
+	ErrTimestampOutOfRange = errors.New("timestamp out of range")
+
+	// ErrCanonMismatch is returned when a manifest targets a canon version other than config.CanonVersion
+	ErrCanonMismatch = errors.New("canon version mismatch")
+
+	// ErrEpochOutOfSequence is returned when a manifest's EpochID is not the next
+	// value after the previous seal (StrictMonotonicEpoch)
+	ErrEpochOutOfSequence = errors.New("epoch id out of sequence")
+
+	// ErrBrokenAnchor is returned when a manifest's PrevSealRoot does not match the previous seal's MerkleRoot
+	ErrBrokenAnchor = errors.New("broken seal anchor")
 )
 
+// CorruptError reports a ledger line that cannot be parsed. It matches
+// ErrLedgerCorrupt under errors.Is, and callers that need the offending line
+// (repair tooling, for one) can extract it with errors.As.
+type CorruptError struct {
+	// LineNum is the 1-based ledger line, or 0 when the corruption is not tied to one
+	LineNum int
+	// RawLine is the offending line as read from the ledger, without its newline
+	RawLine string
+	// Reason describes what is wrong with the line
+	Reason string
+}
+
+func (e *CorruptError) Error() string {
+	if e.LineNum == 0 {
+		return fmt.Sprintf("%v: %s", ErrLedgerCorrupt, e.Reason)
+	}
+	return fmt.Sprintf("%v: line %d: %s", ErrLedgerCorrupt, e.LineNum, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrLedgerCorrupt) hold for every CorruptError
+func (e *CorruptError) Is(target error) bool {
+	return target == ErrLedgerCorrupt
+}
+
+// corruptLine builds a CorruptError for line lineNum with a formatted reason
+func corruptLine(lineNum int, line []byte, format string, args ...interface{}) error {
+	return &CorruptError{LineNum: lineNum, RawLine: string(line), Reason: fmt.Sprintf(format, args...)}
+}
+
 // hex64Pattern validates 64-character lowercase hex strings (SHA-256)
 var hex64Pattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
 
 // hex128Pattern validates 128-character lowercase hex strings (Ed25519 signatures)
 var hex128Pattern = regexp.MustCompile(`^[a-f0-9]{128}$`)
 
-// Default ledger path
+// now is the ledger clock (replaced in tests)
+var now = time.Now
+
+// Default ledger path.
+// ledgerMutex guards ledgerPath, the active store and the data behind them:
+// writers take Lock, readers take RLock around both store capture and access.
+// A nil store means the default FileStore at ledgerPath.
 var (
-	ledgerPath   = "data/ledger.jsonl"
-	ledgerMutex  sync.Mutex
+	ledgerPath  = "data/ledger.jsonl"
+	ledgerStore Store
+	ledgerMutex sync.RWMutex
 )
 
-// SetLedgerPath allows configuration of the ledger file path (primarily for testing)
+// SetLedgerPath allows configuration of the ledger file path (primarily for testing).
+// It also switches the ledger back to the file backend.
 func SetLedgerPath(path string) {
 	ledgerMutex.Lock()
 	defer ledgerMutex.Unlock()
 	ledgerPath = path
+	ledgerStore = nil
+	bumpLedgerVersion()
 }
 
-// GetLedgerPath returns the current ledger file path
-func GetLedgerPath() string {
+// SetStore routes all ledger operations through s. Passing nil restores the
+// default FileStore at the current ledger path.
+func SetStore(s Store) {
 	ledgerMutex.Lock()
 	defer ledgerMutex.Unlock()
+	ledgerStore = s
+	bumpLedgerVersion()
+}
+
+// currentStore returns the active backend. The caller must hold ledgerMutex.
+func currentStore() Store {
+	if ledgerStore != nil {
+		return ledgerStore
+	}
+	return &FileStore{Path: ledgerPath}
+}
+
+// GetLedgerPath returns the current ledger file path
+func GetLedgerPath() string {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
 	return ledgerPath
 }
 
@@ -62,17 +136,60 @@ func GetLedgerPath() string {
 type RegisterEntry struct {
 	Type             string `json:"type"`                        // Always "register"
 	Canon            string `json:"canon"`                       // Canon version (e.g., "v1.0")
-	Timestamp        string `json:"timestamp"`                   // RFC3339Nano format
-	ObjectHashHex    string `json:"object_hash_hex"`             // 64 lowercase hex
+	Timestamp        string `json:"timestamp"`                   // CanonTimestampLayout (older entries: any RFC3339)
+	ObjectHashHex    string `json:"object_hash_hex"`             // 64 lowercase hex (128 under SetHashAlg("sha512"))
 	CanonicalJSONB64 string `json:"canonical_json_b64,omitempty"` // Optional base64 encoded canonical JSON
+	CanonicalJSONEnc string `json:"canonical_json_enc,omitempty"` // "gzip" if compressed before base64; readers always see it decompressed
+	Signature        string `json:"signature,omitempty"`          // Optional Ed25519 signature over the hash of the canonical JSON (128 hex)
+	PublicKey        string `json:"public_key,omitempty"`         // Public key for Signature (64 hex)
 }
 
 // Manifest represents the seal manifest containing cryptographic proof
 type Manifest struct {
-	MerkleRoot string `json:"merkle_root"` // 64 lowercase hex
+	MerkleRoot string `json:"merkle_root"` // 64 lowercase hex (128 under SetHashAlg("sha512"))
 	Signature  string `json:"signature"`   // 128 lowercase hex (Ed25519)
 	PublicKey  string `json:"public_key"`  // 64 lowercase hex (Ed25519)
-	Timestamp  string `json:"timestamp"`   // RFC3339Nano format
+	Timestamp  string `json:"timestamp"`   // RFC3339; stored normalized (see NormalizeTimestamp)
+	Canon      string `json:"canon"`       // Canon version, stamped by AppendSeal
+	EpochID    int    `json:"epoch_id"`    // Numeric ascending epoch index, starting at 0
+
+	// PrevSealRoot anchors this seal to the previous one (config.GenesisPrevHash for the first seal)
+	PrevSealRoot string `json:"prev_seal_root"`
+
+	// LeafCount is the number of registers the seal covers, the totalLeaves every
+	// inclusion proof against MerkleRoot must claim (see Manifest.CheckTreeSize)
+	LeafCount int `json:"leaf_count,omitempty"`
+
+	// PolicyHash is the hash of the rotation policy the seal was made under (see SetSealPolicy)
+	PolicyHash string `json:"policy_hash,omitempty"`
+
+	// SigVersion says what Signature covers: SigVersionRoot (absent) or SigVersionDigest
+	SigVersion int `json:"sig_version,omitempty"`
+}
+
+// Validate checks the manifest's field formats: MerkleRoot as 64 lowercase hex
+// (128 under SetHashAlg("sha512")), Signature as 128 and PublicKey as 64
+// lowercase hex, and Timestamp as RFC3339 (see ParseCanonTimestamp). It returns ErrInvalidHex or
+// ErrInvalidTimestamp for the first bad field. Whether the signature verifies
+// and the manifest fits the ledger's seal chain is left to AppendSeal.
+func (m Manifest) Validate() error {
+	if oh := activeObjectHash.Load(); !oh.pattern.MatchString(m.MerkleRoot) {
+		return fmt.Errorf("%w: merkle_root must be %d lowercase hex chars, got %q", ErrInvalidHex, oh.hexLen, m.MerkleRoot)
+	}
+
+	if !hex128Pattern.MatchString(m.Signature) {
+		return fmt.Errorf("%w: signature must be 128 lowercase hex chars, got %q", ErrInvalidHex, m.Signature)
+	}
+
+	if !hex64Pattern.MatchString(m.PublicKey) {
+		return fmt.Errorf("%w: public_key must be 64 lowercase hex chars, got %q", ErrInvalidHex, m.PublicKey)
+	}
+
+	if _, err := ParseCanonTimestamp(m.Timestamp); err != nil {
+		return fmt.Errorf("manifest timestamp: %w", err)
+	}
+
+	return nil
 }
 
 // SealEntry represents a seal record in the ledger
@@ -84,32 +201,204 @@ type SealEntry struct {
 // AppendRegister appends a registration entry to the ledger.
 // 
 // Parameters:
-//   - objectHashHex: SHA-256 hash of the object (64 lowercase hex)
+//   - objectHashHex: hash of the object under the active algorithm (HashAlg):
+//     64 lowercase hex for sha256, 128 for sha512
 //   - canonicalJSON: Optional canonical JSON bytes for audit replay
 //
 // Returns error if:
-//   - objectHashHex is not valid 64-char lowercase hex
+//   - objectHashHex is not lowercase hex of the active algorithm's length (ErrInvalidHex)
+//   - canonicalJSON exceeds the SetMaxCanonicalJSONBytes cap (ErrPayloadTooLarge)
 //   - File I/O fails
 func AppendRegister(objectHashHex string, canonicalJSON []byte) error {
+	entry, err := newRegisterEntry(objectHashHex, canonicalJSON, now())
+	if err != nil {
+		return err
+	}
+
+	return appendEntry(entry)
+}
+
+// AppendRegisterWithTimestamp appends a registration entry stamped with a
+// client-supplied timestamp instead of server time.
+//
+// The timestamp may be at most config.SubmissionTimestampToleranceSeconds
+// before the server clock (boundary inclusive) and never after it, must be
+// after the last seal, and must not precede the last register. Epoch
+// membership is decided by timestamp: a register dated inside an already
+// sealed epoch could never be covered by a seal, and one dated after the
+// server clock could land after the next seal's timestamp and be sealed again
+// in the following epoch. A register dated before the one above it would be
+// reported by CheckIntegrity as out of order.
+//
+// Returns ErrTimestampOutOfRange if either rule is violated; otherwise the same
+// errors as AppendRegister.
+func AppendRegisterWithTimestamp(objectHashHex string, ts time.Time, canonicalJSON []byte) error {
+	tolerance := time.Duration(config.SubmissionTimestampToleranceSeconds) * time.Second
+	skew := ts.Sub(now())
+	if skew > 0 {
+		return fmt.Errorf("%w: %s is %v after server time", ErrTimestampOutOfRange, NormalizeTimestamp(ts), skew)
+	}
+	if skew < -tolerance {
+		return fmt.Errorf("%w: %s is %v from server time (tolerance -%v)", ErrTimestampOutOfRange, NormalizeTimestamp(ts), skew, tolerance)
+	}
+
+	entry, err := newRegisterEntry(objectHashHex, canonicalJSON, ts)
+	if err != nil {
+		return err
+	}
+
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+
+	lastSealTS, err := lastSealTimestampIn(context.Background(), st)
+	if err != nil {
+		return err
+	}
+	if !ts.After(lastSealTS) {
+		return fmt.Errorf("%w: %s is not after the last seal at %s", ErrTimestampOutOfRange, entry.Timestamp, NormalizeTimestamp(lastSealTS))
+	}
+
+	// CheckIntegrity flags a register dated before the entry above it as out of order
+	pending, err := listRegistersSinceIn(context.Background(), st, lastSealTS)
+	if err != nil {
+		return err
+	}
+	if n := len(pending); n > 0 {
+		lastTS, err := ParseCanonTimestamp(pending[n-1].Timestamp)
+		if err != nil {
+			return err
+		}
+		if ts.Before(lastTS) {
+			return fmt.Errorf("%w: %s precedes the last register at %s", ErrTimestampOutOfRange, entry.Timestamp, pending[n-1].Timestamp)
+		}
+	}
+
+	return appendEntryTo(st, entry)
+}
+
+// AppendRegisterIdempotent appends a registration entry unless an identical one
+// (same ObjectHashHex and canonical JSON) was already registered since the last seal.
+//
+// The check and the write happen under the ledger lock, so concurrent retries of
+// the same object produce exactly one line. A register sealed in a previous epoch
+// does not count as a duplicate.
+//
+// Returns ErrDuplicate (without writing) if the register already exists in the
+// current epoch; otherwise the same errors as AppendRegister.
+func AppendRegisterIdempotent(objectHashHex string, canonicalJSON []byte) error {
+	entry, err := newRegisterEntry(objectHashHex, canonicalJSON, now())
+	if err != nil {
+		return err
+	}
+
+	plainB64 := ""
+	if len(canonicalJSON) > 0 {
+		plainB64 = base64.StdEncoding.EncodeToString(canonicalJSON)
+	}
+
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+
+	lastSealTS, err := lastSealTimestampIn(context.Background(), st)
+	if err != nil {
+		return err
+	}
+
+	pending, err := listRegistersSinceIn(context.Background(), st, lastSealTS)
+	if err != nil {
+		return err
+	}
+
+	for _, reg := range pending {
+		// Pending registers are read back decompressed, so compare against the plain form
+		if reg.ObjectHashHex == entry.ObjectHashHex && reg.CanonicalJSONB64 == plainB64 {
+			return ErrDuplicate
+		}
+	}
+
+	return appendEntryTo(st, entry)
+}
+
+// BatchRegister is one object of an AppendRegisterBatch call.
+type BatchRegister struct {
+	ObjectHashHex string
+	CanonicalJSON []byte // Optional, as for AppendRegister
+}
+
+// AppendRegisterBatch appends a register for every item, in order, under a single
+// acquisition of the ledger lock. Every item is validated before anything is
+// written: one bad hash or oversized payload rejects the whole batch with the
+// same errors as AppendRegister, naming the item's index. All entries share one
+// timestamp. An I/O failure part-way through can still leave a prefix of the
+// batch written. Returns the number of registers written (0 for an empty batch).
+func AppendRegisterBatch(items []BatchRegister) (int, error) {
+	ts := now()
+	lines := make([][]byte, 0, len(items))
+	for i, item := range items {
+		entry, err := newRegisterEntry(item.ObjectHashHex, item.CanonicalJSON, ts)
+		if err != nil {
+			return 0, fmt.Errorf("batch item %d: %w", i, err)
+		}
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return 0, fmt.Errorf("%w: failed to marshal entry: %v", ErrLedgerIO, err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return 0, nil
+	}
+
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+	if err := checkHeaderIn(st); err != nil {
+		return 0, err
+	}
+	defer bumpLedgerVersion()
+	for i, line := range lines {
+		if err := st.Append(line); err != nil {
+			return i, err
+		}
+		noteAppended(line)
+	}
+	return len(lines), nil
+}
+
+// newRegisterEntry validates the object hash and builds a register entry stamped with ts.
+func newRegisterEntry(objectHashHex string, canonicalJSON []byte, ts time.Time) (RegisterEntry, error) {
 	// Validate object hash
-	if !hex64Pattern.MatchString(objectHashHex) {
-		return fmt.Errorf("%w: object_hash_hex must be 64 lowercase hex chars, got %q", ErrInvalidHex, objectHashHex)
+	if oh := activeObjectHash.Load(); !oh.pattern.MatchString(objectHashHex) {
+		return RegisterEntry{}, fmt.Errorf("%w: object_hash_hex must be %d lowercase hex chars, got %q", ErrInvalidHex, oh.hexLen, objectHashHex)
+	}
+	if err := checkPayloadSize(canonicalJSON); err != nil {
+		return RegisterEntry{}, err
 	}
 
 	// Create register entry
 	entry := RegisterEntry{
 		Type:          "register",
 		Canon:         "v1.0",
-		Timestamp:     time.Now().UTC().Format(time.RFC3339Nano),
+		Timestamp:     NormalizeTimestamp(ts),
 		ObjectHashHex: objectHashHex,
 	}
 
-	// Optionally encode canonical JSON
+	// Optionally encode canonical JSON (gzipped first if compression is enabled)
 	if len(canonicalJSON) > 0 {
-		entry.CanonicalJSONB64 = base64.StdEncoding.EncodeToString(canonicalJSON)
+		b64, enc, err := encodeCanonical(canonicalJSON)
+		if err != nil {
+			return RegisterEntry{}, err
+		}
+		entry.CanonicalJSONB64 = b64
+		entry.CanonicalJSONEnc = enc
 	}
 
-	return appendEntry(entry)
+	return entry, nil
 }
 
 // ListRegistersSince returns all registration entries after the specified timestamp.
@@ -119,64 +408,58 @@ func AppendRegister(objectHashHex string, canonicalJSON []byte) error {
 //
 // Returns:
 //   - Slice of RegisterEntry records
-//   - Error if ledger is corrupt or I/O fails
+//   - Error if ledger is corrupt (under the default FailFast policy) or I/O fails;
+//     with SetRejectFutureTimestamps, a register stamped in the future is corrupt
 func ListRegistersSince(lastSealTS time.Time) ([]RegisterEntry, error) {
-	path := GetLedgerPath()
-
-	// If ledger doesn't exist, return empty slice
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return []RegisterEntry{}, nil
-	}
+	return ListRegistersSinceCtx(context.Background(), lastSealTS)
+}
 
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
-	}
-	defer file.Close()
+// ListRegistersSinceCtx is ListRegistersSince that stops scanning once ctx is
+// done, returning an error that wraps ctx.Err(). Under SkipAndCollect (see
+// SetCorruptionPolicy) corrupt lines are skipped; use
+// ListRegistersSinceCollect to get them.
+func ListRegistersSinceCtx(ctx context.Context, lastSealTS time.Time) ([]RegisterEntry, error) {
+	registers, _, err := ListRegistersSinceCollect(ctx, lastSealTS)
+	return registers, err
+}
 
-	var registers []RegisterEntry
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+// listRegistersSinceIn is ListRegistersSinceCtx against an explicit store,
+// for callers that already hold ledgerMutex.
+func listRegistersSinceIn(ctx context.Context, st Store, lastSealTS time.Time) ([]RegisterEntry, error) {
+	return listRegistersSinceWith(ctx, st, lastSealTS, nil)
+}
 
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Bytes()
+// listRegistersSinceWith is listRegistersSinceIn that hands corrupt lines to
+// onCorrupt, if set, instead of failing (see scanLinesWith).
+func listRegistersSinceWith(ctx context.Context, st Store, lastSealTS time.Time, onCorrupt func(*CorruptError) error) ([]RegisterEntry, error) {
+	registers := []RegisterEntry{}
 
-		// Skip empty lines
-		if len(line) == 0 {
-			continue
+	err := scanStoreSinceWith(st, lastSealTS, onCorrupt, cancellable(ctx, func(lineNum int, entryType string, line []byte) error {
+		// Only process register entries
+		if entryType != "register" {
+			return nil
 		}
 
-		// Parse as generic entry to determine type
-		var entry struct {
-			Type string `json:"type"`
+		reg, ts, err := parseRegister(lineNum, line)
+		if err == nil {
+			err = checkNotFuture(lineNum, line, ts)
 		}
-		if err := json.Unmarshal(line, &entry); err != nil {
-			return nil, fmt.Errorf("%w: line %d: invalid JSON: %v", ErrLedgerCorrupt, lineNum, err)
+		var corrupt *CorruptError
+		if onCorrupt != nil && errors.As(err, &corrupt) {
+			return onCorrupt(corrupt)
 		}
-
-		// Only process register entries
-		if entry.Type == "register" {
-			var reg RegisterEntry
-			if err := json.Unmarshal(line, &reg); err != nil {
-				return nil, fmt.Errorf("%w: line %d: invalid register entry: %v", ErrLedgerCorrupt, lineNum, err)
-			}
-
-			// Parse timestamp
-			ts, err := time.Parse(time.RFC3339Nano, reg.Timestamp)
-			if err != nil {
-				return nil, fmt.Errorf("%w: line %d: invalid timestamp: %v", ErrLedgerCorrupt, lineNum, err)
-			}
-
-			// Filter by timestamp
-			if ts.After(lastSealTS) {
-				registers = append(registers, reg)
-			}
+		if err != nil {
+			return err
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+		// Filter by timestamp
+		if ts.After(lastSealTS) {
+			registers = append(registers, reg)
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 
 	return registers, nil
@@ -188,42 +471,99 @@ func ListRegistersSince(lastSealTS time.Time) ([]RegisterEntry, error) {
 //   - manifest: Manifest containing Merkle root, signature, public key, and timestamp
 //
 // Returns error if:
-//   - No registrations exist since last seal (or ever)
-//   - Manifest validation fails
+//   - No registrations exist since last seal (or ever), including when an
+//     identical seal for the same epoch landed first
+//   - Manifest validation fails (see Manifest.Validate)
+//   - Signature enforcement is on and the signature does not verify over
+//     what its sig_version covers (see SetSealSignatureEnforcement)
+//   - manifest.Canon is set and differs from config.CanonVersion
+//   - manifest.SigVersion is SigVersionDigest and Canon is empty,
+//     PrevSealRoot differs from the previous seal's MerkleRoot or LeafCount
+//     differs from the number of pending registers (ErrLeafCountMismatch)
+//   - manifest.EpochID is not the previous seal's EpochID + 1 (0 for the first seal)
+//   - manifest.PrevSealRoot is set and differs from the previous seal's MerkleRoot
 //   - File I/O fails
+//
+// The canon version and the previous seal's root are stamped into a
+// SigVersionRoot manifest that leaves them empty, and the timestamp is always
+// stored normalized (NormalizeTimestamp).
 func AppendSeal(manifest Manifest) error {
-	// Validate manifest fields
-	if !hex64Pattern.MatchString(manifest.MerkleRoot) {
-		return fmt.Errorf("%w: merkle_root must be 64 lowercase hex chars, got %q", ErrInvalidHex, manifest.MerkleRoot)
-	}
+	// The last seal, the pending set and the append are read and written under
+	// one write lock, so of several concurrent seals for the same epoch exactly one lands
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
 
-	if !hex128Pattern.MatchString(manifest.Signature) {
-		return fmt.Errorf("%w: signature must be 128 lowercase hex chars, got %q", ErrInvalidHex, manifest.Signature)
-	}
+	return appendSealIn(currentStore(), manifest)
+}
 
-	if !hex64Pattern.MatchString(manifest.PublicKey) {
-		return fmt.Errorf("%w: public_key must be 64 lowercase hex chars, got %q", ErrInvalidHex, manifest.PublicKey)
+// appendSealIn runs every AppendSeal check against st and appends the seal.
+// The caller must hold ledgerMutex for writing.
+func appendSealIn(st Store, manifest Manifest) error {
+	if err := manifest.Validate(); err != nil {
+		return err
+	}
+	sealTS, _ := ParseCanonTimestamp(manifest.Timestamp) // Checked by Validate
+	manifest.Timestamp = NormalizeTimestamp(sealTS)
+	if enforceSealSignature.Load() {
+		if ok, err := manifest.VerifySelfSignature(); !ok {
+			return fmt.Errorf("seal rejected: %w", err)
+		}
 	}
 
-	// Validate timestamp format
-	if _, err := time.Parse(time.RFC3339Nano, manifest.Timestamp); err != nil {
-		return fmt.Errorf("%w: manifest timestamp: %v", ErrInvalidTimestamp, err)
+	// A manifest produced for another canon must not be sealed by this binary
+	if manifest.Canon != "" && manifest.Canon != config.CanonVersion {
+		return fmt.Errorf("%w: manifest canon %q, expected %q", ErrCanonMismatch, manifest.Canon, config.CanonVersion)
+	}
+	// A digest signature covers canon, so it cannot be stamped afterwards
+	if manifest.SigVersion != SigVersionRoot && manifest.Canon == "" {
+		return fmt.Errorf("%w: sig_version %d manifest carries no canon", ErrCanonMismatch, manifest.SigVersion)
 	}
+	manifest.Canon = config.CanonVersion
 
-	// Check if there are any registrations to seal
-	lastSealTS, err := getLastSealTimestamp()
+	lastSeal, err := lastSealIn(st)
 	if err != nil {
 		return err
 	}
 
-	registers, err := ListRegistersSince(lastSealTS)
+	// Registers pending since the last seal
+	var lastSealTS time.Time
+	if lastSeal != nil {
+		if lastSealTS, err = ParseCanonTimestamp(lastSeal.Manifest.Timestamp); err != nil {
+			return corruptLine(0, nil, "invalid seal timestamp: %v", err)
+		}
+	}
+
+	registers, err := listRegistersSinceIn(context.Background(), st, lastSealTS)
 	if err != nil {
 		return err
 	}
 
+	// A copy of the seal that just landed lost the race: its registers are sealed
+	if len(registers) == 0 && lastSeal != nil && manifest.EpochID == lastSeal.Manifest.EpochID && manifest.MerkleRoot == lastSeal.Manifest.MerkleRoot {
+		return fmt.Errorf("%w: epoch %d is already sealed with this merkle_root", ErrNoRegistrations, manifest.EpochID)
+	}
+
+	// Epochs are strictly monotonic: the manifest must claim exactly the next ID
+	if expected := nextEpochID(lastSeal); manifest.EpochID != expected {
+		return fmt.Errorf("%w: manifest epoch_id %d, expected %d", ErrEpochOutOfSequence, manifest.EpochID, expected)
+	}
+
+	// Anchor this epoch to the previous seal (RequirePrevAnchor). A digest
+	// signature covers prev_seal_root, so there it must already be the right one
+	prevRoot := prevSealRoot(lastSeal)
+	if (manifest.PrevSealRoot != "" || manifest.SigVersion != SigVersionRoot) && manifest.PrevSealRoot != prevRoot {
+		return fmt.Errorf("%w: manifest prev_seal_root %q, previous seal root %q", ErrBrokenAnchor, manifest.PrevSealRoot, prevRoot)
+	}
+	manifest.PrevSealRoot = prevRoot
+
+	// Check if there are any registrations to seal
 	if len(registers) == 0 {
 		return ErrNoRegistrations
 	}
+	// A digest signature binds the tree size, so it must be the real one
+	if manifest.SigVersion != SigVersionRoot && manifest.LeafCount != len(registers) {
+		return fmt.Errorf("%w: manifest leaf_count %d, %d registers pending", ErrLeafCountMismatch, manifest.LeafCount, len(registers))
+	}
 
 	// Create seal entry
 	entry := SealEntry{
@@ -231,101 +571,269 @@ func AppendSeal(manifest Manifest) error {
 		Manifest: manifest,
 	}
 
-	return appendEntry(entry)
+	return appendEntryTo(st, entry)
 }
 
 // getLastSealTimestamp returns the timestamp of the last seal entry.
 // Returns zero time if no seals exist.
 func getLastSealTimestamp() (time.Time, error) {
-	path := GetLedgerPath()
+	return getLastSealTimestampCtx(context.Background())
+}
+
+// getLastSealTimestampCtx is getLastSealTimestamp that stops scanning once ctx is done.
+func getLastSealTimestampCtx(ctx context.Context) (time.Time, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	return lastSealTimestampIn(ctx, currentStore())
+}
+
+// lastSealTimestampIn is getLastSealTimestampCtx against an explicit store.
+func lastSealTimestampIn(ctx context.Context, st Store) (time.Time, error) {
+	var lastSealTS time.Time
+
+	err := scanStore(st, cancellable(ctx, func(lineNum int, entryType string, line []byte) error {
+		if !isEpochBoundary(entryType) {
+			return nil
+		}
+
+		_, ts, err := parseEpochBoundary(lineNum, entryType, line)
+		if err != nil {
+			return err
+		}
 
-	// If ledger doesn't exist, return zero time
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return time.Time{}, nil
+		lastSealTS = ts
+		return nil
+	}))
+	if err != nil {
+		return time.Time{}, err
 	}
 
-	file, err := os.Open(path)
+	return lastSealTS, nil
+}
+
+// NextEpochID returns the EpochID the next seal must carry: 0 for an unsealed
+// ledger, otherwise the last seal's EpochID + 1.
+func NextEpochID() (int, error) {
+	lastSeal, err := getLastSeal()
 	if err != nil {
-		return time.Time{}, fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+		return 0, err
 	}
-	defer file.Close()
+	return nextEpochID(lastSeal), nil
+}
 
-	var lastSealTS time.Time
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
+func nextEpochID(lastSeal *SealEntry) int {
+	if lastSeal == nil {
+		return 0
+	}
+	return lastSeal.Manifest.EpochID + 1
+}
+
+// prevSealRoot is the anchor the next seal must carry: the last seal's MerkleRoot,
+// or config.GenesisPrevHash when the ledger has no seal yet.
+func prevSealRoot(lastSeal *SealEntry) string {
+	if lastSeal == nil {
+		return config.GenesisPrevHash
+	}
+	return lastSeal.Manifest.MerkleRoot
+}
+
+// getLastSeal returns the last seal entry, or nil if the ledger has none.
+func getLastSeal() (*SealEntry, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	return lastSealIn(currentStore())
+}
+
+// lastSealIn returns the last seal entry in st, or nil if none exists.
+// A rotation anchor counts as the seal it carries over from the previous file.
+func lastSealIn(st Store) (*SealEntry, error) {
+	var last *SealEntry
 
-	for scanner.Scan() {
+	err := scanStore(st, func(lineNum int, entryType string, line []byte) error {
+		if !isEpochBoundary(entryType) {
+			return nil
+		}
+
+		seal, _, err := parseEpochBoundary(lineNum, entryType, line)
+		if err != nil {
+			return err
+		}
+
+		last = &seal
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return last, nil
+}
+
+// parseSeal decodes a seal line and its manifest timestamp.
+func parseSeal(lineNum int, line []byte) (SealEntry, time.Time, error) {
+	var seal SealEntry
+	if err := json.Unmarshal(line, &seal); err != nil {
+		return SealEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid seal entry: %v", err)
+	}
+
+	ts, err := ParseCanonTimestamp(seal.Manifest.Timestamp)
+	if err != nil {
+		return SealEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid seal timestamp: %v", err)
+	}
+
+	return seal, ts, nil
+}
+
+// errStopScan lets a scanLedger callback end the scan early without reporting an error
+var errStopScan = errors.New("stop scan")
+
+// scanLedger reads the ledger line by line and invokes fn with the 1-based line
+// number, the entry type and the raw line of every non-empty line.
+// A missing ledger is treated as empty. Lines that are not valid JSON yield
+// ErrLedgerCorrupt; fn may return errStopScan to end the scan early.
+//
+// The scan holds the read lock, so fn must not call back into locking ledger functions.
+func scanLedger(fn func(lineNum int, entryType string, line []byte) error) error {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	return scanStore(currentStore(), fn)
+}
+
+// ctxCheckLines is how many lines a cancellable scan reads between checks of its context
+const ctxCheckLines = 256
+
+// cancellable wraps a scan callback so the scan aborts with ctx.Err() (wrapped)
+// once ctx is done. The context is checked on the first line and then every
+// ctxCheckLines lines, so a live context costs next to nothing.
+func cancellable(ctx context.Context, fn func(lineNum int, entryType string, line []byte) error) func(lineNum int, entryType string, line []byte) error {
+	seen := 0
+	return func(lineNum int, entryType string, line []byte) error {
+		if seen%ctxCheckLines == 0 {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("scan aborted at line %d: %w", lineNum, err)
+			}
+		}
+		seen++
+		return fn(lineNum, entryType, line)
+	}
+}
+
+// scanStore is scanLedger against an explicit store.
+func scanStore(st Store, fn func(lineNum int, entryType string, line []byte) error) error {
+	return scanLines(st.Iterate, 1, fn)
+}
+
+// scanLines runs the scanStore logic over iterate, numbering its first line firstLine.
+func scanLines(iterate func(func(line []byte) error) error, firstLine int, fn func(lineNum int, entryType string, line []byte) error) error {
+	return scanLinesWith(iterate, firstLine, nil, fn)
+}
+
+// scanLinesWith is scanLines that hands lines which are not valid JSON to
+// onCorrupt, if set, and carries on when it returns nil.
+func scanLinesWith(iterate func(func(line []byte) error) error, firstLine int, onCorrupt func(*CorruptError) error, fn func(lineNum int, entryType string, line []byte) error) error {
+	lineNum := firstLine - 1
+
+	err := iterate(func(line []byte) error {
 		lineNum++
-		line := scanner.Bytes()
 
+		// Skip empty lines
 		if len(line) == 0 {
-			continue
+			return nil
 		}
 
+		// Parse as generic entry to determine type
 		var entry struct {
 			Type string `json:"type"`
 		}
 		if err := json.Unmarshal(line, &entry); err != nil {
-			return time.Time{}, fmt.Errorf("%w: line %d: invalid JSON: %v", ErrLedgerCorrupt, lineNum, err)
+			err := corruptLine(lineNum, line, "invalid JSON: %v", err)
+			if onCorrupt == nil {
+				return err
+			}
+			return onCorrupt(err.(*CorruptError))
 		}
 
-		if entry.Type == "seal" {
-			var seal SealEntry
-			if err := json.Unmarshal(line, &seal); err != nil {
-				return time.Time{}, fmt.Errorf("%w: line %d: invalid seal entry: %v", ErrLedgerCorrupt, lineNum, err)
-			}
+		return fn(lineNum, entry.Type, line)
+	})
+	if errors.Is(err, errStopScan) {
+		return nil
+	}
 
-			ts, err := time.Parse(time.RFC3339Nano, seal.Manifest.Timestamp)
-			if err != nil {
-				return time.Time{}, fmt.Errorf("%w: line %d: invalid seal timestamp: %v", ErrLedgerCorrupt, lineNum, err)
-			}
+	return err
+}
 
-			lastSealTS = ts
-		}
+// scanStoreSince is scanStore restricted to the part of the ledger that can hold
+// entries after since. A FileStore with a consistent .idx sidecar skips whole
+// blocks older than since; any other store is scanned from the top. Line
+// numbers are those of the full ledger either way.
+func scanStoreSince(st Store, since time.Time, fn func(lineNum int, entryType string, line []byte) error) error {
+	return scanStoreSinceWith(st, since, nil, fn)
+}
+
+// scanStoreSinceWith is scanStoreSince with scanLinesWith's onCorrupt hook.
+func scanStoreSinceWith(st Store, since time.Time, onCorrupt func(*CorruptError) error, fn func(lineNum int, entryType string, line []byte) error) error {
+	fs, ok := st.(*FileStore)
+	if !ok {
+		return scanLinesWith(st.Iterate, 1, onCorrupt, fn)
+	}
+	offset, firstLine, err := fs.Seek(since)
+	if err != nil {
+		return err
 	}
+	return scanLinesWith(func(visit func(line []byte) error) error {
+		return fs.iterateFrom(offset, visit)
+	}, firstLine, onCorrupt, fn)
+}
 
-	if err := scanner.Err(); err != nil {
-		return time.Time{}, fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+// parseRegister decodes a register line and its timestamp. Compressed canonical
+// JSON is returned decompressed.
+func parseRegister(lineNum int, line []byte) (RegisterEntry, time.Time, error) {
+	var reg RegisterEntry
+	if err := json.Unmarshal(line, &reg); err != nil {
+		return RegisterEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid register entry: %v", err)
 	}
 
-	return lastSealTS, nil
+	// Parse timestamp
+	ts, err := ParseCanonTimestamp(reg.Timestamp)
+	if err != nil {
+		return RegisterEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid timestamp: %v", err)
+	}
+
+	reg, err = decompressRegister(lineNum, line, reg)
+	if err != nil {
+		return RegisterEntry{}, time.Time{}, err
+	}
+
+	return reg, ts, nil
 }
 
-// appendEntry appends a JSON entry to the ledger file
+// appendEntry appends a JSON entry to the ledger
 func appendEntry(entry interface{}) error {
 	ledgerMutex.Lock()
-	defer ledgerMutex.Unlock()
+	defer unlockAndNotify()
 
-	path := ledgerPath
+	return appendEntryTo(currentStore(), entry)
+}
 
-	// Ensure ledger directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("%w: failed to create ledger directory: %v", ErrLedgerIO, err)
+// appendEntryTo marshals entry, appends it to st and queues it for the observers.
+// The caller must hold ledgerMutex for writing and release it with unlockAndNotify.
+func appendEntryTo(st Store, entry interface{}) error {
+	if err := checkHeaderIn(st); err != nil {
+		return err
 	}
 
-	// Marshal entry to JSON
 	jsonBytes, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("%w: failed to marshal entry: %v", ErrLedgerIO, err)
 	}
 
-	// Append to ledger file
-	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
-	}
-	defer file.Close()
-
-	// Write JSON line
-	if _, err := file.Write(jsonBytes); err != nil {
-		return fmt.Errorf("%w: failed to write entry: %v", ErrLedgerIO, err)
-	}
-
-	// Write newline
-	if _, err := file.Write([]byte("\n")); err != nil {
-		return fmt.Errorf("%w: failed to write newline: %v", ErrLedgerIO, err)
+	defer bumpLedgerVersion()
+	if err := st.Append(jsonBytes); err != nil {
+		return err
 	}
-
+	noteAppended(jsonBytes)
 	return nil
 }
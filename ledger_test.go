@@ -3,11 +3,16 @@ package ledger
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
 )
 
 // setupTestLedger creates a temporary ledger file for testing
@@ -264,6 +269,100 @@ func TestListRegistersSince_CorruptLine(t *testing.T) {
 	}
 }
 
+func TestListRegistersSince_CorruptLineError(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, ledgerPath, "this is not valid json\n")
+
+	_, err := ListRegistersSince(time.Time{})
+	if !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("expected ErrLedgerCorrupt, got %v", err)
+	}
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("expected *CorruptError, got %T", err)
+	}
+	if corrupt.LineNum != 2 {
+		t.Errorf("LineNum = %d, want 2", corrupt.LineNum)
+	}
+	if corrupt.RawLine != "this is not valid json" {
+		t.Errorf("RawLine = %q", corrupt.RawLine)
+	}
+}
+
+func TestListRegistersSince_LineLongerThanScannerDefault(t *testing.T) {
+	setupTestLedger(t)
+
+	// Well under the payload cap, but the line is past bufio's 64 KiB default token size
+	payload := []byte(`{"a":"` + strings.Repeat("x", 200000) + `"}`)
+	if err := AppendRegister(validObjectHash(), payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 1 || registers[0].CanonicalJSONB64 != base64.StdEncoding.EncodeToString(payload) {
+		t.Fatalf("large register not read back intact (%d registers)", len(registers))
+	}
+}
+
+func TestCorruptError_InvalidTimestamp(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	bad := `{"type":"register","object_hash_hex":"` + validObjectHash() + `","timestamp":"yesterday"}`
+	appendRaw(t, ledgerPath, bad+"\n")
+
+	_, err := ListRegistersSince(time.Time{})
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) {
+		t.Fatalf("expected *CorruptError, got %v", err)
+	}
+	if corrupt.LineNum != 2 || corrupt.RawLine != bad {
+		t.Errorf("got line %d %q, want line 2 %q", corrupt.LineNum, corrupt.RawLine, bad)
+	}
+	if !strings.Contains(err.Error(), "ledger corrupt: line 2: invalid timestamp") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestManifestValidate(t *testing.T) {
+	if err := validManifest().Validate(); err != nil {
+		t.Fatalf("valid manifest rejected: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(m *Manifest)
+		want   error
+	}{
+		{"short merkle_root", func(m *Manifest) { m.MerkleRoot = m.MerkleRoot[:63] }, ErrInvalidHex},
+		{"uppercase merkle_root", func(m *Manifest) { m.MerkleRoot = strings.ToUpper(m.MerkleRoot) }, ErrInvalidHex},
+		{"short signature", func(m *Manifest) { m.Signature = m.Signature[:64] }, ErrInvalidHex},
+		{"non-hex signature", func(m *Manifest) { m.Signature = strings.Repeat("g", 128) }, ErrInvalidHex},
+		{"empty public_key", func(m *Manifest) { m.PublicKey = "" }, ErrInvalidHex},
+		{"long public_key", func(m *Manifest) { m.PublicKey += "00" }, ErrInvalidHex},
+		{"empty timestamp", func(m *Manifest) { m.Timestamp = "" }, ErrInvalidTimestamp},
+		{"non-RFC3339 timestamp", func(m *Manifest) { m.Timestamp = "2026-01-10 12:00:00" }, ErrInvalidTimestamp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := validManifest()
+			tt.mutate(&m)
+			if err := m.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
 func TestAppendSeal_NoRegistrations(t *testing.T) {
 	setupTestLedger(t)
 
@@ -326,6 +425,30 @@ func TestAppendSeal_Valid(t *testing.T) {
 	if seal.Manifest.PublicKey != manifest.PublicKey {
 		t.Errorf("PublicKey = %s, want %s", seal.Manifest.PublicKey, manifest.PublicKey)
 	}
+
+	if seal.Manifest.Canon != config.CanonVersion {
+		t.Errorf("Canon = %q, want %q", seal.Manifest.Canon, config.CanonVersion)
+	}
+}
+
+func TestAppendSeal_CanonMismatch(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	manifest := validManifest()
+	manifest.Canon = "v2.0"
+	if err := AppendSeal(manifest); !errors.Is(err, ErrCanonMismatch) {
+		t.Fatalf("expected ErrCanonMismatch, got %v", err)
+	}
+
+	// A manifest that already names the current canon is accepted
+	manifest.Canon = config.CanonVersion
+	if err := AppendSeal(manifest); err != nil {
+		t.Fatalf("AppendSeal with matching canon failed: %v", err)
+	}
 }
 
 func TestAppendSeal_InvalidManifest(t *testing.T) {
@@ -443,7 +566,9 @@ func TestAppendSeal_AfterPreviousSeal(t *testing.T) {
 	}
 
 	// Try to seal again without new registrations
-	err := AppendSeal(validManifest())
+	second := validManifest()
+	second.EpochID = 1
+	err := AppendSeal(second)
 	if err == nil {
 		t.Fatalf("expected error when sealing without new registrations")
 	}
@@ -458,11 +583,66 @@ func TestAppendSeal_AfterPreviousSeal(t *testing.T) {
 	}
 
 	// Now seal should work
-	if err := AppendSeal(validManifest()); err != nil {
+	if err := AppendSeal(second); err != nil {
 		t.Fatalf("AppendSeal 2 failed: %v", err)
 	}
 }
 
+func TestAppendSeal_EpochID(t *testing.T) {
+	setupTestLedger(t)
+
+	hashes := []string{
+		"b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+		"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+	}
+
+	// First seal must be epoch 0
+	if err := AppendRegister(hashes[0], nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	first := validManifest()
+	first.EpochID = 1
+	if err := AppendSeal(first); !errors.Is(err, ErrEpochOutOfSequence) {
+		t.Fatalf("expected ErrEpochOutOfSequence for first seal with epoch 1, got %v", err)
+	}
+	first.EpochID = 0
+	if err := AppendSeal(first); err != nil {
+		t.Fatalf("AppendSeal epoch 0 failed: %v", err)
+	}
+
+	// Correct increment
+	if err := AppendRegister(hashes[1], nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	next, err := NextEpochID()
+	if err != nil || next != 1 {
+		t.Fatalf("NextEpochID = %d, %v; want 1", next, err)
+	}
+	second := validManifest()
+	second.EpochID = next
+	if err := AppendSeal(second); err != nil {
+		t.Fatalf("AppendSeal epoch 1 failed: %v", err)
+	}
+
+	// Gaps and regressions are rejected
+	if err := AppendRegister(hashes[2], nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	for _, epochID := range []int{1, 3, -1} {
+		m := validManifest()
+		m.EpochID = epochID
+		if err := AppendSeal(m); !errors.Is(err, ErrEpochOutOfSequence) {
+			t.Errorf("epoch_id %d: expected ErrEpochOutOfSequence, got %v", epochID, err)
+		}
+	}
+
+	seals := readSeals(t)
+	if len(seals) != 2 || seals[0].Manifest.EpochID != 0 || seals[1].Manifest.EpochID != 1 {
+		t.Fatalf("unexpected seals: %+v", seals)
+	}
+}
+
 func TestCanonicalJSONB64_RoundTrip(t *testing.T) {
 	setupTestLedger(t)
 
@@ -525,3 +705,374 @@ func TestConcurrentAppends(t *testing.T) {
 		t.Errorf("expected 50 registers, got %d", len(registers))
 	}
 }
+
+func TestConcurrentIdenticalSeals(t *testing.T) {
+	setupTestLedger(t)
+	for i := 0; i < 3; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	manifest := signedManifest(t)
+
+	const n = 16
+	errs := make(chan error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- AppendSeal(manifest)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	landed := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			landed++
+		case !errors.Is(err, ErrNoRegistrations):
+			t.Errorf("losing seal: expected ErrNoRegistrations, got %v", err)
+		}
+	}
+	if landed != 1 {
+		t.Errorf("%d seals landed, want exactly 1", landed)
+	}
+	if seals := readSeals(t); len(seals) != 1 {
+		t.Errorf("ledger holds %d seals, want 1", len(seals))
+	}
+}
+
+func TestAppendRegisterBatch(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		items := []BatchRegister{
+			{ObjectHashHex: testHash(0)},
+			{ObjectHashHex: testHash(1), CanonicalJSON: []byte(`{"k":"v"}`)},
+			{ObjectHashHex: testHash(2)},
+		}
+		n, err := AppendRegisterBatch(items)
+		if err != nil || n != 3 {
+			t.Fatalf("AppendRegisterBatch = %d, %v; want 3, nil", n, err)
+		}
+
+		registers, err := ListRegistersSince(time.Time{})
+		if err != nil {
+			t.Fatalf("ListRegistersSince failed: %v", err)
+		}
+		if len(registers) != 3 {
+			t.Fatalf("expected 3 registers, got %d", len(registers))
+		}
+		for i, reg := range registers {
+			if reg.ObjectHashHex != testHash(i) {
+				t.Errorf("register %d = %s, want %s", i, reg.ObjectHashHex, testHash(i))
+			}
+		}
+		if registers[1].CanonicalJSONB64 != base64.StdEncoding.EncodeToString([]byte(`{"k":"v"}`)) {
+			t.Errorf("canonical JSON not stored for item 1")
+		}
+	})
+}
+
+func TestAppendRegisterBatch_AllOrNothing(t *testing.T) {
+	setupTestLedger(t)
+
+	items := []BatchRegister{
+		{ObjectHashHex: testHash(0)},
+		{ObjectHashHex: "NOT-A-HASH"},
+	}
+	n, err := AppendRegisterBatch(items)
+	if !errors.Is(err, ErrInvalidHex) || n != 0 {
+		t.Fatalf("AppendRegisterBatch = %d, %v; want 0, ErrInvalidHex", n, err)
+	}
+	if !strings.Contains(err.Error(), "batch item 1") {
+		t.Errorf("error does not name the bad item: %v", err)
+	}
+	if _, statErr := os.Stat(GetLedgerPath()); !os.IsNotExist(statErr) {
+		t.Errorf("ledger written despite rejected batch")
+	}
+}
+
+func TestAppendRegisterIdempotent_FirstWrite(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegisterIdempotent(validObjectHash(), []byte(`{"k":"v"}`)); err != nil {
+		t.Fatalf("AppendRegisterIdempotent failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 1 {
+		t.Errorf("expected 1 register, got %d", len(registers))
+	}
+}
+
+func TestAppendRegisterIdempotent_DuplicateWithinEpoch(t *testing.T) {
+	setupTestLedger(t)
+
+	payload := []byte(`{"k":"v"}`)
+	if err := AppendRegisterIdempotent(validObjectHash(), payload); err != nil {
+		t.Fatalf("first append failed: %v", err)
+	}
+
+	err := AppendRegisterIdempotent(validObjectHash(), payload)
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+
+	// A different payload under the same hash is not an identical retry
+	if err := AppendRegisterIdempotent(validObjectHash(), []byte(`{"k":"other"}`)); err != nil {
+		t.Fatalf("append with different payload failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 2 {
+		t.Errorf("expected 2 registers, got %d", len(registers))
+	}
+}
+
+func TestAppendRegisterIdempotent_NewEpochAfterSeal(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegisterIdempotent(validObjectHash(), nil); err != nil {
+		t.Fatalf("first append failed: %v", err)
+	}
+	if err := AppendSeal(validManifest()); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	// Same hash in a new epoch must be written
+	if err := AppendRegisterIdempotent(validObjectHash(), nil); err != nil {
+		t.Fatalf("append after seal failed: %v", err)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	if len(registers) != 2 {
+		t.Errorf("expected 2 registers, got %d", len(registers))
+	}
+}
+
+func TestAppendRegisterIdempotent_InvalidHex(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegisterIdempotent("bad", nil); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+}
+
+// setClock freezes the ledger clock at ts for the duration of the test
+func setClock(t *testing.T, ts time.Time) {
+	t.Helper()
+	previous := now
+	now = func() time.Time { return ts }
+	t.Cleanup(func() { now = previous })
+}
+
+func TestAppendRegisterWithTimestamp_ToleranceBoundary(t *testing.T) {
+	serverNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// Deliberate deviation from a symmetric ±300s window: future timestamps are
+	// rejected outright, because a register dated after the server clock can
+	// sort after the next seal and be sealed twice (see
+	// TestAppendRegisterWithTimestamp_FutureDatedNeverResealed).
+	tests := []struct {
+		name    string
+		offset  time.Duration
+		wantErr bool
+	}{
+		{name: "+1ns", offset: time.Nanosecond, wantErr: true},
+		{name: "exactly +300s", offset: 300 * time.Second, wantErr: true},
+		{name: "+301s", offset: 301 * time.Second, wantErr: true},
+		{name: "exactly -300s", offset: -300 * time.Second},
+		{name: "-301s", offset: -301 * time.Second, wantErr: true},
+		{name: "server time", offset: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestLedger(t)
+			setClock(t, serverNow)
+
+			ts := serverNow.Add(tt.offset)
+			err := AppendRegisterWithTimestamp(validObjectHash(), ts, nil)
+			if tt.wantErr {
+				if !errors.Is(err, ErrTimestampOutOfRange) {
+					t.Fatalf("expected ErrTimestampOutOfRange, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AppendRegisterWithTimestamp failed: %v", err)
+			}
+
+			registers, err := ListRegistersSince(time.Time{})
+			if err != nil {
+				t.Fatalf("ListRegistersSince failed: %v", err)
+			}
+			if len(registers) != 1 || registers[0].Timestamp != NormalizeTimestamp(ts) {
+				t.Errorf("stored registers = %+v, want timestamp %s", registers, ts.Format(time.RFC3339Nano))
+			}
+		})
+	}
+}
+
+func TestAppendRegisterWithTimestamp_BeforeLastSeal(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if err := AppendSeal(validManifest()); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	// Within tolerance, but inside the already sealed epoch
+	err := AppendRegisterWithTimestamp(validObjectHash(), time.Now().Add(-time.Minute), nil)
+	if !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Fatalf("expected ErrTimestampOutOfRange, got %v", err)
+	}
+}
+
+func TestAppendRegisterWithTimestamp_BeforeLastRegister(t *testing.T) {
+	setupTestLedger(t)
+	serverNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	setClock(t, serverNow)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// Within tolerance, but dated before the register already in the ledger
+	err := AppendRegisterWithTimestamp(testHash(1), serverNow.Add(-time.Minute), nil)
+	if !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Fatalf("expected ErrTimestampOutOfRange, got %v", err)
+	}
+
+	// The same timestamp as the last register keeps the ledger ordered
+	if err := AppendRegisterWithTimestamp(testHash(1), serverNow, nil); err != nil {
+		t.Fatalf("AppendRegisterWithTimestamp failed: %v", err)
+	}
+	if _, err := SealPending(testSeedHex); err != nil {
+		t.Fatalf("SealPending failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !report.Valid {
+		t.Fatalf("report = %+v, want a valid ledger", report)
+	}
+}
+
+func TestAppendRegisterWithTimestamp_FutureDatedNeverResealed(t *testing.T) {
+	setupTestLedger(t)
+	serverNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	setClock(t, serverNow)
+
+	// A future-dated register would sort after the next seal and be sealed twice
+	if err := AppendRegisterWithTimestamp(testHash(0), serverNow.Add(time.Second), nil); !errors.Is(err, ErrTimestampOutOfRange) {
+		t.Fatalf("future-dated register: expected ErrTimestampOutOfRange, got %v", err)
+	}
+
+	// The latest accepted timestamp is the server clock itself
+	if err := AppendRegisterWithTimestamp(testHash(0), serverNow, nil); err != nil {
+		t.Fatalf("AppendRegisterWithTimestamp failed: %v", err)
+	}
+	if _, err := SealPending(testSeedHex); err != nil {
+		t.Fatalf("first SealPending failed: %v", err)
+	}
+	setClock(t, serverNow.Add(time.Minute))
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if _, err := SealPending(testSeedHex); err != nil {
+		t.Fatalf("second SealPending failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !report.Valid || len(report.Epochs) != 2 || report.Epochs[0].RegisterCount != 1 || report.Epochs[1].RegisterCount != 1 {
+		t.Fatalf("report = %+v, want two clean epochs of one register each", report)
+	}
+}
+
+func TestAppendRegisterWithTimestamp_InvalidHex(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegisterWithTimestamp("bad", time.Now(), nil); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+}
+
+func TestLedger_ConcurrentReadsAndPathChanges(t *testing.T) {
+	// Run with -race: readers capture the path under RLock while SetLedgerPath swaps it
+	pathA := setupTestLedger(t)
+	pathB := filepath.Join(t.TempDir(), "ledger.jsonl")
+
+	for _, p := range []string{pathA, pathB} {
+		SetLedgerPath(p)
+		if err := AppendRegister(validObjectHash(), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				registers, err := ListRegistersSince(time.Time{})
+				if err != nil {
+					errs <- err
+					return
+				}
+				if len(registers) != 1 {
+					errs <- fmt.Errorf("expected 1 register, got %d", len(registers))
+					return
+				}
+				if _, err := getLastSealTimestamp(); err != nil {
+					errs <- err
+					return
+				}
+				if _, _, err := GetRegisterByHash(validObjectHash()); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			if j%2 == 0 {
+				SetLedgerPath(pathA)
+			} else {
+				SetLedgerPath(pathB)
+			}
+			_ = GetLedgerPath()
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
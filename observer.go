@@ -0,0 +1,118 @@
+package ledger
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultObserverTimeout bounds how long a writer waits for its observers
+// before returning
+const DefaultObserverTimeout = time.Second
+
+// observers is the copy-on-write list of functions RegisterObserver added
+var observers atomic.Pointer[[]func(entryType string, raw []byte)]
+
+// observersMu serializes RegisterObserver's copy of the list
+var observersMu sync.Mutex
+
+// observerTimeout is stored as nanoseconds so it can be changed while appends run
+var observerTimeout atomic.Int64
+
+func init() {
+	observerTimeout.Store(int64(DefaultObserverTimeout))
+}
+
+// pendingAppends holds the lines written under the current write lock, for
+// unlockAndNotify to hand to observers once the lock is released. Guarded by
+// ledgerMutex.
+var pendingAppends [][]byte
+
+// RegisterObserver adds fn to the functions called for every entry appended to
+// the ledger: registers, seals, headers, receipts and rotation anchors. fn gets
+// the entry's type and its JSON line exactly as stored (without the newline),
+// which it must not modify.
+//
+// Observers run only after the write succeeded (for a FileStore, after it was
+// synced to disk) and after the ledger lock is released, so they never hold up other readers or writers. The appending call
+// waits for them at most SetObserverTimeout; past that it returns and delivery
+// finishes in the background. A panicking observer is recovered and logged, and
+// the remaining observers still run. Entries of one append call are delivered
+// in file order; observers for different writers may run concurrently. Failed
+// writes are never reported. Observers cannot be removed.
+func RegisterObserver(fn func(entryType string, raw []byte)) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+
+	var list []func(string, []byte)
+	if cur := observers.Load(); cur != nil {
+		list = append(list, *cur...)
+	}
+	list = append(list, fn)
+	observers.Store(&list)
+}
+
+// SetObserverTimeout sets how long an append waits for the observers of its
+// entries before returning. Non-positive values restore the default.
+func SetObserverTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultObserverTimeout
+	}
+	observerTimeout.Store(int64(d))
+}
+
+// noteAppended records a line just written to the current store for the
+// observers. The caller holds ledgerMutex for writing.
+func noteAppended(line []byte) {
+	if observers.Load() != nil {
+		pendingAppends = append(pendingAppends, line)
+	}
+}
+
+// unlockAndNotify releases the write lock, then delivers the lines appended
+// under it to every observer, waiting at most the observer timeout. Writers
+// defer it in place of ledgerMutex.Unlock.
+func unlockAndNotify() {
+	appended := pendingAppends
+	pendingAppends = nil
+	ledgerMutex.Unlock()
+
+	fns := observers.Load()
+	if fns == nil || len(appended) == 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, line := range appended {
+			var entry struct {
+				Type string `json:"type"`
+			}
+			_ = json.Unmarshal(line, &entry)
+			for _, fn := range *fns {
+				notifyObserver(fn, entry.Type, line)
+			}
+		}
+	}()
+
+	timer := time.NewTimer(time.Duration(observerTimeout.Load()))
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+}
+
+// notifyObserver calls fn, recovering a panic so it cannot reach the writer or
+// skip the other observers.
+func notifyObserver(fn func(string, []byte), entryType string, line []byte) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("ledger: observer panicked on %s entry: %v", entryType, p)
+		}
+	}()
+	fn(entryType, line)
+}
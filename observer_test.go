@@ -0,0 +1,139 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+// observedEntry is one call to a test observer
+type observedEntry struct {
+	entryType string
+	raw       string
+}
+
+// recordAppends registers an observer that records every call, removed at cleanup
+func recordAppends(t *testing.T) *[]observedEntry {
+	t.Helper()
+	var got []observedEntry
+	RegisterObserver(func(entryType string, raw []byte) {
+		got = append(got, observedEntry{entryType, string(raw)})
+	})
+	t.Cleanup(func() { observers.Store(nil) })
+	return &got
+}
+
+func TestRegisterObserver_RegisterAndSeal(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		got := recordAppends(t)
+		second := 0
+		RegisterObserver(func(string, []byte) { second++ })
+
+		if err := AppendRegister(testHash(0), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+		m, err := SealPending(testSeedHex)
+		if err != nil {
+			t.Fatalf("SealPending failed: %v", err)
+		}
+
+		if len(*got) != 2 || (*got)[0].entryType != "register" || (*got)[1].entryType != "seal" {
+			t.Fatalf("observed %+v, want register then seal", *got)
+		}
+		var reg RegisterEntry
+		if err := json.Unmarshal([]byte((*got)[0].raw), &reg); err != nil || reg.ObjectHashHex != testHash(0) {
+			t.Errorf("register raw = %s (%v)", (*got)[0].raw, err)
+		}
+		var seal SealEntry
+		if err := json.Unmarshal([]byte((*got)[1].raw), &seal); err != nil || seal.Manifest.MerkleRoot != m.MerkleRoot {
+			t.Errorf("seal raw = %s (%v)", (*got)[1].raw, err)
+		}
+		if second != 2 {
+			t.Errorf("second observer called %d times, want 2", second)
+		}
+	})
+}
+
+func TestRegisterObserver_NotCalledOnFailedWrite(t *testing.T) {
+	setupTestLedger(t)
+	got := recordAppends(t)
+
+	if err := AppendRegister("not-a-hash", nil); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+	if _, err := SealPending(testSeedHex); !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+	if err := AppendSeal(validManifest()); err == nil {
+		t.Fatalf("AppendSeal with nothing to seal succeeded")
+	}
+	if len(*got) != 0 {
+		t.Fatalf("observers fired for failed writes: %+v", *got)
+	}
+}
+
+func TestRegisterObserver_RunsAfterLockRelease(t *testing.T) {
+	setupTestLedger(t)
+
+	// Reading the ledger from the observer would deadlock if the write lock were still held
+	done := make(chan int, 1)
+	RegisterObserver(func(string, []byte) {
+		registers, _ := ListRegistersSince(time.Time{})
+		done <- len(registers)
+	})
+	t.Cleanup(func() { observers.Store(nil) })
+
+	go AppendRegister(testHash(0), nil)
+	select {
+	case n := <-done:
+		if n != 1 {
+			t.Fatalf("observer saw %d registers, want the appended one", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("observer blocked: ledger lock still held")
+	}
+}
+
+func TestRegisterObserver_PanicDoesNotReachWriter(t *testing.T) {
+	setupTestLedger(t)
+	RegisterObserver(func(string, []byte) { panic("observer bug") })
+	got := recordAppends(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if len(*got) != 1 || (*got)[0].entryType != "register" {
+		t.Fatalf("observer after the panicking one saw %+v, want the register", *got)
+	}
+}
+
+func TestRegisterObserver_SlowObserverBounded(t *testing.T) {
+	setupTestLedger(t)
+	SetObserverTimeout(50 * time.Millisecond)
+	t.Cleanup(func() { SetObserverTimeout(0) })
+
+	release := make(chan struct{})
+	delivered := make(chan struct{})
+	RegisterObserver(func(string, []byte) {
+		<-release
+		close(delivered)
+	})
+	t.Cleanup(func() { observers.Store(nil) })
+
+	start := time.Now()
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("AppendRegister waited %v for a stuck observer", elapsed)
+	}
+
+	// Delivery carries on in the background once the observer unblocks
+	close(release)
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("observer never finished")
+	}
+}
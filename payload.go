@@ -0,0 +1,40 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// DefaultMaxCanonicalJSONBytes is the largest canonical JSON payload a register
+// accepts unless overridden.
+const DefaultMaxCanonicalJSONBytes = 1 << 20 // 1 MiB
+
+// ErrPayloadTooLarge is returned when a register's canonical JSON exceeds the size cap
+var ErrPayloadTooLarge = errors.New("canonical JSON payload too large")
+
+// maxCanonicalJSONBytes is the current cap; 0 disables it
+var maxCanonicalJSONBytes atomic.Int64
+
+func init() {
+	maxCanonicalJSONBytes.Store(DefaultMaxCanonicalJSONBytes)
+}
+
+// SetMaxCanonicalJSONBytes changes the largest canonical JSON payload accepted by
+// subsequent appends. 0 (or a negative n) removes the limit. Registers without a
+// payload are never affected.
+func SetMaxCanonicalJSONBytes(n int) {
+	if n < 0 {
+		n = 0
+	}
+	maxCanonicalJSONBytes.Store(int64(n))
+}
+
+// checkPayloadSize rejects canonicalJSON larger than the configured cap
+func checkPayloadSize(canonicalJSON []byte) error {
+	limit := maxCanonicalJSONBytes.Load()
+	if limit > 0 && int64(len(canonicalJSON)) > limit {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrPayloadTooLarge, len(canonicalJSON), limit)
+	}
+	return nil
+}
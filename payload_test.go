@@ -0,0 +1,63 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// setMaxPayload sets the canonical JSON cap for the duration of the test
+func setMaxPayload(t *testing.T, n int) {
+	t.Helper()
+	SetMaxCanonicalJSONBytes(n)
+	t.Cleanup(func() { SetMaxCanonicalJSONBytes(DefaultMaxCanonicalJSONBytes) })
+}
+
+func TestAppendRegister_PayloadCapBoundary(t *testing.T) {
+	path := setupTestLedger(t)
+	setMaxPayload(t, 16)
+
+	if err := AppendRegister(validObjectHash(), bytes.Repeat([]byte("a"), 16)); err != nil {
+		t.Fatalf("payload at the cap rejected: %v", err)
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	err = AppendRegister(validObjectHash(), bytes.Repeat([]byte("a"), 17))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected ErrPayloadTooLarge for cap+1, got %v", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("rejected payload modified the ledger")
+	}
+
+	// A register without payload is never capped
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Errorf("hash-only register rejected: %v", err)
+	}
+}
+
+func TestAppendRegister_PayloadCapDisabled(t *testing.T) {
+	setupTestLedger(t)
+	setMaxPayload(t, 0)
+
+	if err := AppendRegister(validObjectHash(), bytes.Repeat([]byte("a"), DefaultMaxCanonicalJSONBytes+1)); err != nil {
+		t.Fatalf("expected no limit with cap 0, got %v", err)
+	}
+}
+
+func TestAppendRegister_PayloadCapDefault(t *testing.T) {
+	setupTestLedger(t)
+
+	err := AppendRegister(validObjectHash(), bytes.Repeat([]byte("a"), DefaultMaxCanonicalJSONBytes+1))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("expected ErrPayloadTooLarge above the default cap, got %v", err)
+	}
+}
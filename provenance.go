@@ -0,0 +1,84 @@
+package ledger
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// ErrUnsignedRegister is returned by VerifyRegisterSignature for a register that
+// carries no signature. Callers replaying a mixed ledger may skip such entries.
+var ErrUnsignedRegister = errors.New("register is not signed")
+
+// AppendSignedRegister appends a register whose canonical JSON is signed by the submitter.
+//
+// The signature must be an Ed25519 signature over the SHA-256 of canonicalJSON
+// (the message sign.SignHashHex signs), made by pubHex. canonicalJSON must hash
+// to objectHashHex. The signature is verified before anything is written, so
+// the stored entry always replays and verifies end to end.
+//
+// Returns ErrInvalidHex for malformed inputs, sign.ErrVerificationFailed if the
+// signature does not match the payload; otherwise the same errors as AppendRegister.
+func AppendSignedRegister(objectHashHex string, canonicalJSON []byte, sigHex, pubHex string) error {
+	if len(canonicalJSON) == 0 {
+		return fmt.Errorf("%w: a signed register requires canonical JSON", ErrInvalidHex)
+	}
+	if got := ComputeObjectHash(canonicalJSON); got != objectHashHex {
+		return fmt.Errorf("%w: canonical JSON hashes to %s, not object_hash_hex %s", sign.ErrVerificationFailed, got, objectHashHex)
+	}
+	if !hex128Pattern.MatchString(sigHex) {
+		return fmt.Errorf("%w: signature must be 128 lowercase hex chars, got %q", ErrInvalidHex, sigHex)
+	}
+	if !hex64Pattern.MatchString(pubHex) {
+		return fmt.Errorf("%w: public_key must be 64 lowercase hex chars, got %q", ErrInvalidHex, pubHex)
+	}
+	if _, err := sign.VerifyHashHex(objectHashHex, sigHex, pubHex); err != nil {
+		return err
+	}
+
+	entry, err := newRegisterEntry(objectHashHex, canonicalJSON, now())
+	if err != nil {
+		return err
+	}
+	entry.Signature = sigHex
+	entry.PublicKey = pubHex
+
+	return appendEntry(entry)
+}
+
+// VerifyRegisterSignature re-derives the hash from a register's stored canonical
+// JSON and verifies the entry's signature over it.
+//
+// The hash is recomputed from the payload, never taken from ObjectHashHex, so a
+// tampered payload fails even if the stored hash and signature are untouched.
+//
+// Returns:
+//   - (true, nil) if the payload hashes to ObjectHashHex and the signature verifies
+//   - (false, ErrUnsignedRegister) if the entry has no signature
+//   - (false, sign.ErrVerificationFailed) if the payload or signature does not match
+//   - (false, error) for a signed entry without a decodable payload
+func VerifyRegisterSignature(entry RegisterEntry) (bool, error) {
+	if entry.Signature == "" && entry.PublicKey == "" {
+		return false, ErrUnsignedRegister
+	}
+
+	entry, err := decompressRegister(0, nil, entry)
+	if err != nil {
+		return false, err
+	}
+	if entry.CanonicalJSONB64 == "" {
+		return false, corruptLine(0, nil, "signed register has no canonical JSON")
+	}
+	payload, err := base64.StdEncoding.DecodeString(entry.CanonicalJSONB64)
+	if err != nil {
+		return false, corruptLine(0, nil, "invalid canonical_json_b64: %v", err)
+	}
+
+	recomputed := ComputeObjectHash(payload)
+	if recomputed != entry.ObjectHashHex {
+		return false, fmt.Errorf("%w: canonical JSON hashes to %s, not object_hash_hex %s", sign.ErrVerificationFailed, recomputed, entry.ObjectHashHex)
+	}
+	return sign.VerifyHashHex(recomputed, entry.Signature, entry.PublicKey)
+}
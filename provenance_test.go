@@ -0,0 +1,97 @@
+package ledger
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// appendSigned registers payload signed with testSeedHex and returns the stored entry
+func appendSigned(t *testing.T, payload []byte) RegisterEntry {
+	t.Helper()
+	hash := ComputeObjectHash(payload)
+	sig, pub, err := sign.SignHashHex(hash, testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex failed: %v", err)
+	}
+	if err := AppendSignedRegister(hash, payload, sig, pub); err != nil {
+		t.Fatalf("AppendSignedRegister failed: %v", err)
+	}
+	reg, found, err := GetRegisterByHash(hash)
+	if err != nil || !found {
+		t.Fatalf("GetRegisterByHash: found=%v err=%v", found, err)
+	}
+	return *reg
+}
+
+func TestVerifyRegisterSignature_Signed(t *testing.T) {
+	setupTestLedger(t)
+	t.Cleanup(func() { SetCanonicalCompression(false) })
+
+	for _, compressed := range []bool{false, true} {
+		SetCanonicalCompression(compressed)
+		reg := appendSigned(t, []byte(`{"doc":"contract","n":1}`))
+		if reg.Signature == "" || reg.PublicKey == "" {
+			t.Fatalf("signature not stored: %+v", reg)
+		}
+		if ok, err := VerifyRegisterSignature(reg); !ok || err != nil {
+			t.Errorf("compressed=%v: VerifyRegisterSignature = %v, %v; want true", compressed, ok, err)
+		}
+	}
+}
+
+func TestVerifyRegisterSignature_TamperedPayload(t *testing.T) {
+	setupTestLedger(t)
+	reg := appendSigned(t, []byte(`{"amount":100}`))
+
+	reg.CanonicalJSONB64 = base64.StdEncoding.EncodeToString([]byte(`{"amount":900}`))
+	if ok, err := VerifyRegisterSignature(reg); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed for tampered payload, got %v, %v", ok, err)
+	}
+
+	// Rewriting the hash to match the new payload still breaks the signature
+	reg.ObjectHashHex = ComputeObjectHash([]byte(`{"amount":900}`))
+	if ok, err := VerifyRegisterSignature(reg); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed for re-hashed payload, got %v, %v", ok, err)
+	}
+}
+
+func TestVerifyRegisterSignature_Unsigned(t *testing.T) {
+	setupTestLedger(t)
+
+	payload := []byte(`{"k":"v"}`)
+	if err := AppendRegister(ComputeObjectHash(payload), payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	reg, _, err := GetRegisterByHash(ComputeObjectHash(payload))
+	if err != nil {
+		t.Fatalf("GetRegisterByHash failed: %v", err)
+	}
+
+	if ok, err := VerifyRegisterSignature(*reg); ok || !errors.Is(err, ErrUnsignedRegister) {
+		t.Fatalf("expected ErrUnsignedRegister, got %v, %v", ok, err)
+	}
+}
+
+func TestAppendSignedRegister_RejectsBadSignature(t *testing.T) {
+	setupTestLedger(t)
+
+	payload := []byte(`{"k":"v"}`)
+	hash := ComputeObjectHash(payload)
+	sig, pub, err := sign.SignHashHex(testHash(1), testSeedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex failed: %v", err)
+	}
+
+	if err := AppendSignedRegister(hash, payload, sig, pub); !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed, got %v", err)
+	}
+	if err := AppendSignedRegister(testHash(2), payload, sig, pub); !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed for a hash/payload mismatch, got %v", err)
+	}
+	if pending, _ := ListPendingRegisters(); len(pending) != 0 {
+		t.Fatalf("rejected registers were written: %+v", pending)
+	}
+}
@@ -0,0 +1,238 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidCount is returned by LastNRegisters for a non-positive n
+var ErrInvalidCount = errors.New("register count must be positive")
+
+// LookupOption selects which match a point lookup returns when a hash was registered more than once
+type LookupOption int
+
+const (
+	// FirstMatch returns the earliest register with the requested hash (default)
+	FirstMatch LookupOption = iota
+
+	// LatestMatch returns the most recent register with the requested hash
+	LatestMatch
+)
+
+// GetRegisterByHash returns the register entry recorded for objectHashHex.
+//
+// Parameters:
+//   - objectHashHex: hash of the object under the active algorithm (HashAlg):
+//     64 lowercase hex for sha256, 128 for sha512
+//   - opts: Optional LookupOption (FirstMatch or LatestMatch)
+//
+// Returns:
+//   - The matching RegisterEntry and found=true, or nil and found=false
+//   - ErrInvalidHex if objectHashHex is malformed, or a scan error
+func GetRegisterByHash(objectHashHex string, opts ...LookupOption) (*RegisterEntry, bool, error) {
+	if oh := activeObjectHash.Load(); !oh.pattern.MatchString(objectHashHex) {
+		return nil, false, fmt.Errorf("%w: object_hash_hex must be %d lowercase hex chars, got %q", ErrInvalidHex, oh.hexLen, objectHashHex)
+	}
+
+	mode := FirstMatch
+	if len(opts) > 0 {
+		mode = opts[len(opts)-1]
+	}
+
+	var match *RegisterEntry
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "register" {
+			return nil
+		}
+
+		reg, _, err := parseRegister(lineNum, line)
+		if err != nil {
+			return err
+		}
+		if reg.ObjectHashHex != objectHashHex {
+			return nil
+		}
+
+		match = &reg
+		if mode == FirstMatch {
+			return errStopScan
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return match, match != nil, nil
+}
+
+// LastSeal returns the most recent seal, or the seal a rotation anchor carries
+// over when the current file has none of its own.
+//
+// Returns:
+//   - The last SealEntry and found=true, or nil and found=false if nothing was ever sealed
+//   - A scan error if the ledger is corrupt or cannot be read
+func LastSeal() (*SealEntry, bool, error) {
+	seal, err := getLastSeal()
+	if err != nil {
+		return nil, false, err
+	}
+	return seal, seal != nil, nil
+}
+
+// LastNRegisters returns the last n registers in chronological (file) order,
+// or all of them if the ledger holds fewer than n.
+//
+// It makes one forward scan keeping only the raw lines of the n most recent
+// registers in a ring buffer, and decodes just those at the end, so memory is
+// O(n) regardless of ledger size.
+//
+// Returns ErrInvalidCount if n <= 0, or a scan error.
+func LastNRegisters(n int) ([]RegisterEntry, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidCount, n)
+	}
+
+	type rawLine struct {
+		lineNum int
+		line    []byte
+	}
+	var ring []rawLine
+	next := 0 // Slot the next register overwrites once the ring is full
+
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "register" {
+			return nil
+		}
+		if len(ring) < n {
+			ring = append(ring, rawLine{lineNum, append([]byte(nil), line...)})
+			return nil
+		}
+		ring[next].lineNum = lineNum
+		ring[next].line = append(ring[next].line[:0], line...)
+		next = (next + 1) % n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	registers := make([]RegisterEntry, 0, len(ring))
+	for i := range ring {
+		raw := ring[(next+i)%len(ring)]
+		reg, _, err := parseRegister(raw.lineNum, raw.line)
+		if err != nil {
+			return nil, err
+		}
+		registers = append(registers, reg)
+	}
+	return registers, nil
+}
+
+// ListSeals returns every seal of the current ledger file in ledger order.
+// Rotation anchors are not seals and are not listed.
+//
+// Returns an empty slice if nothing was sealed, or a scan error if the ledger
+// is corrupt or cannot be read.
+func ListSeals() ([]SealEntry, error) {
+	seals := []SealEntry{}
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "seal" {
+			return nil
+		}
+
+		seal, _, err := parseSeal(lineNum, line)
+		if err != nil {
+			return err
+		}
+		seals = append(seals, seal)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return seals, nil
+}
+
+// GetSealByEpoch returns the seal whose manifest carries epochID.
+//
+// Returns:
+//   - The matching SealEntry and found=true, or nil and found=false
+//   - A scan error if the ledger is corrupt or cannot be read
+func GetSealByEpoch(epochID int) (*SealEntry, bool, error) {
+	var match *SealEntry
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "seal" {
+			return nil
+		}
+
+		seal, _, err := parseSeal(lineNum, line)
+		if err != nil {
+			return err
+		}
+		if seal.Manifest.EpochID != epochID {
+			return nil
+		}
+
+		match = &seal
+		return errStopScan
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return match, match != nil, nil
+}
+
+// FindSealCovering returns the seal that closed the epoch a register stamped
+// registerTS belongs to: the earliest seal whose manifest timestamp is at or
+// after registerTS.
+//
+// Returns:
+//   - The covering SealEntry and found=true, or nil and found=false while the
+//     register is still pending
+//   - A scan error if the ledger is corrupt or cannot be read
+func FindSealCovering(registerTS time.Time) (*SealEntry, bool, error) {
+	var match *SealEntry
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "seal" {
+			return nil
+		}
+
+		seal, ts, err := parseSeal(lineNum, line)
+		if err != nil {
+			return err
+		}
+		if ts.Before(registerTS) {
+			return nil
+		}
+
+		match = &seal
+		return errStopScan
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return match, match != nil, nil
+}
+
+// CountByType tallies ledger entries by their type in a single scan, without
+// decoding them: "register", "seal", "header", "anchor", and any unknown type
+// under its literal string. An empty ledger yields an empty map.
+//
+// Returns ErrLedgerCorrupt if a line is not valid JSON, or a scan error.
+func CountByType() (map[string]int, error) {
+	counts := map[string]int{}
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		counts[entryType]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,293 @@
+package ledger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetRegisterByHash_Present(t *testing.T) {
+	setupTestLedger(t)
+
+	hash := validObjectHash()
+	if err := AppendRegister(hash, []byte(`{"k":"v"}`)); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	reg, found, err := GetRegisterByHash(hash)
+	if err != nil {
+		t.Fatalf("GetRegisterByHash failed: %v", err)
+	}
+	if !found || reg == nil {
+		t.Fatalf("expected register to be found")
+	}
+	if reg.ObjectHashHex != hash {
+		t.Errorf("ObjectHashHex = %s, want %s", reg.ObjectHashHex, hash)
+	}
+}
+
+func TestGetRegisterByHash_Absent(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	absent := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	reg, found, err := GetRegisterByHash(absent)
+	if err != nil {
+		t.Fatalf("GetRegisterByHash failed: %v", err)
+	}
+	if found || reg != nil {
+		t.Errorf("expected not found, got %+v", reg)
+	}
+}
+
+func TestGetRegisterByHash_Duplicates(t *testing.T) {
+	setupTestLedger(t)
+
+	hash := validObjectHash()
+	if err := AppendRegister(hash, []byte(`{"n":1}`)); err != nil {
+		t.Fatalf("AppendRegister 1 failed: %v", err)
+	}
+	if err := AppendRegister(hash, []byte(`{"n":2}`)); err != nil {
+		t.Fatalf("AppendRegister 2 failed: %v", err)
+	}
+
+	all, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+
+	first, found, err := GetRegisterByHash(hash)
+	if err != nil || !found {
+		t.Fatalf("GetRegisterByHash(first) found=%v err=%v", found, err)
+	}
+	if first.CanonicalJSONB64 != all[0].CanonicalJSONB64 {
+		t.Errorf("default lookup returned %s, want first entry %s", first.CanonicalJSONB64, all[0].CanonicalJSONB64)
+	}
+
+	latest, found, err := GetRegisterByHash(hash, LatestMatch)
+	if err != nil || !found {
+		t.Fatalf("GetRegisterByHash(latest) found=%v err=%v", found, err)
+	}
+	if latest.CanonicalJSONB64 != all[1].CanonicalJSONB64 {
+		t.Errorf("LatestMatch returned %s, want last entry %s", latest.CanonicalJSONB64, all[1].CanonicalJSONB64)
+	}
+}
+
+func TestGetRegisterByHash_InvalidHash(t *testing.T) {
+	setupTestLedger(t)
+
+	_, found, err := GetRegisterByHash("NOT-A-HASH")
+	if !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+	if found {
+		t.Errorf("expected found=false")
+	}
+}
+
+func TestGetSealByEpoch(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2, 1, 3)
+	seals := readSeals(t)
+
+	for epoch := 0; epoch < 3; epoch++ {
+		seal, found, err := GetSealByEpoch(epoch)
+		if err != nil || !found {
+			t.Fatalf("epoch %d: found=%v err=%v", epoch, found, err)
+		}
+		if seal.Manifest != seals[epoch].Manifest {
+			t.Errorf("epoch %d: manifest %+v, want %+v", epoch, seal.Manifest, seals[epoch].Manifest)
+		}
+	}
+
+	if seal, found, err := GetSealByEpoch(3); err != nil || found || seal != nil {
+		t.Fatalf("epoch 3: expected not found, got %+v found=%v err=%v", seal, found, err)
+	}
+}
+
+func TestListSeals(t *testing.T) {
+	setupTestLedger(t)
+
+	if seals, err := ListSeals(); err != nil || len(seals) != 0 {
+		t.Fatalf("empty ledger: got %d seals, err=%v", len(seals), err)
+	}
+
+	buildSealedLedger(t, 2, 1, 3)
+	if err := AppendRegister(testHash(99), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	want := readSeals(t)
+	got, err := ListSeals()
+	if err != nil {
+		t.Fatalf("ListSeals failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListSeals = %+v, want %+v", got, want)
+	}
+}
+
+func TestLastSeal(t *testing.T) {
+	setupTestLedger(t)
+
+	if seal, found, err := LastSeal(); err != nil || found || seal != nil {
+		t.Fatalf("empty ledger: got %+v found=%v err=%v", seal, found, err)
+	}
+
+	buildSealedLedger(t, 2, 1)
+	seals := readSeals(t)
+	seal, found, err := LastSeal()
+	if err != nil || !found || seal.Manifest != seals[1].Manifest {
+		t.Fatalf("LastSeal = %+v, %v, %v; want %+v", seal, found, err, seals[1].Manifest)
+	}
+}
+
+func TestLastNRegisters(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		if regs, err := LastNRegisters(5); err != nil || len(regs) != 0 {
+			t.Fatalf("empty ledger: %v, %v", regs, err)
+		}
+
+		buildSealedLedger(t, 3, 2)
+		appendN(t, 10, 2)
+		all := []string{testHash(0), testHash(1), testHash(2), testHash(3), testHash(4), testHash(10), testHash(11)}
+
+		for _, n := range []int{1, 3, 7, 20} {
+			regs, err := LastNRegisters(n)
+			if err != nil {
+				t.Fatalf("n=%d: LastNRegisters failed: %v", n, err)
+			}
+			want := all
+			if n < len(all) {
+				want = all[len(all)-n:]
+			}
+			got := make([]string, len(regs))
+			for i, reg := range regs {
+				got[i] = reg.ObjectHashHex
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("n=%d: got %v, want %v", n, got, want)
+			}
+		}
+
+		for _, n := range []int{0, -1} {
+			if _, err := LastNRegisters(n); !errors.Is(err, ErrInvalidCount) {
+				t.Errorf("n=%d: expected ErrInvalidCount, got %v", n, err)
+			}
+		}
+	})
+}
+
+func TestFindSealCovering(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2, 2)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	regs, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed: %v", err)
+	}
+	seals := readSeals(t)
+
+	tests := []struct {
+		name      string
+		reg       int
+		wantEpoch int // -1 for pending
+	}{
+		{"before first seal", 1, 0},
+		{"between seals", 2, 1},
+		{"after last seal", 4, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := time.Parse(time.RFC3339Nano, regs[tt.reg].Timestamp)
+			if err != nil {
+				t.Fatalf("invalid register timestamp: %v", err)
+			}
+			seal, found, err := FindSealCovering(ts)
+			if err != nil {
+				t.Fatalf("FindSealCovering failed: %v", err)
+			}
+			if tt.wantEpoch < 0 {
+				if found || seal != nil {
+					t.Errorf("expected pending register, got seal %+v", seal)
+				}
+				return
+			}
+			if !found || seal == nil {
+				t.Fatalf("expected a covering seal")
+			}
+			if seal.Manifest.MerkleRoot != seals[tt.wantEpoch].Manifest.MerkleRoot {
+				t.Errorf("covering seal epoch %d, want %d", seal.Manifest.EpochID, tt.wantEpoch)
+			}
+		})
+	}
+}
+
+func TestFindSealCovering_SealTimestampInclusive(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1)
+
+	seal, found, err := FindSealCovering(mustLastSealTimestamp(t))
+	if err != nil || !found {
+		t.Fatalf("FindSealCovering found=%v err=%v", found, err)
+	}
+	if seal.Manifest.EpochID != 0 {
+		t.Errorf("EpochID = %d, want 0", seal.Manifest.EpochID)
+	}
+}
+
+func TestCountByType_MixedLedger(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	buildSealedLedger(t, 2, 1)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, path, `{"type":"note","text":"future entry type"}`+"\n")
+
+	counts, err := CountByType()
+	if err != nil {
+		t.Fatalf("CountByType failed: %v", err)
+	}
+	want := map[string]int{"header": 1, "register": 4, "seal": 2, "note": 1}
+	if len(counts) != len(want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+	for typ, n := range want {
+		if counts[typ] != n {
+			t.Errorf("counts[%q] = %d, want %d", typ, counts[typ], n)
+		}
+	}
+}
+
+func TestCountByType_EmptyLedger(t *testing.T) {
+	setupTestLedger(t)
+
+	counts, err := CountByType()
+	if err != nil {
+		t.Fatalf("CountByType failed: %v", err)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("expected an empty map, got %v", counts)
+	}
+}
+
+func TestCountByType_Corrupt(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, path, "{broken\n")
+
+	if _, err := CountByType(); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("expected ErrLedgerCorrupt, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package ledger
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrOffsetOutOfRange is returned by ReadFrom when offset is negative or past the
+// end of the ledger, typically because it was rotated or truncated. Callers
+// should start over from offset 0.
+var ErrOffsetOutOfRange = errors.New("offset out of range")
+
+// ReadFrom returns the registers stored after byte offset and the offset just
+// past the last complete line read, to pass to the next call.
+//
+// offset must be 0 or a value previously returned by ReadFrom for the same
+// ledger (the start of a line). A trailing line without its newline is still
+// being written: it is neither returned nor consumed, so newOffset stops before
+// it. Offsets are those of the JSONL file; for stores other than FileStore they
+// are the offsets the lines would have in the equivalent file. Corrupt lines
+// are reported with their byte offset, since line numbers are unknown mid-file.
+func ReadFrom(offset int64) (entries []RegisterEntry, newOffset int64, err error) {
+	if offset < 0 {
+		return nil, 0, fmt.Errorf("%w: %d", ErrOffsetOutOfRange, offset)
+	}
+
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	newOffset = offset
+	visit := func(line []byte) error {
+		at := newOffset
+		newOffset += int64(len(line)) + 1
+
+		if len(line) == 0 {
+			return nil
+		}
+		var entry struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return corruptLine(0, line, "invalid JSON at byte offset %d: %v", at, err)
+		}
+		if entry.Type != "register" {
+			return nil
+		}
+
+		reg, _, err := parseRegister(0, line)
+		if err != nil {
+			return fmt.Errorf("at byte offset %d: %w", at, err)
+		}
+		entries = append(entries, reg)
+		return nil
+	}
+
+	if err := linesFrom(currentStore(), offset, visit); err != nil {
+		return nil, offset, err
+	}
+
+	return entries, newOffset, nil
+}
+
+// linesFrom calls fn with each complete line of st starting at byte offset,
+// without the newline. Returns ErrOffsetOutOfRange for an offset past the end.
+func linesFrom(st Store, offset int64, fn func(line []byte) error) error {
+	if fs, ok := st.(*FileStore); ok {
+		return fs.completeLinesFrom(offset, fn)
+	}
+	return storeLinesFrom(st, offset, fn)
+}
+
+// completeLinesFrom calls fn with each newline-terminated line starting at byte
+// offset, without the newline. A final unterminated line is skipped.
+func (s *FileStore) completeLinesFrom(offset int64, fn func(line []byte) error) error {
+	file, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		if offset > 0 {
+			return fmt.Errorf("%w: %d past end of empty ledger", ErrOffsetOutOfRange, offset)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("%w: failed to stat ledger: %v", ErrLedgerIO, err)
+	}
+	if offset > info.Size() {
+		return fmt.Errorf("%w: %d past end of ledger (%d bytes)", ErrOffsetOutOfRange, offset, info.Size())
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("%w: failed to seek ledger: %v", ErrLedgerIO, err)
+	}
+
+	r := bufio.NewReader(file)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// Partial (or no) trailing line: leave it for the next call
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+		}
+		if err := fn(line[:len(line)-1]); err != nil {
+			return err
+		}
+	}
+}
+
+// storeLinesFrom calls fn with each line of st whose JSONL offset is at or
+// after offset. Every stored line is complete.
+func storeLinesFrom(st Store, offset int64, fn func(line []byte) error) error {
+	var pos int64
+	err := st.Iterate(func(line []byte) error {
+		start := pos
+		pos += int64(len(line)) + 1
+		if start < offset {
+			return nil
+		}
+		return fn(line)
+	})
+	if err != nil {
+		return err
+	}
+	if offset > pos {
+		return fmt.Errorf("%w: %d past end of ledger (%d bytes)", ErrOffsetOutOfRange, offset, pos)
+	}
+	return nil
+}
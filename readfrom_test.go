@@ -0,0 +1,111 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadFrom_Incremental(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			if err := AppendRegister(testHash(i), nil); err != nil {
+				t.Fatalf("AppendRegister failed: %v", err)
+			}
+		}
+
+		entries, off, err := ReadFrom(0)
+		if err != nil {
+			t.Fatalf("ReadFrom(0) failed: %v", err)
+		}
+		if len(entries) != 2 || entries[0].ObjectHashHex != testHash(0) || entries[1].ObjectHashHex != testHash(1) {
+			t.Fatalf("first read = %+v, want registers 0 and 1", entries)
+		}
+		_, size, err := storeEnd(currentStore())
+		if err != nil {
+			t.Fatalf("storeEnd failed: %v", err)
+		}
+		if off != size {
+			t.Fatalf("offset = %d, want ledger size %d", off, size)
+		}
+
+		// Nothing new: same offset, no entries
+		entries, again, err := ReadFrom(off)
+		if err != nil || len(entries) != 0 || again != off {
+			t.Fatalf("idle read = (%d entries, %d, %v), want (0, %d, nil)", len(entries), again, err, off)
+		}
+
+		for i := 2; i < 4; i++ {
+			if err := AppendRegister(testHash(i), nil); err != nil {
+				t.Fatalf("AppendRegister failed: %v", err)
+			}
+		}
+		entries, next, err := ReadFrom(off)
+		if err != nil {
+			t.Fatalf("ReadFrom(%d) failed: %v", off, err)
+		}
+		if len(entries) != 2 || entries[0].ObjectHashHex != testHash(2) || entries[1].ObjectHashHex != testHash(3) {
+			t.Fatalf("second read = %+v, want only registers 2 and 3", entries)
+		}
+		if _, size, _ := storeEnd(currentStore()); next != size {
+			t.Fatalf("advanced offset = %d, want %d", next, size)
+		}
+	})
+}
+
+func TestReadFrom_SkipsPartialTrailingLine(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	_, off, err := ReadFrom(0)
+	if err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+
+	appendRaw(t, ledgerPath, `{"type":"register","object_hash_hex":"`)
+	entries, next, err := ReadFrom(off)
+	if err != nil {
+		t.Fatalf("ReadFrom with torn tail failed: %v", err)
+	}
+	if len(entries) != 0 || next != off {
+		t.Fatalf("torn tail consumed: %d entries, offset %d, want 0 and %d", len(entries), next, off)
+	}
+}
+
+func TestReadFrom_OffsetOutOfRange(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		if err := AppendRegister(testHash(0), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+		for _, off := range []int64{-1, 1 << 20} {
+			if _, _, err := ReadFrom(off); !errors.Is(err, ErrOffsetOutOfRange) {
+				t.Errorf("ReadFrom(%d) error = %v, want ErrOffsetOutOfRange", off, err)
+			}
+		}
+	})
+}
+
+func TestReadFrom_CorruptLineReportsOffset(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	info, err := os.Stat(ledgerPath)
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	appendRaw(t, ledgerPath, "not json\n")
+
+	_, _, err = ReadFrom(info.Size())
+	if !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("expected ErrLedgerCorrupt, got %v", err)
+	}
+	if want := fmt.Sprintf("byte offset %d", info.Size()); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not mention %q", err, want)
+	}
+}
@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotReady is returned by CheckReady when the ledger cannot serve reads or appends
+var ErrNotReady = errors.New("ledger not ready")
+
+// readyChunk is how much of the file tail CheckReady reads at a time
+const readyChunk = 64 * 1024
+
+// CheckReady reports whether the file ledger is usable, without writing to it:
+//   - the ledger directory exists and the process may write to it
+//   - the ledger file, if it exists, can be opened for reading and the process
+//     may append to it
+//   - its last entry is complete, newline-terminated JSON with a type
+//
+// A missing ledger file is ready: the first append creates it. With any Store
+// other than FileStore it always succeeds. Failures wrap ErrNotReady with the reason.
+func CheckReady() error {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	fs, ok := currentStore().(*FileStore)
+	if !ok {
+		return nil
+	}
+
+	dir := filepath.Dir(fs.Path)
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("%w: ledger directory: %v", ErrNotReady, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: ledger directory %s is not a directory", ErrNotReady, dir)
+	}
+	if err := pathWritable(dir); err != nil {
+		return fmt.Errorf("%w: ledger directory %s is not writable: %v", ErrNotReady, dir, err)
+	}
+
+	file, err := os.Open(fs.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: cannot open ledger: %v", ErrNotReady, err)
+	}
+	defer file.Close()
+	if err := pathWritable(fs.Path); err != nil {
+		return fmt.Errorf("%w: ledger %s is not writable: %v", ErrNotReady, fs.Path, err)
+	}
+
+	line, lineEnd, err := lastLine(file)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read ledger: %v", ErrNotReady, err)
+	}
+	if line == nil {
+		return nil
+	}
+	if !lineEnd {
+		return fmt.Errorf("%w: final line has no newline (interrupted append, see RepairTruncatedTail)", ErrNotReady)
+	}
+	var entry struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(line, &entry); err != nil || entry.Type == "" {
+		return fmt.Errorf("%w: final entry does not parse", ErrNotReady)
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of f, read backwards from the end,
+// and whether a newline follows it. It returns a nil line for an empty file.
+func lastLine(f *os.File) ([]byte, bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+
+	var tail []byte
+	pos := info.Size()
+	for pos > 0 {
+		n := int64(readyChunk)
+		if n > pos {
+			n = pos
+		}
+		pos -= n
+		chunk := make([]byte, n)
+		if _, err := f.ReadAt(chunk, pos); err != nil && !errors.Is(err, io.EOF) {
+			return nil, false, err
+		}
+		tail = append(chunk, tail...)
+
+		// Done once a line break precedes the last non-empty line
+		content := bytes.TrimRight(tail, "\n")
+		if len(content) > 0 && bytes.LastIndexByte(content, '\n') >= 0 {
+			break
+		}
+	}
+
+	content := bytes.TrimRight(tail, "\n")
+	if len(content) == 0 {
+		return nil, false, nil
+	}
+	lineEnd := len(content) < len(tail)
+	return content[bytes.LastIndexByte(content, '\n')+1:], lineEnd, nil
+}
@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckReady_LongFinalEntry(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	// A final entry spanning several tail chunks, followed by blank lines
+	payload := []byte(`{"k":"` + strings.Repeat("x", 3*readyChunk) + `"}`)
+	if err := AppendRegister(validObjectHash(), payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, path, "\n\n")
+
+	if err := CheckReady(); err != nil {
+		t.Fatalf("CheckReady failed: %v", err)
+	}
+}
+
+func TestCheckReady_UnparseableFinalEntry(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, path, "not json\n")
+
+	if err := CheckReady(); !errors.Is(err, ErrNotReady) || !strings.Contains(err.Error(), "does not parse") {
+		t.Fatalf("expected ErrNotReady for a corrupt final entry, got %v", err)
+	}
+}
+
+func TestCheckReady_LedgerPathIsDirectory(t *testing.T) {
+	path := setupTestLedger(t)
+	if err := os.Mkdir(path, 0755); err != nil {
+		t.Fatalf("mkdir failed: %v", err)
+	}
+
+	if err := CheckReady(); !errors.Is(err, ErrNotReady) {
+		t.Fatalf("expected ErrNotReady, got %v", err)
+	}
+}
+
+func TestCheckReady_MemStore(t *testing.T) {
+	setupMemLedger(t)
+	if err := CheckReady(); err != nil {
+		t.Fatalf("CheckReady failed: %v", err)
+	}
+}
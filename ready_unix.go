@@ -0,0 +1,12 @@
+//go:build unix
+
+package ledger
+
+import "syscall"
+
+// pathWritable asks the kernel whether the process may write to path: create
+// files in it for a directory, append to it for a file. Nothing is written.
+func pathWritable(path string) error {
+	const wOK = 0x2
+	return syscall.Access(path, wOK)
+}
@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Receipt records where an appended register landed, so a caller can fetch the
+// exact line later (seek to ByteOffset) or build a proof without rescanning.
+type Receipt struct {
+	ObjectHashHex string `json:"object_hash_hex"`
+	Timestamp     string `json:"timestamp"`   // CanonTimestampLayout, as stored
+	ByteOffset    int64  `json:"byte_offset"` // Offset of the first byte of the line
+	LineNumber    int    `json:"line_number"` // 1-based, empty lines included
+}
+
+// AppendRegisterReceipt is AppendRegister returning a Receipt for the stored entry.
+//
+// The offset and line number are taken under the ledger lock right before the
+// write, so concurrent appends through this package cannot move them. For a
+// FileStore the offset is the file size before the write; for other stores it
+// is the offset the line would have in the equivalent JSONL file. Each call
+// reads only what was appended since the previous one, not the whole ledger.
+func AppendRegisterReceipt(objectHashHex string, canonicalJSON []byte) (Receipt, error) {
+	entry, err := newRegisterEntry(objectHashHex, canonicalJSON, now())
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+	lines, size, err := storeEnd(st)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	if err := appendEntryTo(st, entry); err != nil {
+		return Receipt{}, err
+	}
+
+	return Receipt{
+		ObjectHashHex: entry.ObjectHashHex,
+		Timestamp:     entry.Timestamp,
+		ByteOffset:    size,
+		LineNumber:    lines + 1,
+	}, nil
+}
+
+// receiptEnd is where the ledger ended at the last AppendRegisterReceipt, so
+// the next one reads only the lines appended since instead of the whole ledger.
+type receiptEnd struct {
+	store  Store  // ledgerStore it was taken on, nil for the file at path
+	path   string // ledgerPath it was taken on
+	offset int64  // Byte offset just past the last complete line
+	lines  int    // Number of complete lines before offset
+	last   []byte // Copy of the line ending at offset, unused at the top
+}
+
+// receiptCursor is the receiptEnd of the current ledger. Guarded by ledgerMutex.
+var receiptCursor receiptEnd
+
+// storeEnd returns the number of lines in st and its size in bytes, a torn
+// final line included. It resumes from receiptCursor: the cursor's own line is
+// re-read and must be unchanged, otherwise (rotation, repair, a rewrite by
+// another tool) st is counted again from the top. The caller must hold
+// ledgerMutex for writing.
+func storeEnd(st Store) (lines int, size int64, err error) {
+	if receiptCursor.store != ledgerStore || receiptCursor.path != ledgerPath {
+		receiptCursor = receiptEnd{store: ledgerStore, path: ledgerPath}
+	}
+	err = advanceReceiptCursor(st)
+	if errors.Is(err, errCursorStale) {
+		receiptCursor = receiptEnd{store: ledgerStore, path: ledgerPath}
+		err = advanceReceiptCursor(st)
+	}
+	if err != nil {
+		receiptCursor = receiptEnd{}
+		return 0, 0, err
+	}
+	lines, size = receiptCursor.lines, receiptCursor.offset
+
+	// The file itself is authoritative for the offset: a torn tail still
+	// occupies bytes and a line of its own
+	if fs, ok := st.(*FileStore); ok {
+		info, err := os.Stat(fs.Path)
+		if os.IsNotExist(err) {
+			return lines, 0, nil
+		}
+		if err != nil {
+			return 0, 0, fmt.Errorf("%w: failed to stat ledger: %v", ErrLedgerIO, err)
+		}
+		if info.Size() > size {
+			lines++
+		}
+		size = info.Size()
+	}
+
+	return lines, size, nil
+}
+
+// errCursorStale reports that receiptCursor no longer matches the ledger
+var errCursorStale = errors.New("receipt cursor stale")
+
+// advanceReceiptCursor moves receiptCursor past every complete line of st
+// appended since it was taken. It returns errCursorStale if the cursor's line
+// is gone or changed.
+func advanceReceiptCursor(st Store) error {
+	cur := &receiptCursor
+	start, checkAnchor := int64(0), cur.lines > 0
+	if checkAnchor {
+		start = cur.offset - int64(len(cur.last)) - 1
+	}
+
+	err := linesFrom(st, start, func(line []byte) error {
+		if checkAnchor {
+			checkAnchor = false
+			if !bytes.Equal(line, cur.last) {
+				return errCursorStale
+			}
+			return nil
+		}
+		cur.offset += int64(len(line)) + 1
+		cur.lines++
+		cur.last = append(cur.last[:0], line...)
+		return nil
+	})
+	if errors.Is(err, ErrOffsetOutOfRange) || (err == nil && checkAnchor) {
+		// The ledger now ends before the cursor's line
+		return errCursorStale
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// readLineAt returns the ledger line that starts at offset
+func readLineAt(t *testing.T, path string, offset int64) []byte {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		t.Fatalf("failed to seek ledger: %v", err)
+	}
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("failed to read line at %d: %v", offset, err)
+	}
+	return line[:len(line)-1]
+}
+
+func TestAppendRegisterReceipt_OffsetSeeksToLine(t *testing.T) {
+	path := setupTestLedger(t)
+
+	if err := InitLedger(); err != nil {
+		t.Fatalf("InitLedger failed: %v", err)
+	}
+	var receipts []Receipt
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			if err := AppendSeal(signedManifest(t)); err != nil {
+				t.Fatalf("AppendSeal failed: %v", err)
+			}
+		}
+		r, err := AppendRegisterReceipt(testHash(i), []byte(`{"i":1}`))
+		if err != nil {
+			t.Fatalf("AppendRegisterReceipt failed: %v", err)
+		}
+		receipts = append(receipts, r)
+	}
+
+	// header, register, register, seal, register
+	wantLines := []int{2, 3, 5}
+	for i, r := range receipts {
+		if r.LineNumber != wantLines[i] {
+			t.Errorf("receipt %d: LineNumber = %d, want %d", i, r.LineNumber, wantLines[i])
+		}
+
+		var entry RegisterEntry
+		if err := json.Unmarshal(readLineAt(t, path, r.ByteOffset), &entry); err != nil {
+			t.Fatalf("receipt %d: offset %d is not the start of an entry: %v", i, r.ByteOffset, err)
+		}
+		if entry.ObjectHashHex != r.ObjectHashHex || entry.Timestamp != r.Timestamp || r.ObjectHashHex != testHash(i) {
+			t.Errorf("receipt %d = %+v, line holds %+v", i, r, entry)
+		}
+	}
+}
+
+func TestAppendRegisterReceipt_LineNumberMatchesScan(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		if err := AppendRegister(testHash(0), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+		r, err := AppendRegisterReceipt(testHash(1), nil)
+		if err != nil {
+			t.Fatalf("AppendRegisterReceipt failed: %v", err)
+		}
+
+		found := 0
+		err = scanLedger(func(lineNum int, entryType string, line []byte) error {
+			reg, _, err := parseRegister(lineNum, line)
+			if err == nil && reg.ObjectHashHex == r.ObjectHashHex {
+				found = lineNum
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatalf("scanLedger failed: %v", err)
+		}
+		if found != r.LineNumber || found != 2 {
+			t.Errorf("LineNumber = %d, register found on line %d", r.LineNumber, found)
+		}
+	})
+}
+
+func TestAppendRegisterReceipt_InvalidHash(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, err := AppendRegisterReceipt("not-a-hash", nil); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+}
+
+func TestAppendRegisterReceipt_ReadsOnlyNewLines(t *testing.T) {
+	path := setupTestLedger(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := AppendRegisterReceipt(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegisterReceipt failed: %v", err)
+		}
+	}
+
+	// Split line 1 in two without changing the size: a count from the top
+	// would now see one line more
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	data[bytes.IndexByte(data, '\n')/2] = '\n'
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+
+	// Another writer's line in between is still counted
+	appendRaw(t, path, "\n")
+	r, err := AppendRegisterReceipt(testHash(3), nil)
+	if err != nil {
+		t.Fatalf("AppendRegisterReceipt failed: %v", err)
+	}
+	if r.LineNumber != 5 || r.ByteOffset != int64(len(data))+1 {
+		t.Fatalf("receipt = %+v, want line 5 at offset %d", r, len(data)+1)
+	}
+}
+
+func TestAppendRegisterReceipt_LedgerRewritten(t *testing.T) {
+	path := setupTestLedger(t)
+
+	for i := 0; i < 3; i++ {
+		if _, err := AppendRegisterReceipt(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegisterReceipt failed: %v", err)
+		}
+	}
+
+	// Replaced by a shorter ledger whose last line differs from the remembered one
+	first := readLineAt(t, path, 0)
+	if err := os.WriteFile(path, append(first, '\n'), 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+	r, err := AppendRegisterReceipt(testHash(3), nil)
+	if err != nil {
+		t.Fatalf("AppendRegisterReceipt failed: %v", err)
+	}
+	if r.LineNumber != 2 || r.ByteOffset != int64(len(first))+1 {
+		t.Fatalf("receipt = %+v, want line 2 at offset %d", r, len(first)+1)
+	}
+	if got := readLineAt(t, path, r.ByteOffset); !bytes.Contains(got, []byte(testHash(3))) {
+		t.Fatalf("offset %d holds %s", r.ByteOffset, got)
+	}
+}
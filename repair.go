@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RepairTruncatedTail recovers a ledger whose final append was interrupted.
+//
+// A crash between opening the file and finishing the write can leave a trailing
+// line that is missing its newline or is only half-written JSON. Such a line makes
+// every scan fail with ErrLedgerCorrupt. This function:
+//   - appends the missing newline if the final line is complete, valid JSON
+//   - truncates the final line if it does not parse and lacks its newline
+//
+// It is deliberately conservative: if any line other than the final one is
+// corrupt, or the final line is invalid but was written out in full with its
+// newline, nothing is modified and ErrLedgerCorrupt is returned. An append
+// writes the newline last, so a damaged terminated line is evidence of
+// tampering, not of an interrupted append.
+//
+// Only the file backend can be torn; with any other Store this is a no-op.
+//
+// Returns true if the file was modified.
+func RepairTruncatedTail() (bool, error) {
+	ledgerMutex.Lock()
+	defer ledgerMutex.Unlock()
+
+	fs, ok := currentStore().(*FileStore)
+	if !ok {
+		return false, nil
+	}
+	path := fs.Path
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var offset, lastStart int64
+	var lastLine []byte
+	lineNum := 0
+	badLine := 0
+	var badRaw []byte
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNum++
+			if badLine != 0 {
+				// A damaged line followed by more data is not a torn tail
+				return false, corruptLine(badLine, badRaw, "corrupt line is not the final line, refusing to repair")
+			}
+
+			content := bytes.TrimSuffix(line, []byte("\n"))
+			if len(content) > 0 && !json.Valid(content) {
+				badLine = lineNum
+				badRaw = content
+			}
+
+			lastStart = offset
+			lastLine = line
+			offset += int64(len(line))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+		}
+	}
+
+	if len(lastLine) == 0 {
+		return false, nil
+	}
+
+	// Damaged final line: drop it entirely, never touching prior lines
+	if badLine != 0 {
+		if bytes.HasSuffix(lastLine, []byte("\n")) {
+			return false, corruptLine(badLine, badRaw, "corrupt final line is newline-terminated, not a torn append; refusing to repair")
+		}
+		if err := file.Truncate(lastStart); err != nil {
+			return false, fmt.Errorf("%w: failed to truncate partial line: %v", ErrLedgerIO, err)
+		}
+		// The dropped line may have been indexed; later appends would hide the shrink
+		os.Remove(IndexPath(path))
+		bumpLedgerVersion()
+		return true, nil
+	}
+
+	// Complete entry whose newline never made it to disk
+	if !bytes.HasSuffix(lastLine, []byte("\n")) {
+		if _, err := file.WriteAt([]byte("\n"), offset); err != nil {
+			return false, fmt.Errorf("%w: failed to write newline: %v", ErrLedgerIO, err)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
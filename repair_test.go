@@ -0,0 +1,171 @@
+package ledger
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appendRaw writes raw bytes to the end of the ledger, bypassing validation
+func appendRaw(t *testing.T, path string, raw string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("failed to open ledger: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(raw); err != nil {
+		t.Fatalf("failed to write raw bytes: %v", err)
+	}
+}
+
+func TestRepairTruncatedTail_HalfWrittenLine(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	before, _ := os.ReadFile(ledgerPath)
+
+	// Simulate a crash midway through the JSON of the next entry
+	appendRaw(t, ledgerPath, `{"type":"register","canon":"v1.0","timest`)
+
+	if _, err := ListRegistersSince(time.Time{}); err == nil {
+		t.Fatalf("expected ListRegistersSince to fail on torn tail")
+	}
+
+	repaired, err := RepairTruncatedTail()
+	if err != nil {
+		t.Fatalf("RepairTruncatedTail failed: %v", err)
+	}
+	if !repaired {
+		t.Fatalf("expected repaired=true")
+	}
+
+	after, _ := os.ReadFile(ledgerPath)
+	if string(after) != string(before) {
+		t.Errorf("repair changed complete lines:\nbefore: %q\nafter:  %q", before, after)
+	}
+
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed after repair: %v", err)
+	}
+	if len(registers) != 1 {
+		t.Errorf("expected 1 register, got %d", len(registers))
+	}
+}
+
+func TestRepairTruncatedTail_MissingNewline(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// Complete JSON, but the newline never reached the disk
+	data, _ := os.ReadFile(ledgerPath)
+	line := strings.TrimSuffix(string(data), "\n")
+	appendRaw(t, ledgerPath, line)
+
+	repaired, err := RepairTruncatedTail()
+	if err != nil {
+		t.Fatalf("RepairTruncatedTail failed: %v", err)
+	}
+	if !repaired {
+		t.Fatalf("expected repaired=true")
+	}
+
+	// The completed entry must be kept, and further appends must land on their own line
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil {
+		t.Fatalf("ListRegistersSince failed after repair: %v", err)
+	}
+	if len(registers) != 3 {
+		t.Errorf("expected 3 registers, got %d", len(registers))
+	}
+}
+
+func TestRepairTruncatedTail_RefusesInteriorCorruption(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	appendRaw(t, ledgerPath, "this is not valid json\n")
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	before, _ := os.ReadFile(ledgerPath)
+
+	repaired, err := RepairTruncatedTail()
+	if err == nil {
+		t.Fatalf("expected error for interior corruption")
+	}
+	if repaired {
+		t.Errorf("expected repaired=false")
+	}
+	if !strings.Contains(err.Error(), "ledger corrupt") {
+		t.Errorf("expected ErrLedgerCorrupt, got: %v", err)
+	}
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) || corrupt.LineNum != 2 {
+		t.Errorf("expected CorruptError for line 2, got %#v", err)
+	}
+
+	after, _ := os.ReadFile(ledgerPath)
+	if string(after) != string(before) {
+		t.Errorf("ledger modified despite refusal")
+	}
+}
+
+func TestRepairTruncatedTail_CleanLedger(t *testing.T) {
+	setupTestLedger(t)
+
+	repaired, err := RepairTruncatedTail()
+	if err != nil || repaired {
+		t.Fatalf("missing ledger: repaired=%v err=%v", repaired, err)
+	}
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	repaired, err = RepairTruncatedTail()
+	if err != nil || repaired {
+		t.Fatalf("clean ledger: repaired=%v err=%v", repaired, err)
+	}
+}
+
+func TestRepairTruncatedTail_RefusesCompleteInvalidFinalLine(t *testing.T) {
+	ledgerPath := setupTestLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	// Invalid JSON, but written out in full with its newline: not a torn append
+	appendRaw(t, ledgerPath, "this is not valid json\n")
+	before, _ := os.ReadFile(ledgerPath)
+
+	repaired, err := RepairTruncatedTail()
+	if !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("expected ErrLedgerCorrupt, got %v", err)
+	}
+	if repaired {
+		t.Errorf("expected repaired=false")
+	}
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) || corrupt.LineNum != 2 {
+		t.Errorf("expected CorruptError for line 2, got %#v", err)
+	}
+
+	after, _ := os.ReadFile(ledgerPath)
+	if string(after) != string(before) {
+		t.Errorf("ledger modified despite refusal")
+	}
+}
@@ -0,0 +1,66 @@
+package ledger
+
+import (
+	"encoding/base64"
+)
+
+// ReplayMismatch describes a register whose stored canonical JSON does not hash to its ObjectHashHex
+type ReplayMismatch struct {
+	LineNum       int    `json:"line_num"`
+	ObjectHashHex string `json:"object_hash_hex"`
+	RecomputedHex string `json:"recomputed_hex,omitempty"` // Empty when the payload could not be decoded
+	Reason        string `json:"reason"`
+}
+
+// ReplayAudit re-hashes the stored canonical JSON of every register and reports
+// entries whose recomputed hash differs from ObjectHashHex.
+//
+// Registers without CanonicalJSONB64 are skipped: there is nothing to replay.
+// An undecodable payload is reported as a mismatch rather than aborting the audit.
+//
+// Returns:
+//   - Mismatches in ledger order (empty when every payload matches its hash)
+//   - ErrLedgerCorrupt if the ledger cannot be parsed
+func ReplayAudit() ([]ReplayMismatch, error) {
+	var mismatches []ReplayMismatch
+
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		if entryType != "register" {
+			return nil
+		}
+
+		reg, _, err := parseRegister(lineNum, line)
+		if err != nil {
+			return err
+		}
+		if reg.CanonicalJSONB64 == "" {
+			return nil
+		}
+
+		payload, err := base64.StdEncoding.DecodeString(reg.CanonicalJSONB64)
+		if err != nil {
+			mismatches = append(mismatches, ReplayMismatch{
+				LineNum:       lineNum,
+				ObjectHashHex: reg.ObjectHashHex,
+				Reason:        "canonical_json_b64 is not valid base64",
+			})
+			return nil
+		}
+
+		recomputed := ComputeObjectHash(payload)
+		if recomputed != reg.ObjectHashHex {
+			mismatches = append(mismatches, ReplayMismatch{
+				LineNum:       lineNum,
+				ObjectHashHex: reg.ObjectHashHex,
+				RecomputedHex: recomputed,
+				Reason:        "stored hash does not match canonical JSON",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mismatches, nil
+}
@@ -0,0 +1,58 @@
+package ledger
+
+import (
+	"testing"
+)
+
+func TestReplayAudit_MatchingPair(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, err := RegisterCanonical(map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("RegisterCanonical failed: %v", err)
+	}
+	// Registers without a payload have nothing to replay
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	mismatches, err := ReplayAudit()
+	if err != nil {
+		t.Fatalf("ReplayAudit failed: %v", err)
+	}
+	if len(mismatches) != 0 {
+		t.Errorf("expected no mismatches, got %+v", mismatches)
+	}
+}
+
+func TestReplayAudit_MismatchedPair(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, err := RegisterCanonical(map[string]string{"k": "v"}); err != nil {
+		t.Fatalf("RegisterCanonical failed: %v", err)
+	}
+
+	payload := []byte(`{"k":"injected"}`)
+	forged := validObjectHash()
+	if err := AppendRegister(forged, payload); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	mismatches, err := ReplayAudit()
+	if err != nil {
+		t.Fatalf("ReplayAudit failed: %v", err)
+	}
+	if len(mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %+v", mismatches)
+	}
+
+	m := mismatches[0]
+	if m.LineNum != 2 {
+		t.Errorf("LineNum = %d, want 2", m.LineNum)
+	}
+	if m.ObjectHashHex != forged {
+		t.Errorf("ObjectHashHex = %s, want %s", m.ObjectHashHex, forged)
+	}
+	if m.RecomputedHex != ComputeObjectHash(payload) {
+		t.Errorf("RecomputedHex = %s, want %s", m.RecomputedHex, ComputeObjectHash(payload))
+	}
+}
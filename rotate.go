@@ -0,0 +1,136 @@
+package ledger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+// ErrUnsealedRegisters is returned by Rotate when registers are pending after the last seal
+var ErrUnsealedRegisters = errors.New("ledger has unsealed registers")
+
+// AnchorEntry is the first entry of a rotated ledger file. It carries the final
+// seal of the previous file, so the seal chain (PrevSealRoot, EpochID) and the
+// "after the last seal" rules continue across files.
+type AnchorEntry struct {
+	Type        string `json:"type"`          // Always "anchor"
+	Canon       string `json:"canon"`         // Canon version
+	Timestamp   string `json:"timestamp"`     // Timestamp of the previous file's final seal
+	PrevHash    string `json:"prev_hash"`     // Merkle root of the previous file's final seal
+	PrevEpochID int    `json:"prev_epoch_id"` // EpochID of the previous file's final seal
+	PrevLedger  string `json:"prev_ledger"`   // Path of the previous ledger file
+}
+
+// Rotate starts a new ledger file at newPath, anchored to the current ledger.
+//
+// The current ledger must end with a seal: Rotate refuses with
+// ErrUnsealedRegisters if registers are pending, and with ErrNoRegistrations if
+// nothing was ever sealed. The new file's first entry is an AnchorEntry whose
+// prev_hash is the final seal's root; the ledger then switches to the file
+// backend at newPath. newPath must not already contain entries.
+func Rotate(newPath string) error {
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+
+	st := currentStore()
+	oldPath := ledgerPath
+	if fs, ok := st.(*FileStore); ok {
+		oldPath = fs.Path
+	}
+	if newPath == oldPath {
+		return fmt.Errorf("%w: rotation target is the current ledger %s", ErrLedgerIO, newPath)
+	}
+
+	lastSeal, err := lastSealIn(st)
+	if err != nil {
+		return err
+	}
+	if lastSeal == nil {
+		return fmt.Errorf("%w: nothing sealed yet, refusing to rotate", ErrNoRegistrations)
+	}
+
+	lastSealTS, err := ParseCanonTimestamp(lastSeal.Manifest.Timestamp)
+	if err != nil {
+		return corruptLine(0, nil, "invalid seal timestamp: %v", err)
+	}
+	pending, err := listRegistersSinceIn(context.Background(), st, lastSealTS)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d registers after the last seal, seal before rotating", ErrUnsealedRegisters, len(pending))
+	}
+
+	// Never clobber an existing ledger
+	if info, err := os.Stat(newPath); err == nil && info.Size() > 0 {
+		return fmt.Errorf("%w: rotation target %s already has entries", ErrLedgerIO, newPath)
+	}
+
+	anchor := AnchorEntry{
+		Type:        "anchor",
+		Canon:       config.CanonVersion,
+		Timestamp:   lastSeal.Manifest.Timestamp,
+		PrevHash:    lastSeal.Manifest.MerkleRoot,
+		PrevEpochID: lastSeal.Manifest.EpochID,
+		PrevLedger:  oldPath,
+	}
+	if err := appendEntryTo(&FileStore{Path: newPath}, anchor); err != nil {
+		return err
+	}
+
+	ledgerPath = newPath
+	ledgerStore = nil
+	bumpLedgerVersion()
+	return nil
+}
+
+// isEpochBoundary reports whether entries of this type close an epoch
+func isEpochBoundary(entryType string) bool {
+	return entryType == "seal" || entryType == "anchor"
+}
+
+// parseEpochBoundary decodes a seal, or an anchor as the seal it carries over.
+func parseEpochBoundary(lineNum int, entryType string, line []byte) (SealEntry, time.Time, error) {
+	if entryType == "seal" {
+		return parseSeal(lineNum, line)
+	}
+
+	anchor, ts, err := parseAnchor(lineNum, line)
+	if err != nil {
+		return SealEntry{}, time.Time{}, err
+	}
+	return anchor.asSeal(), ts, nil
+}
+
+// parseAnchor decodes an anchor line and its timestamp.
+func parseAnchor(lineNum int, line []byte) (AnchorEntry, time.Time, error) {
+	var anchor AnchorEntry
+	if err := json.Unmarshal(line, &anchor); err != nil {
+		return AnchorEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid anchor entry: %v", err)
+	}
+
+	ts, err := ParseCanonTimestamp(anchor.Timestamp)
+	if err != nil {
+		return AnchorEntry{}, time.Time{}, corruptLine(lineNum, line, "invalid anchor timestamp: %v", err)
+	}
+
+	return anchor, ts, nil
+}
+
+// asSeal is the previous file's final seal as far as chaining is concerned
+func (a AnchorEntry) asSeal() SealEntry {
+	return SealEntry{
+		Type: "seal",
+		Manifest: Manifest{
+			MerkleRoot: a.PrevHash,
+			Timestamp:  a.Timestamp,
+			Canon:      a.Canon,
+			EpochID:    a.PrevEpochID,
+		},
+	}
+}
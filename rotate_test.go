@@ -0,0 +1,109 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotate_AfterSeal(t *testing.T) {
+	oldPath := setupTestLedger(t)
+	buildSealedLedger(t, 2, 1)
+	seals := readSeals(t)
+	finalRoot := seals[len(seals)-1].Manifest.MerkleRoot
+
+	newPath := filepath.Join(t.TempDir(), "ledger-2024-02.jsonl")
+	if err := Rotate(newPath); err != nil {
+		t.Fatalf("Rotate failed: %v", err)
+	}
+	if GetLedgerPath() != newPath {
+		t.Fatalf("ledger path = %s, want %s", GetLedgerPath(), newPath)
+	}
+
+	// The new file opens with an anchor to the old file's final seal
+	data, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("failed to read rotated ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line in rotated ledger, got %d", len(lines))
+	}
+	var anchor AnchorEntry
+	if err := json.Unmarshal([]byte(lines[0]), &anchor); err != nil {
+		t.Fatalf("failed to unmarshal anchor: %v", err)
+	}
+	if anchor.Type != "anchor" || anchor.PrevHash != finalRoot || anchor.PrevEpochID != 1 || anchor.PrevLedger != oldPath {
+		t.Fatalf("unexpected anchor: %+v", anchor)
+	}
+
+	// The seal chain continues in the new file
+	if err := AppendRegister(testHash(40), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	manifest, _, err := PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+	if manifest.EpochID != 2 || manifest.PrevSealRoot != finalRoot {
+		t.Fatalf("manifest after rotation: epoch %d prev %s, want 2 and %s", manifest.EpochID, manifest.PrevSealRoot, finalRoot)
+	}
+	if err := AppendSeal(manifest); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil || !report.Valid {
+		t.Fatalf("rotated ledger should be valid, got %+v err=%v", report.Violations, err)
+	}
+
+	// The old file is untouched
+	SetLedgerPath(oldPath)
+	if report, err := CheckIntegrity(); err != nil || !report.Valid || report.Seals != 2 {
+		t.Fatalf("old ledger changed: %+v err=%v", report, err)
+	}
+}
+
+func TestRotate_RefusesPendingRegisters(t *testing.T) {
+	oldPath := setupTestLedger(t)
+	buildSealedLedger(t, 1)
+	if err := AppendRegister(testHash(9), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	newPath := filepath.Join(t.TempDir(), "next.jsonl")
+	if err := Rotate(newPath); !errors.Is(err, ErrUnsealedRegisters) {
+		t.Fatalf("expected ErrUnsealedRegisters, got %v", err)
+	}
+	if GetLedgerPath() != oldPath {
+		t.Errorf("ledger path switched despite refusal")
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		t.Errorf("rotation target should not be created, stat err = %v", err)
+	}
+}
+
+func TestRotate_RefusesUnsealedLedger(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := Rotate(filepath.Join(t.TempDir(), "next.jsonl")); !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+}
+
+func TestCheckIntegrity_AnchorNotFirst(t *testing.T) {
+	path := setupTestLedger(t)
+	buildSealedLedger(t, 1)
+	appendRaw(t, path, `{"type":"anchor","canon":"v1.0","timestamp":"2099-01-01T00:00:00Z","prev_hash":"`+testHash(1)+`","prev_epoch_id":0}`+"\n")
+
+	report, err := CheckIntegrity()
+	if err != nil {
+		t.Fatalf("CheckIntegrity failed: %v", err)
+	}
+	if !hasCategory(report, IntegrityBrokenAnchor) {
+		t.Fatalf("expected broken_anchor for misplaced anchor, got %+v", report.Violations)
+	}
+}
@@ -0,0 +1,133 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/config"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// ErrEpochNotFull is returned by AutoSealIfReady when fewer than config.EpochSize registers are pending
+var ErrEpochNotFull = errors.New("epoch not full")
+
+// ShouldSeal reports whether the pending registers since the last seal have
+// reached config.EpochSize, along with the current pending count, so a
+// scheduler can close epochs by size.
+func ShouldSeal() (bool, int, error) {
+	pending, err := ListPendingRegisters()
+	if err != nil {
+		return false, 0, err
+	}
+	return len(pending) >= config.EpochSize, len(pending), nil
+}
+
+// AutoSealIfReady seals the pending registers with seedHex only when ShouldSeal
+// reports the epoch is full. It returns ErrEpochNotFull (without writing) otherwise.
+func AutoSealIfReady(seedHex string) (Manifest, error) {
+	ready, count, err := ShouldSeal()
+	if err != nil {
+		return Manifest{}, err
+	}
+	if !ready {
+		return Manifest{}, fmt.Errorf("%w: %d of %d registers pending", ErrEpochNotFull, count, config.EpochSize)
+	}
+
+	return SealPending(seedHex)
+}
+
+// PrepareSeal is a dry run of the next seal: it builds the Merkle root over the
+// registers pending now, fills in the same manifest fields SealPending would
+// (timestamp, epoch ID, previous seal root, canon, leaf count, seal policy
+// hash), signs the manifest's SigningDigest with seedHex and returns it together
+// with the registers it covers. Nothing is written; pass the manifest to
+// AppendSeal to commit it.
+//
+// The ledger is only read-locked while preparing, so the result is a snapshot.
+// AppendSeal accepts the manifest as long as nothing is appended in between:
+// another seal fails it with ErrEpochOutOfSequence or ErrBrokenAnchor, and a
+// new register with ErrLeafCountMismatch. Use SealPending to prepare and
+// append in one step.
+//
+// Returns ErrNoRegistrations if nothing is pending, ErrPolicyViolation if the
+// epoch tree breaks the seal policy's depth bounds (SetSealPolicy), sign
+// errors for a malformed seed, or the error of reading a corrupt ledger.
+func PrepareSeal(seedHex string) (Manifest, []RegisterEntry, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	return prepareSealIn(currentStore(), seedHex)
+}
+
+// SealPending seals every pending register in one step: it builds the Merkle
+// root, fills in the timestamp, epoch ID, previous seal root, canon version,
+// leaf count and seal policy hash (SetSealPolicy), signs the manifest with seedHex and
+// appends the seal. All of it happens under the
+// ledger write lock, so no register or seal can land between computing the
+// root and writing it.
+//
+// It returns the manifest as stored, or any error PrepareSeal or AppendSeal
+// would return.
+func SealPending(seedHex string) (Manifest, error) {
+	ledgerMutex.Lock()
+	defer unlockAndNotify()
+	st := currentStore()
+
+	manifest, _, err := prepareSealIn(st, seedHex)
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := appendSealIn(st, manifest); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+// prepareSealIn is PrepareSeal against st. The caller must hold ledgerMutex.
+func prepareSealIn(st Store, seedHex string) (Manifest, []RegisterEntry, error) {
+	lastSeal, err := lastSealIn(st)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	var lastSealTS time.Time
+	if lastSeal != nil {
+		lastSealTS, _ = ParseCanonTimestamp(lastSeal.Manifest.Timestamp)
+	}
+	pending, err := listRegistersSinceIn(context.Background(), st, lastSealTS)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	if len(pending) == 0 {
+		return Manifest{}, nil, ErrNoRegistrations
+	}
+
+	root, depth, _, err := merkle.BuildRootWithStats(registerLeaves(pending))
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	if bp := sealPolicy.Load(); bp != nil {
+		if err := policy.CheckTreeDepth(bp.policy, depth); err != nil {
+			return Manifest{}, nil, fmt.Errorf("%w: %d pending registers: %v", ErrPolicyViolation, len(pending), err)
+		}
+	}
+
+	manifest, err := Manifest{
+		MerkleRoot:   root,
+		Timestamp:    NormalizeTimestamp(now()),
+		Canon:        config.CanonVersion,
+		EpochID:      nextEpochID(lastSeal),
+		PrevSealRoot: prevSealRoot(lastSeal),
+		LeafCount:    len(pending),
+		PolicyHash:   SealPolicyHash(),
+	}.Sign(seedHex)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+
+	return manifest, pending, nil
+}
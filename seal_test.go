@@ -0,0 +1,181 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+func TestPrepareSeal_DoesNotWrite(t *testing.T) {
+	path := setupTestLedger(t)
+	buildSealedLedger(t, 2)
+	for i := 10; i < 13; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+
+	manifest, covered, err := PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Fatalf("PrepareSeal modified the ledger")
+	}
+
+	if len(covered) != 3 || covered[0].ObjectHashHex != testHash(10) || covered[2].ObjectHashHex != testHash(12) {
+		t.Fatalf("unexpected covered registers: %+v", covered)
+	}
+	if manifest.EpochID != 1 {
+		t.Errorf("EpochID = %d, want 1", manifest.EpochID)
+	}
+
+	root, _, err := ComputeEpochRoot(mustLastSealTimestamp(t))
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if manifest.MerkleRoot != root {
+		t.Errorf("MerkleRoot = %s, want %s", manifest.MerkleRoot, root)
+	}
+}
+
+func TestPrepareSeal_AcceptedByAppendSeal(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1)
+	if err := AppendRegister(testHash(5), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	manifest, _, err := PrepareSeal(testSeedHex)
+	if err != nil {
+		t.Fatalf("PrepareSeal failed: %v", err)
+	}
+	if err := AppendSeal(manifest); err != nil {
+		t.Fatalf("AppendSeal rejected prepared manifest: %v", err)
+	}
+
+	report, err := CheckIntegrity()
+	if err != nil || !report.Valid || report.Seals != 2 {
+		t.Fatalf("CheckIntegrity = %+v, %v", report, err)
+	}
+}
+
+func TestPrepareSeal_NoRegistrations(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, _, err := PrepareSeal(testSeedHex); !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+}
+
+func TestSealPending(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		buildSealedLedger(t, 2)
+		appendN(t, 10, 3)
+
+		manifest, err := SealPending(testSeedHex)
+		if err != nil {
+			t.Fatalf("SealPending failed: %v", err)
+		}
+
+		seals := readSeals(t)
+		if len(seals) != 2 || seals[1].Manifest != manifest {
+			t.Fatalf("stored seals = %+v, want the second to be %+v", seals, manifest)
+		}
+		if manifest.EpochID != 1 || manifest.PrevSealRoot != seals[0].Manifest.MerkleRoot || manifest.Canon != config.CanonVersion {
+			t.Errorf("manifest chain fields = epoch %d, prev %q, canon %q", manifest.EpochID, manifest.PrevSealRoot, manifest.Canon)
+		}
+		if ok, err := manifest.VerifySelfSignature(); !ok {
+			t.Errorf("manifest signature does not verify: %v", err)
+		}
+
+		report, err := CheckIntegrity()
+		if err != nil || !report.Valid || report.Seals != 2 {
+			t.Fatalf("CheckIntegrity = %+v, %v", report, err)
+		}
+
+		// Everything is sealed now
+		if _, err := SealPending(testSeedHex); !errors.Is(err, ErrNoRegistrations) {
+			t.Fatalf("second SealPending: expected ErrNoRegistrations, got %v", err)
+		}
+	})
+}
+
+func TestSealPending_NoRegistrations(t *testing.T) {
+	path := setupTestLedger(t)
+
+	if _, err := SealPending(testSeedHex); !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("SealPending wrote to an empty ledger: %v", err)
+	}
+}
+
+// mustLastSealTimestamp returns the last seal timestamp or fails the test
+func mustLastSealTimestamp(t *testing.T) time.Time {
+	t.Helper()
+	ts, err := getLastSealTimestamp()
+	if err != nil {
+		t.Fatalf("getLastSealTimestamp failed: %v", err)
+	}
+	return ts
+}
+
+// appendN appends n distinct registers starting at testHash(start)
+func appendN(t *testing.T, start, n int) {
+	t.Helper()
+	for i := start; i < start+n; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+}
+
+func TestShouldSeal_Threshold(t *testing.T) {
+	setupMemLedger(t)
+
+	appendN(t, 0, config.EpochSize-1)
+	ready, count, err := ShouldSeal()
+	if err != nil || ready || count != config.EpochSize-1 {
+		t.Fatalf("at %d: ShouldSeal = %v, %d, %v; want false", config.EpochSize-1, ready, count, err)
+	}
+	if _, err := AutoSealIfReady(testSeedHex); !errors.Is(err, ErrEpochNotFull) {
+		t.Fatalf("expected ErrEpochNotFull, got %v", err)
+	}
+
+	appendN(t, config.EpochSize-1, 1)
+	ready, count, err = ShouldSeal()
+	if err != nil || !ready || count != config.EpochSize {
+		t.Fatalf("at %d: ShouldSeal = %v, %d, %v; want true", config.EpochSize, ready, count, err)
+	}
+
+	manifest, err := AutoSealIfReady(testSeedHex)
+	if err != nil {
+		t.Fatalf("AutoSealIfReady failed: %v", err)
+	}
+	if manifest.EpochID != 0 {
+		t.Errorf("EpochID = %d, want 0", manifest.EpochID)
+	}
+
+	// Just after the seal the counter starts over
+	appendN(t, config.EpochSize, 1)
+	ready, count, err = ShouldSeal()
+	if err != nil || ready || count != 1 {
+		t.Fatalf("after seal: ShouldSeal = %v, %d, %v; want false, 1", ready, count, err)
+	}
+}
@@ -0,0 +1,56 @@
+package ledger
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// ErrPolicyViolation is returned when sealing would break the seal policy
+var ErrPolicyViolation = errors.New("seal policy violation")
+
+// boundPolicy is a rotation policy together with its hash
+type boundPolicy struct {
+	policy *policy.RotationPolicy
+	hash   string // SHA-256 of policy.CanonicalizePolicy, 64 hex
+}
+
+// sealPolicy is the policy seals are made under (nil: none, the default)
+var sealPolicy atomic.Pointer[boundPolicy]
+
+// SetSealPolicy makes PrepareSeal and SealPending seal under p: every manifest
+// they produce carries p's hash in PolicyHash, which the seal signature covers,
+// and an epoch whose Merkle tree depth falls outside p's MinDepth/MaxDepth is
+// refused with ErrPolicyViolation.
+// The hash is that of the canonical policy (as served by GET /policy), so an
+// auditor can tell which constitution was in force for each epoch. A nil p
+// clears the policy; manifests then carry no policy_hash.
+//
+// Returns the policy.ValidateInvariants error if p breaks an invariant; the
+// previous seal policy then stays in force.
+func SetSealPolicy(p *policy.RotationPolicy) error {
+	if p == nil {
+		sealPolicy.Store(nil)
+		return nil
+	}
+	if err := policy.ValidateInvariants(p); err != nil {
+		return err
+	}
+	canonical, err := policy.CanonicalizePolicy(p)
+	if err != nil {
+		return err
+	}
+	sealPolicy.Store(&boundPolicy{policy: p, hash: hash.Sha256Hex(canonical)})
+	return nil
+}
+
+// SealPolicyHash returns the hash PrepareSeal and SealPending stamp into
+// manifests, or "" when no seal policy is set.
+func SealPolicyHash() string {
+	if bp := sealPolicy.Load(); bp != nil {
+		return bp.hash
+	}
+	return ""
+}
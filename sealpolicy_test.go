@@ -0,0 +1,128 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/internal/policy"
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// testPolicy returns a rotation policy that satisfies every invariant
+func testPolicy() *policy.RotationPolicy {
+	return &policy.RotationPolicy{
+		PolicyVersion: "1.0",
+		Issuer:        policy.IssuerInfo{Name: "Alpha", ID: "rva://1"},
+		Epochs:        policy.EpochConfig{IntervalSeconds: 86400, IDFormat: "numeric_ascending"},
+		Constraints: policy.CryptoConstraints{
+			HashAlg:         "sha256",
+			AllowedHashAlgs: []string{"sha256"},
+			DomainSeparator: "RVA_NODE:v1",
+			MinDepth:        1,
+			MaxDepth:        64,
+		},
+		Cutover: policy.CutoverRules{RequirePrevAnchor: true, StrictMonotonicEpoch: true},
+	}
+}
+
+// useSealPolicy seals under p for the rest of the test
+func useSealPolicy(t *testing.T, p *policy.RotationPolicy) {
+	t.Helper()
+	if err := SetSealPolicy(p); err != nil {
+		t.Fatalf("SetSealPolicy failed: %v", err)
+	}
+	t.Cleanup(func() { SetSealPolicy(nil) })
+}
+
+func TestSealPending_PolicyHash(t *testing.T) {
+	setupTestLedger(t)
+	p := testPolicy()
+	useSealPolicy(t, p)
+	appendN(t, 0, 2)
+
+	manifest, err := SealPending(testSeedHex)
+	if err != nil {
+		t.Fatalf("SealPending failed: %v", err)
+	}
+	canonical, err := policy.CanonicalizePolicy(p)
+	if err != nil {
+		t.Fatalf("CanonicalizePolicy failed: %v", err)
+	}
+	if want := hash.Sha256Hex(canonical); manifest.PolicyHash != want || readSeals(t)[0].Manifest.PolicyHash != want {
+		t.Fatalf("policy_hash = %q, want %q", manifest.PolicyHash, want)
+	}
+	if ok, err := manifest.VerifySelfSignature(); !ok {
+		t.Fatalf("manifest signature does not verify: %v", err)
+	}
+
+	// Claiming another policy was in force breaks the signature
+	forged := manifest
+	forged.PolicyHash = testHash(99)
+	if ok, err := forged.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a rewritten policy_hash, ok=%v err=%v", ok, err)
+	}
+	forged.PolicyHash = ""
+	if ok, err := forged.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a stripped policy_hash, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestSealPending_NoPolicy(t *testing.T) {
+	setupTestLedger(t)
+	appendN(t, 0, 1)
+
+	manifest, err := SealPending(testSeedHex)
+	if err != nil {
+		t.Fatalf("SealPending failed: %v", err)
+	}
+	if manifest.PolicyHash != "" || SealPolicyHash() != "" {
+		t.Fatalf("expected no policy_hash without a seal policy, got %q", manifest.PolicyHash)
+	}
+}
+
+func TestSetSealPolicy_RejectsInvalid(t *testing.T) {
+	valid := testPolicy()
+	useSealPolicy(t, valid)
+	before := SealPolicyHash()
+
+	invalid := testPolicy()
+	invalid.Constraints.HashAlg = "md5"
+	if err := SetSealPolicy(invalid); err == nil {
+		t.Fatalf("expected an invalid policy to be rejected")
+	}
+	if SealPolicyHash() != before {
+		t.Fatalf("a rejected policy replaced the seal policy")
+	}
+}
+
+func TestSealPending_PolicyDepthBounds(t *testing.T) {
+	setupTestLedger(t)
+	p := testPolicy()
+	p.Constraints.MinDepth, p.Constraints.MaxDepth = 2, 2
+	useSealPolicy(t, p)
+
+	// Two registers hash in one level: too shallow
+	appendN(t, 0, 2)
+	if _, _, err := PrepareSeal(testSeedHex); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("PrepareSeal: expected ErrPolicyViolation, got %v", err)
+	}
+	if _, err := SealPending(testSeedHex); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("SealPending: expected ErrPolicyViolation, got %v", err)
+	}
+	if seals := readSeals(t); len(seals) != 0 {
+		t.Fatalf("a seal breaking the policy was written: %+v", seals)
+	}
+
+	// Three registers need two levels
+	appendN(t, 2, 1)
+	if _, err := SealPending(testSeedHex); err != nil {
+		t.Fatalf("SealPending within bounds failed: %v", err)
+	}
+
+	// Five registers need three levels: too deep
+	appendN(t, 3, 5)
+	if _, err := SealPending(testSeedHex); !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("SealPending: expected ErrPolicyViolation, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+// ─────────────────────────────────────────────
+// Runtime Configuration (deployment, NOT canon)
+// ─────────────────────────────────────────────
+
+// RuntimeConfig holds per-deployment settings loaded from a JSON file.
+// Unlike the canon constants above, these values may differ between nodes.
+type RuntimeConfig struct {
+	LedgerPath  string `json:"ledger_path"`
+	SealSeedHex string `json:"seal_seed_hex"`
+	ListenAddr  string `json:"listen_addr"`
+	WriteToken  string `json:"write_token"` // Bearer token for the server's write endpoints; empty disables them
+}
+
+// String renders the config with the seal seed and write token redacted so it is safe to log.
+func (c RuntimeConfig) String() string {
+	return fmt.Sprintf("{ledger_path:%s seal_seed_hex:%s listen_addr:%s write_token:%s}", c.LedgerPath, redact(c.SealSeedHex), c.ListenAddr, redact(c.WriteToken))
+}
+
+// redact hides a secret, keeping only whether it is set
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+// LoadRuntimeConfig reads and validates a runtime config file.
+// The seal seed, when present, must be 64 lowercase hex chars (see sign.ValidateSeedHex).
+// A missing file yields an error wrapping os.ErrNotExist so callers can fall back to defaults.
+func LoadRuntimeConfig(path string) (*RuntimeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: could not read %s: %w", path, err)
+	}
+
+	var cfg RuntimeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("config: malformed JSON in %s: %w", path, err)
+	}
+
+	if cfg.SealSeedHex != "" {
+		// Never echo the seed itself: the sign error message would include it
+		if err := sign.ValidateSeedHex(cfg.SealSeedHex); err != nil {
+			return nil, fmt.Errorf("config: seal_seed_hex: %w", sign.ErrInvalidHex)
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+const testSeedHex = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "runtime.json")
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadRuntimeConfig_Valid(t *testing.T) {
+	path := writeConfig(t, `{"ledger_path":"/var/lib/rva/ledger.jsonl","seal_seed_hex":"`+testSeedHex+`","listen_addr":"127.0.0.1:9090"}`)
+
+	cfg, err := LoadRuntimeConfig(path)
+	if err != nil {
+		t.Fatalf("LoadRuntimeConfig failed: %v", err)
+	}
+	if cfg.LedgerPath != "/var/lib/rva/ledger.jsonl" {
+		t.Errorf("LedgerPath = %s", cfg.LedgerPath)
+	}
+	if cfg.SealSeedHex != testSeedHex {
+		t.Errorf("SealSeedHex not loaded")
+	}
+	if cfg.ListenAddr != "127.0.0.1:9090" {
+		t.Errorf("ListenAddr = %s", cfg.ListenAddr)
+	}
+
+	// The seed must never be rendered when the config is logged
+	for _, rendered := range []string{cfg.String(), fmt.Sprintf("%v", *cfg), fmt.Sprintf("%+v", *cfg)} {
+		if strings.Contains(rendered, testSeedHex) {
+			t.Errorf("seed leaked in %q", rendered)
+		}
+	}
+}
+
+func TestLoadRuntimeConfig_BadSeed(t *testing.T) {
+	path := writeConfig(t, `{"seal_seed_hex":"NOT-A-SEED"}`)
+
+	_, err := LoadRuntimeConfig(path)
+	if !errors.Is(err, sign.ErrInvalidHex) {
+		t.Fatalf("expected sign.ErrInvalidHex, got %v", err)
+	}
+	if strings.Contains(err.Error(), "NOT-A-SEED") {
+		t.Errorf("error leaks seed value: %v", err)
+	}
+}
+
+func TestLoadRuntimeConfig_MissingFile(t *testing.T) {
+	_, err := LoadRuntimeConfig(filepath.Join(t.TempDir(), "absent.json"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestRuntimeConfig_StringRedactsWriteToken(t *testing.T) {
+	path := writeConfig(t, `{"write_token":"s3cret-token"}`)
+
+	cfg, err := LoadRuntimeConfig(path)
+	if err != nil {
+		t.Fatalf("LoadRuntimeConfig failed: %v", err)
+	}
+	if cfg.WriteToken != "s3cret-token" {
+		t.Fatalf("WriteToken not loaded")
+	}
+	for _, rendered := range []string{cfg.String(), fmt.Sprintf("%v", *cfg), fmt.Sprintf("%+v", *cfg)} {
+		if strings.Contains(rendered, "s3cret-token") {
+			t.Errorf("write token leaked in %q", rendered)
+		}
+	}
+}
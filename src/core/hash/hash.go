@@ -0,0 +1,57 @@
+// Package hash provides canonical JSON encoding and SHA-256 hashing for FORGED-LRO Canon v1.0.
+//
+// Canon rules (Hash v1.0):
+// - Object keys are sorted lexicographically (byte order).
+// - No insignificant whitespace; no trailing newline.
+// - HTML characters (<, >, &) are NOT escaped.
+// - Numbers are emitted verbatim as they appear in the marshalled input.
+// - Digests are SHA-256 encoded as 64-char lowercase hex.
+// - stdlib-only.
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrCanonicalize is returned when a value cannot be represented as canonical JSON.
+var ErrCanonicalize = errors.New("cannot canonicalize value")
+
+// Canonicalize returns the canonical JSON encoding of v.
+// Two values that marshal to semantically identical JSON produce identical bytes.
+func Canonicalize(v interface{}) ([]byte, error) {
+	// 1. Value -> JSON: struct field order follows the type definition
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: marshal: %v", ErrCanonicalize, err)
+	}
+
+	// 2. JSON -> generic tree: maps re-encode with sorted keys, numbers stay verbatim
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("%w: re-parse: %v", ErrCanonicalize, err)
+	}
+
+	// 3. Generic tree -> canonical bytes
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(tree); err != nil {
+		return nil, fmt.Errorf("%w: encode: %v", ErrCanonicalize, err)
+	}
+
+	// Encoder always terminates with '\n'; the canon forbids it
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// Sha256Hex returns the SHA-256 digest of data as 64 lowercase hex chars.
+func Sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,63 @@
+package hash
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCanonicalize_SortedKeysNoWhitespace(t *testing.T) {
+	in := map[string]interface{}{
+		"zeta":  1,
+		"alpha": map[string]interface{}{"y": true, "b": nil},
+		"html":  "<a&b>",
+	}
+
+	got, err := Canonicalize(in)
+	if err != nil {
+		t.Fatalf("Canonicalize error: %v", err)
+	}
+
+	want := `{"alpha":{"b":null,"y":true},"html":"<a&b>","zeta":1}`
+	if string(got) != want {
+		t.Fatalf("got %s want %s", got, want)
+	}
+	if bytes.HasSuffix(got, []byte("\n")) {
+		t.Fatalf("canonical bytes must not have a trailing newline")
+	}
+}
+
+func TestCanonicalize_StructMatchesMap(t *testing.T) {
+	type obj struct {
+		B string `json:"b"`
+		A int    `json:"a"`
+	}
+
+	fromStruct, err := Canonicalize(obj{B: "x", A: 7})
+	if err != nil {
+		t.Fatalf("Canonicalize(struct) error: %v", err)
+	}
+	fromMap, err := Canonicalize(map[string]interface{}{"a": 7, "b": "x"})
+	if err != nil {
+		t.Fatalf("Canonicalize(map) error: %v", err)
+	}
+
+	if !bytes.Equal(fromStruct, fromMap) {
+		t.Fatalf("struct and map differ: %s vs %s", fromStruct, fromMap)
+	}
+}
+
+func TestCanonicalize_Unmarshalable(t *testing.T) {
+	_, err := Canonicalize(func() {})
+	if !errors.Is(err, ErrCanonicalize) {
+		t.Fatalf("expected ErrCanonicalize, got %v", err)
+	}
+}
+
+func TestSha256Hex_KnownVector(t *testing.T) {
+	// SHA-256("abc")
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got := Sha256Hex([]byte("abc")); got != want {
+		t.Fatalf("got %s want %s", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package merkle
+
+import (
+	"testing"
+)
+
+// Benchmark construcción de árbol con 1,000 hojas
+func BenchmarkBuildTree1000(b *testing.B) {
+	vals := make([]string, 1000)
+	for i := range vals {
+		vals[i] = string(rune(i))
+	}
+	leaves := makeLeaves(vals)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := BuildRoot(leaves); err != nil {
+			b.Fatalf("BuildRoot failed: %v", err)
+		}
+	}
+}
+
+// Benchmark verificación de prueba con profundidad ~20 (árbol ~1 millón de nodos)
+func BenchmarkVerifyDepth20(b *testing.B) {
+	// Generamos ~1 millón de hojas
+	vals := make([]string, 1<<20) // 2^20 ≈ 1,048,576
+	for i := range vals {
+		vals[i] = string(rune(i))
+	}
+	leaves := makeLeaves(vals)
+
+	// Seleccionamos una hoja en el medio
+	idx := len(leaves) / 2
+	proof, root, err := BuildProof(leaves, idx)
+	if err != nil {
+		b.Fatalf("Proof generation failed: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ok, err := VerifyProof(leaves[idx], idx, len(leaves), proof, root)
+		if err != nil {
+			b.Fatalf("Verify failed: %v", err)
+		}
+		if !ok {
+			b.Fatalf("Proof invalid")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrInternalNodeCollision is returned by VerifyProof, when collision checks are
+// on, for a leaf equal to an internal node of the tree the proof describes.
+var ErrInternalNodeCollision = errors.New("leaf equals an internal node")
+
+// rejectInternalNodeCollisions enables the collision checks (off by default)
+var rejectInternalNodeCollisions atomic.Bool
+
+// SetRejectInternalNodeCollisions turns the internal-node collision detector on
+// or off for every proof verification in the process.
+//
+// Leaves and parents are both bare hashes with no domain separation, so a
+// "leaf" that is really an internal node's hash can stand in for the subtree
+// below it (a second-preimage forgery). When on, VerifyProof rejects a leaf
+// equal to any internal node the proof reveals, i.e. a sibling at level 1 or
+// above. Trees built from genuine object hashes never trip it.
+//
+// The detector does not stop the forgery itself. An internal node presented
+// as a leaf of a smaller tree (lower totalLeaves, proof truncated to the
+// levels above the node) reveals nothing the verifier can compare against:
+// it is a genuine proof for that smaller tree, which has the same root. Only
+// a totalLeaves the verifier trusts defeats it, because the proof length is
+// then fixed by the real tree; the ledger signs it into each seal
+// (Manifest.LeafCount, Manifest.CheckTreeSize).
+func SetRejectInternalNodeCollisions(enabled bool) {
+	rejectInternalNodeCollisions.Store(enabled)
+}
+
+// checkLeafCollision reports ErrInternalNodeCollision if leaf equals a proof
+// sibling above level 0; every such sibling is an internal node.
+func checkLeafCollision(leaf string, proof []ProofNode) error {
+	for level := 1; level < len(proof); level++ {
+		if proof[level].Hash == leaf {
+			return fmt.Errorf("%w: proof[%d]", ErrInternalNodeCollision, level)
+		}
+	}
+	return nil
+}
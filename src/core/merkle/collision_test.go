@@ -0,0 +1,87 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestRejectInternalNodeCollisions_CraftedLeaf(t *testing.T) {
+	leaves := makeLeaves([]string{"a", "b"})
+	internal, err := HashPair(leaves[0], leaves[1])
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	// Leaf 2 is the hash of the subtree over leaves 0 and 1
+	leaves = append(leaves, internal, makeLeaves([]string{"c"})[0])
+
+	proof, root, err := BuildProof(leaves, 2)
+	if err != nil {
+		t.Fatalf("BuildProof failed: %v", err)
+	}
+
+	// Legacy behaviour: the proof is structurally valid
+	if ok, err := VerifyProof(internal, 2, len(leaves), proof, root); !ok || err != nil {
+		t.Fatalf("detector off: ok=%v err=%v, want true, nil", ok, err)
+	}
+
+	SetRejectInternalNodeCollisions(true)
+	t.Cleanup(func() { SetRejectInternalNodeCollisions(false) })
+
+	ok, err := VerifyProof(internal, 2, len(leaves), proof, root)
+	if ok || !errors.Is(err, ErrInternalNodeCollision) {
+		t.Fatalf("detector on: ok=%v err=%v, want ErrInternalNodeCollision", ok, err)
+	}
+
+	// Leaves whose proofs do not reveal the collision still verify
+	proof, _, _ = BuildProof(leaves, 3)
+	if ok, err := VerifyProof(leaves[3], 3, len(leaves), proof, root); !ok || err != nil {
+		t.Errorf("leaf 3: ok=%v err=%v, want true, nil", ok, err)
+	}
+}
+
+func TestRejectInternalNodeCollisions_HonestTrees(t *testing.T) {
+	SetRejectInternalNodeCollisions(true)
+	t.Cleanup(func() { SetRejectInternalNodeCollisions(false) })
+
+	for n := 1; n <= 9; n++ {
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("leaf-%d", i)
+		}
+		leaves := makeLeaves(vals)
+		for i := range leaves {
+			proof, root, err := BuildProof(leaves, i)
+			if err != nil {
+				t.Fatalf("n=%d: BuildProof(%d) failed: %v", n, i, err)
+			}
+			if ok, err := VerifyProof(leaves[i], i, n, proof, root); !ok || err != nil {
+				t.Errorf("n=%d i=%d: ok=%v err=%v, want true, nil", n, i, ok, err)
+			}
+		}
+	}
+}
+
+func TestInternalNodeForgery_NeedsTrustedTreeSize(t *testing.T) {
+	leaves := makeLeaves([]string{"a", "b", "c", "d"})
+	root, err := BuildRoot(leaves)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	left, _ := HashPair(leaves[0], leaves[1])
+	right, _ := HashPair(leaves[2], leaves[3])
+
+	// The internal node over a, b posing as leaf 0 of a 2-leaf tree
+	forged := []ProofNode{{Hash: right, Position: "right"}}
+
+	SetRejectInternalNodeCollisions(true)
+	t.Cleanup(func() { SetRejectInternalNodeCollisions(false) })
+	if ok, err := VerifyProof(left, 0, 2, forged, root); !ok || err != nil {
+		t.Fatalf("claimed size: ok=%v err=%v; the forgery is a genuine 2-leaf proof", ok, err)
+	}
+
+	// Under the real size the truncated proof no longer fits the tree
+	if ok, err := VerifyProof(left, 0, len(leaves), forged, root); ok || !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("real size: ok=%v err=%v, want ErrInvalidProof", ok, err)
+	}
+}
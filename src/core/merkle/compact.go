@@ -0,0 +1,132 @@
+package merkle
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// CompactProof is a Merkle proof without positions: Hashes[i] is the sibling
+// at level i, from the leaf up. Positions are fully determined by the leaf
+// index and the tree size, including odd duplication, so storing them is
+// redundant.
+type CompactProof struct {
+	Hashes []string `json:"hashes"`
+}
+
+// CompactFromProof drops the positions of a verbose proof.
+func CompactFromProof(proof []ProofNode) CompactProof {
+	hashes := make([]string, len(proof))
+	for i, node := range proof {
+		hashes[i] = node.Hash
+	}
+	return CompactProof{Hashes: hashes}
+}
+
+// ProofFromCompact restores the verbose form of a compact proof for the leaf at
+// index in a tree of totalLeaves leaves. The number of hashes must equal the
+// tree depth.
+func ProofFromCompact(index, totalLeaves int, hashes []string) ([]ProofNode, error) {
+	if totalLeaves <= 0 {
+		return nil, fmt.Errorf("%w: totalLeaves must be positive", ErrInvalidTotalLeaves)
+	}
+	if index < 0 || index >= totalLeaves {
+		return nil, fmt.Errorf("%w: index %d, totalLeaves %d", ErrInvalidIndex, index, totalLeaves)
+	}
+	if depth := treeDepth(totalLeaves); len(hashes) != depth {
+		return nil, fmt.Errorf("%w: proof length %d, expected %d for totalLeaves=%d", ErrInvalidProof, len(hashes), depth, totalLeaves)
+	}
+
+	proof := make([]ProofNode, len(hashes))
+	for level, h := range hashes {
+		// Even nodes take their sibling (or themselves, when duplicated) on the right
+		position := "right"
+		if index%2 == 1 {
+			position = "left"
+		}
+		proof[level] = ProofNode{Hash: h, Position: position}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyCompactProof verifies a compact proof with the same strictness as
+// VerifyProof, including the odd-duplication rule.
+func VerifyCompactProof(leaf string, index int, totalLeaves int, hashes []string, root string) (bool, error) {
+	proof, err := ProofFromCompact(index, totalLeaves, hashes)
+	if err != nil {
+		return false, err
+	}
+	return VerifyProof(leaf, index, totalLeaves, proof, root)
+}
+
+// EncodeProofCompact packs a proof into an unpadded base64url string for
+// constrained transports (QR codes, headers): one byte with the node count, the
+// node positions as a bitmap (bit i set = node i is on the left), then each
+// node's raw digest bytes. It is about half the size of the hex JSON form.
+// The digest width is the active hash algorithm's.
+//
+// It returns ErrInvalidProof for a proof the format cannot carry faithfully:
+// more than 255 nodes or a position other than "left"/"right". A node hash
+// that is not canonical hex of the active width yields ErrInvalidLeafFormat.
+func EncodeProofCompact(proof []ProofNode) (string, error) {
+	if len(proof) > 255 {
+		return "", fmt.Errorf("%w: compact proof holds at most 255 nodes, got %d", ErrInvalidProof, len(proof))
+	}
+	width := len(activeScheme.Load().sum(nil))
+	blob := make([]byte, 1+(len(proof)+7)/8, 1+(len(proof)+7)/8+len(proof)*width)
+	blob[0] = byte(len(proof))
+	for i, node := range proof {
+		switch node.Position {
+		case "left":
+			blob[1+i/8] |= 1 << (i % 8)
+		case "right":
+		default:
+			return "", fmt.Errorf("%w: node %d has position %q", ErrInvalidProof, i, node.Position)
+		}
+		if err := checkHash(node.Hash, "proof node %d = %q", i, node.Hash); err != nil {
+			return "", err
+		}
+		raw, _ := hex.DecodeString(node.Hash)
+		blob = append(blob, raw...)
+	}
+	return base64.RawURLEncoding.EncodeToString(blob), nil
+}
+
+// DecodeProofCompact unpacks a string from EncodeProofCompact. It returns
+// ErrInvalidProof for anything that is not exactly such an encoding: bad
+// base64, a length that does not match the node count, set padding bits, or
+// more than 255 nodes.
+func DecodeProofCompact(s string) ([]ProofNode, error) {
+	blob, err := base64.RawURLEncoding.Strict().DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: compact proof is not base64url: %v", ErrInvalidProof, err)
+	}
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("%w: empty compact proof", ErrInvalidProof)
+	}
+
+	width := len(activeScheme.Load().sum(nil))
+	n := int(blob[0])
+	bitmap := blob[1:min(len(blob), 1+(n+7)/8)]
+	if want := 1 + (n+7)/8 + n*width; len(blob) != want {
+		return nil, fmt.Errorf("%w: compact proof of %d nodes must be %d bytes, got %d", ErrInvalidProof, n, want, len(blob))
+	}
+	if n%8 != 0 && bitmap[len(bitmap)-1]>>(n%8) != 0 {
+		return nil, fmt.Errorf("%w: compact proof has position bits past node %d", ErrInvalidProof, n-1)
+	}
+
+	digests := blob[1+len(bitmap):]
+	proof := make([]ProofNode, n)
+	for i := range proof {
+		position := "right"
+		if bitmap[i/8]&(1<<(i%8)) != 0 {
+			position = "left"
+		}
+		proof[i] = ProofNode{
+			Hash:     hex.EncodeToString(digests[i*width : (i+1)*width]),
+			Position: position,
+		}
+	}
+	return proof, nil
+}
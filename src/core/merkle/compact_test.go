@@ -0,0 +1,172 @@
+package merkle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompactProof_MatchesVerbose(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 7, 8, 11} {
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("leaf-%d", i)
+		}
+		leaves := makeLeaves(vals)
+
+		for index := 0; index < n; index++ {
+			proof, root, err := BuildProof(leaves, index)
+			if err != nil {
+				t.Fatalf("n=%d index=%d: BuildProof error: %v", n, index, err)
+			}
+			compact := CompactFromProof(proof)
+
+			// Index-derived positions reproduce the verbose proof exactly
+			restored, err := ProofFromCompact(index, n, compact.Hashes)
+			if err != nil {
+				t.Fatalf("n=%d index=%d: ProofFromCompact error: %v", n, index, err)
+			}
+			if !reflect.DeepEqual(restored, proof) {
+				t.Fatalf("n=%d index=%d: restored %+v, want %+v", n, index, restored, proof)
+			}
+
+			verbose, err := VerifyProof(leaves[index], index, n, proof, root)
+			if err != nil || !verbose {
+				t.Fatalf("n=%d index=%d: VerifyProof = %v, %v", n, index, verbose, err)
+			}
+			ok, err := VerifyCompactProof(leaves[index], index, n, compact.Hashes, root)
+			if err != nil || !ok {
+				t.Fatalf("n=%d index=%d: VerifyCompactProof = %v, %v", n, index, ok, err)
+			}
+		}
+	}
+}
+
+func TestCompactProof_OddLeafCountPositions(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+	proof, root, err := BuildProof(leaves, 4)
+	if err != nil {
+		t.Fatalf("BuildProof error: %v", err)
+	}
+
+	// Leaf 4 of 5 is duplicated at the first two levels, then joins from the right
+	restored, err := ProofFromCompact(4, 5, CompactFromProof(proof).Hashes)
+	if err != nil {
+		t.Fatalf("ProofFromCompact error: %v", err)
+	}
+	want := []string{"right", "right", "left"}
+	for i, node := range restored {
+		if node.Position != want[i] {
+			t.Errorf("level %d position = %s, want %s", i, node.Position, want[i])
+		}
+	}
+	if restored[0].Hash != leaves[4] {
+		t.Errorf("level 0 sibling should be the duplicated leaf itself")
+	}
+
+	// The same hashes claimed for another index do not verify
+	if ok, _ := VerifyCompactProof(leaves[4], 3, 5, CompactFromProof(proof).Hashes, root); ok {
+		t.Fatalf("compact proof verified for the wrong index")
+	}
+}
+
+func TestCompactProof_WrongLength(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C"})
+	proof, root, _ := BuildProof(leaves, 0)
+	hashes := CompactFromProof(proof).Hashes
+
+	if _, err := VerifyCompactProof(leaves[0], 0, 3, hashes[:1], root); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof, got %v", err)
+	}
+	if _, err := VerifyCompactProof(leaves[0], 3, 3, hashes, root); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("expected ErrInvalidIndex, got %v", err)
+	}
+}
+
+func TestEncodeProofCompact_RoundTrip(t *testing.T) {
+	for n := 0; n <= 20; n++ {
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("node-%d-%d", n, i)
+		}
+		proof := make([]ProofNode, n)
+		for i, h := range makeLeaves(vals) {
+			position := "right"
+			if (i*7+n)%3 == 0 {
+				position = "left"
+			}
+			proof[i] = ProofNode{Hash: h, Position: position}
+		}
+
+		encoded, err := EncodeProofCompact(proof)
+		if err != nil {
+			t.Fatalf("n=%d: EncodeProofCompact error: %v", n, err)
+		}
+		decoded, err := DecodeProofCompact(encoded)
+		if err != nil {
+			t.Fatalf("n=%d: DecodeProofCompact error: %v", n, err)
+		}
+		if !reflect.DeepEqual(decoded, proof) {
+			t.Fatalf("n=%d: decoded %+v, want %+v", n, decoded, proof)
+		}
+		if n > 0 {
+			verbose, _ := json.Marshal(proof)
+			if len(encoded) > len(verbose)*6/10 {
+				t.Errorf("n=%d: compact %d bytes vs JSON %d, want about half", n, len(encoded), len(verbose))
+			}
+		}
+	}
+}
+
+func TestDecodeProofCompact_RejectsMalformed(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+	proof, _, _ := BuildProof(leaves, 4)
+	encoded, err := EncodeProofCompact(proof)
+	if err != nil {
+		t.Fatalf("EncodeProofCompact error: %v", err)
+	}
+	blob, _ := base64.RawURLEncoding.DecodeString(encoded)
+
+	extraBit := append([]byte(nil), blob...)
+	extraBit[1] |= 1 << 7
+
+	for name, s := range map[string]string{
+		"truncated":      encoded[:len(encoded)-4],
+		"trailing bytes": base64.RawURLEncoding.EncodeToString(append(blob, 0)),
+		"padding bit":    base64.RawURLEncoding.EncodeToString(extraBit),
+		"not base64":     "!!!" + encoded,
+		"padded":         base64.URLEncoding.EncodeToString(blob[:2]),
+		"empty":          "",
+	} {
+		if _, err := DecodeProofCompact(s); !errors.Is(err, ErrInvalidProof) {
+			t.Errorf("%s: expected ErrInvalidProof, got %v", name, err)
+		}
+	}
+}
+
+func TestEncodeProofCompact_RejectsMalformed(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B"})
+	tooLong := make([]ProofNode, 256)
+	for i := range tooLong {
+		tooLong[i] = ProofNode{Hash: leaves[0], Position: "right"}
+	}
+
+	for name, tc := range map[string]struct {
+		proof []ProofNode
+		want  error
+	}{
+		"256 nodes":     {tooLong, ErrInvalidProof},
+		"bad position":  {[]ProofNode{{Hash: leaves[0], Position: "up"}}, ErrInvalidProof},
+		"non-hex hash":  {[]ProofNode{{Hash: strings.Repeat("zz", 32), Position: "left"}}, ErrInvalidLeafFormat},
+		"short hash":    {[]ProofNode{{Hash: leaves[1][:62], Position: "left"}}, ErrInvalidLeafFormat},
+		"uppercase hex": {[]ProofNode{{Hash: strings.ToUpper(leaves[1]), Position: "right"}}, ErrNonCanonicalHash},
+	} {
+		if s, err := EncodeProofCompact(tc.proof); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %q, %v", name, tc.want, s, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package merkle
+
+import (
+	"fmt"
+)
+
+// BuildConsistencyNodes returns the nodes a holder of an inclusion proof from
+// the first oldSize leaves needs to extend it to the tree over all leaves (see
+// ExtendProof). For each level of the new tree, from the leaves up, it lists
+// the node covering the last old leaf (position "left") and, if the level has
+// one, the node right after it ("right"): at most two nodes per level, the same
+// for every old index.
+//
+// These nodes do not prove by themselves that the old root is a prefix of the
+// new one; an extended proof is only meaningful once verified against a trusted
+// new root.
+func BuildConsistencyNodes(leaves []string, oldSize int) ([]ProofNode, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyLeaves
+	}
+	if oldSize <= 0 || oldSize > len(leaves) {
+		return nil, fmt.Errorf("%w: oldSize %d, total leaves %d", ErrInvalidTotalLeaves, oldSize, len(leaves))
+	}
+	for i, leaf := range leaves {
+		if err := checkHash(leaf, "leaf[%d] = %q", i, leaf); err != nil {
+			return nil, err
+		}
+	}
+
+	nodes := []ProofNode{}
+	level := make([]string, len(leaves))
+	copy(level, leaves)
+	boundary := oldSize - 1
+
+	for len(level) > 1 {
+		nodes = append(nodes, ProofNode{Hash: level[boundary], Position: "left"})
+		if boundary+1 < len(level) {
+			nodes = append(nodes, ProofNode{Hash: level[boundary+1], Position: "right"})
+		}
+
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, err := HashPair(level[i], right)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, parent)
+		}
+		level = next
+		boundary /= 2
+	}
+	return nodes, nil
+}
+
+// ExtendProof rewrites an inclusion proof for leaf oldIndex of a tree of oldSize
+// leaves into the proof for the same leaf in the tree of newSize leaves that
+// grew from it by appends, without the leaf list.
+//
+// A sibling lying entirely before the last old leaf is the same node in both
+// trees and is taken from oldProof; siblings touching or past the old boundary
+// come from consistency, as produced by BuildConsistencyNodes for (oldSize,
+// newSize). The result equals BuildProof over the new leaves and must still be
+// checked with VerifyProof against the new root.
+func ExtendProof(oldProof []ProofNode, oldIndex, oldSize int, consistency []ProofNode, newSize int) ([]ProofNode, error) {
+	if oldSize <= 0 || newSize < oldSize {
+		return nil, fmt.Errorf("%w: cannot extend from %d to %d leaves", ErrInvalidTotalLeaves, oldSize, newSize)
+	}
+	if oldIndex < 0 || oldIndex >= oldSize {
+		return nil, fmt.Errorf("%w: index %d, oldSize %d", ErrInvalidIndex, oldIndex, oldSize)
+	}
+	if len(oldProof) != treeDepth(oldSize) {
+		return nil, fmt.Errorf("%w: old proof length %d, expected %d for oldSize=%d", ErrInvalidProof, len(oldProof), treeDepth(oldSize), oldSize)
+	}
+	for i, node := range oldProof {
+		if err := checkHash(node.Hash, "oldProof[%d].hash = %q", i, node.Hash); err != nil {
+			return nil, err
+		}
+		if want := expectedPosition(oldIndex >> i); node.Position != want {
+			return nil, fmt.Errorf("%w: oldProof[%d].position %q != expected %q", ErrInvalidProof, i, node.Position, want)
+		}
+	}
+	for i, node := range consistency {
+		if err := checkHash(node.Hash, "consistency[%d].hash = %q", i, node.Hash); err != nil {
+			return nil, err
+		}
+	}
+
+	proof := make([]ProofNode, 0, treeDepth(newSize))
+	next := 0
+	take := func(position string) (string, error) {
+		if next >= len(consistency) {
+			return "", fmt.Errorf("%w: consistency too short, missing %s node", ErrInvalidProof, position)
+		}
+		node := consistency[next]
+		if node.Position != position {
+			return "", fmt.Errorf("%w: consistency[%d].position %q != expected %q", ErrInvalidProof, next, node.Position, position)
+		}
+		next++
+		return node.Hash, nil
+	}
+
+	n := newSize
+	for level := 0; n > 1; level++ {
+		index, boundary := oldIndex>>level, (oldSize-1)>>level
+
+		// The boundary node, and the node after it if the level has one
+		boundaryHash, err := take("left")
+		if err != nil {
+			return nil, err
+		}
+		afterHash := ""
+		if boundary+1 < n {
+			if afterHash, err = take("right"); err != nil {
+				return nil, err
+			}
+		}
+
+		switch sibling := index ^ 1; {
+		case sibling < boundary:
+			// Complete in the old tree, unchanged by the appends
+			proof = append(proof, oldProof[level])
+		case sibling == boundary:
+			proof = append(proof, ProofNode{Hash: boundaryHash, Position: "right"})
+		case afterHash != "":
+			proof = append(proof, ProofNode{Hash: afterHash, Position: "right"})
+		default:
+			// Last node of an odd level: paired with itself
+			proof = append(proof, ProofNode{Hash: boundaryHash, Position: "right"})
+		}
+		n = (n + 1) / 2
+	}
+
+	if next != len(consistency) {
+		return nil, fmt.Errorf("%w: %d unused consistency nodes", ErrInvalidProof, len(consistency)-next)
+	}
+	return proof, nil
+}
+
+// expectedPosition is the side of the sibling of the node at index
+func expectedPosition(index int) string {
+	if index%2 == 1 {
+		return "left"
+	}
+	return "right"
+}
@@ -0,0 +1,111 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// extendAndCheck extends the proof of oldIndex from oldSize to len(leaves) leaves
+// and asserts it equals the freshly built proof and verifies against the new root
+func extendAndCheck(t *testing.T, leaves []string, oldIndex, oldSize int) {
+	t.Helper()
+	oldProof, _, err := BuildProof(leaves[:oldSize], oldIndex)
+	if err != nil {
+		t.Fatalf("BuildProof(old) error: %v", err)
+	}
+	consistency, err := BuildConsistencyNodes(leaves, oldSize)
+	if err != nil {
+		t.Fatalf("BuildConsistencyNodes error: %v", err)
+	}
+
+	extended, err := ExtendProof(oldProof, oldIndex, oldSize, consistency, len(leaves))
+	if err != nil {
+		t.Fatalf("ExtendProof(index=%d, %d->%d) error: %v", oldIndex, oldSize, len(leaves), err)
+	}
+	want, root, err := BuildProof(leaves, oldIndex)
+	if err != nil {
+		t.Fatalf("BuildProof(new) error: %v", err)
+	}
+	if !reflect.DeepEqual(extended, want) {
+		t.Fatalf("index=%d %d->%d: extended %+v, want %+v", oldIndex, oldSize, len(leaves), extended, want)
+	}
+	ok, err := VerifyProof(leaves[oldIndex], oldIndex, len(leaves), extended, root)
+	if err != nil || !ok {
+		t.Fatalf("extended proof does not verify: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestExtendProof_OneAppend(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E", "F"})
+	for oldSize := 1; oldSize < len(leaves); oldSize++ {
+		for i := 0; i < oldSize; i++ {
+			extendAndCheck(t, leaves[:oldSize+1], i, oldSize)
+		}
+	}
+}
+
+func TestExtendProof_SeveralAppends(t *testing.T) {
+	vals := make([]string, 17)
+	for i := range vals {
+		vals[i] = fmt.Sprintf("leaf-%d", i)
+	}
+	leaves := makeLeaves(vals)
+
+	for newSize := 1; newSize <= len(leaves); newSize++ {
+		for oldSize := 1; oldSize <= newSize; oldSize++ {
+			for i := 0; i < oldSize; i++ {
+				extendAndCheck(t, leaves[:newSize], i, oldSize)
+			}
+		}
+	}
+}
+
+func TestExtendProof_RejectsMalformedInput(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+	oldProof, _, _ := BuildProof(leaves[:3], 1)
+	consistency, _ := BuildConsistencyNodes(leaves, 3)
+
+	if _, err := ExtendProof(oldProof, 1, 3, consistency, 2); !errors.Is(err, ErrInvalidTotalLeaves) {
+		t.Errorf("shrinking tree: expected ErrInvalidTotalLeaves, got %v", err)
+	}
+	if _, err := ExtendProof(oldProof, 3, 3, consistency, 5); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("index out of range: expected ErrInvalidIndex, got %v", err)
+	}
+	if _, err := ExtendProof(oldProof[:1], 1, 3, consistency, 5); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("short old proof: expected ErrInvalidProof, got %v", err)
+	}
+	if _, err := ExtendProof(oldProof, 1, 3, consistency[:len(consistency)-1], 5); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("short consistency: expected ErrInvalidProof, got %v", err)
+	}
+	if _, err := ExtendProof(oldProof, 1, 3, append(consistency, consistency[0]), 5); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("extra consistency node: expected ErrInvalidProof, got %v", err)
+	}
+
+	swapped := append([]ProofNode(nil), oldProof...)
+	swapped[0].Position = "right"
+	if _, err := ExtendProof(swapped, 1, 3, consistency, 5); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("wrong old position: expected ErrInvalidProof, got %v", err)
+	}
+
+	if _, err := BuildConsistencyNodes(leaves, 6); !errors.Is(err, ErrInvalidTotalLeaves) {
+		t.Errorf("oldSize beyond leaves: expected ErrInvalidTotalLeaves, got %v", err)
+	}
+}
+
+func TestExtendProof_TamperedConsistencyFailsVerification(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+	oldProof, _, _ := BuildProof(leaves[:3], 2)
+	consistency, _ := BuildConsistencyNodes(leaves, 3)
+	root, _ := BuildRoot(leaves)
+
+	consistency[len(consistency)-1].Hash = leaves[0]
+	extended, err := ExtendProof(oldProof, 2, 3, consistency, 5)
+	if err != nil {
+		t.Fatalf("ExtendProof error: %v", err)
+	}
+	if ok, _ := VerifyProof(leaves[2], 2, 5, extended, root); ok {
+		t.Fatal("proof built from tampered consistency nodes verified")
+	}
+}
@@ -0,0 +1,70 @@
+package merkle
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+)
+
+// flipBit flips bit (mod the digest width) of a hex hash, keeping it canonical hex.
+func flipBit(h string, bit int) string {
+	raw, _ := hex.DecodeString(h)
+	bit %= len(raw) * 8
+	raw[bit/8] ^= 1 << (bit % 8)
+	return hex.EncodeToString(raw)
+}
+
+// FuzzVerifyProof builds a real proof over a random leaf set, then mutates one
+// bit of the leaf, one proof node (hash bit or side) or the root. The genuine
+// proof must verify; no mutation may verify or panic.
+func FuzzVerifyProof(f *testing.F) {
+	f.Add("seed", uint8(1), uint16(0), uint16(0), uint16(0))
+	f.Add("seed", uint8(2), uint16(1), uint16(1), uint16(7))
+	f.Add("odd", uint8(5), uint16(4), uint16(2), uint16(255))
+	f.Add("odd", uint8(7), uint16(6), uint16(9), uint16(3))
+	f.Add("big", uint8(255), uint16(128), uint16(17), uint16(100))
+
+	f.Fuzz(func(t *testing.T, seed string, n uint8, index, target, bit uint16) {
+		if n == 0 {
+			return
+		}
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("%s/%d", seed, i)
+		}
+		leaves := makeLeaves(vals)
+		idx := int(index) % len(leaves)
+
+		proof, root, err := BuildProof(leaves, idx)
+		if err != nil {
+			t.Fatalf("BuildProof(%d leaves, %d) failed: %v", len(leaves), idx, err)
+		}
+		if ok, err := VerifyProof(leaves[idx], idx, len(leaves), proof, root); !ok || err != nil {
+			t.Fatalf("genuine proof rejected: ok=%v err=%v", ok, err)
+		}
+
+		leaf := leaves[idx]
+		mutated := append([]ProofNode(nil), proof...)
+		// Targets: 0 leaf, 1 root, then a hash bit or the side of each proof node
+		switch slot := int(target) % (2 + 2*len(proof)); {
+		case slot == 0:
+			leaf = flipBit(leaf, int(bit))
+		case slot == 1:
+			root = flipBit(root, int(bit))
+		case slot%2 == 0:
+			node := &mutated[(slot-2)/2]
+			node.Hash = flipBit(node.Hash, int(bit))
+		default:
+			node := &mutated[(slot-2)/2]
+			if node.Position == "left" {
+				node.Position = "right"
+			} else {
+				node.Position = "left"
+			}
+		}
+
+		if ok, err := VerifyProof(leaf, idx, len(leaves), mutated, root); ok && err == nil {
+			t.Fatalf("mutated proof verified: leaves=%d index=%d target=%d bit=%d", len(leaves), idx, target, bit)
+		}
+	})
+}
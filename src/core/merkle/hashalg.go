@@ -0,0 +1,62 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+)
+
+// ErrUnsupportedHashAlg is returned by SetHashAlg for an algorithm other than sha256 or sha512.
+var ErrUnsupportedHashAlg = errors.New("unsupported hash algorithm")
+
+// hashScheme is the node hash of the tree: leaves, proof nodes and roots are
+// lowercase hex digests of this function, and parents hash left||right with it.
+type hashScheme struct {
+	name    string
+	pattern *regexp.Regexp // lowercase hex of the digest width
+	anyCase *regexp.Regexp // same width, either case
+	sum     func(data []byte) []byte
+}
+
+var hashSchemes = map[string]*hashScheme{
+	"sha256": {
+		name:    "sha256",
+		pattern: regexp.MustCompile(`^[a-f0-9]{64}$`),
+		anyCase: regexp.MustCompile(`^[a-fA-F0-9]{64}$`),
+		sum:     func(b []byte) []byte { sum := sha256.Sum256(b); return sum[:] },
+	},
+	"sha512": {
+		name:    "sha512",
+		pattern: regexp.MustCompile(`^[a-f0-9]{128}$`),
+		anyCase: regexp.MustCompile(`^[a-fA-F0-9]{128}$`),
+		sum:     func(b []byte) []byte { sum := sha512.Sum512(b); return sum[:] },
+	},
+}
+
+// activeScheme is the scheme every tree function uses (sha256 unless SetHashAlg changed it)
+var activeScheme atomic.Pointer[hashScheme]
+
+func init() {
+	activeScheme.Store(hashSchemes["sha256"])
+}
+
+// SetHashAlg selects the node hash for every subsequent call: "sha256" (the
+// canon default, 64-char hex nodes) or "sha512" (128-char hex nodes). Leaves
+// must be digests of the same algorithm. It is process-wide; switching while
+// trees are being built or verified mixes widths and fails validation.
+func SetHashAlg(alg string) error {
+	scheme, ok := hashSchemes[alg]
+	if !ok {
+		return fmt.Errorf("%w: %q (supported: sha256, sha512)", ErrUnsupportedHashAlg, alg)
+	}
+	activeScheme.Store(scheme)
+	return nil
+}
+
+// HashAlg returns the active node hash algorithm name.
+func HashAlg() string {
+	return activeScheme.Load().name
+}
@@ -0,0 +1,65 @@
+package merkle
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// useSHA512 switches the tree hash to sha512 for the duration of the test
+func useSHA512(t *testing.T) {
+	t.Helper()
+	if err := SetHashAlg("sha512"); err != nil {
+		t.Fatalf("SetHashAlg failed: %v", err)
+	}
+	t.Cleanup(func() { SetHashAlg("sha256") })
+}
+
+func sha512Leaf(s string) string {
+	sum := sha512.Sum512([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestSetHashAlg_SHA512Tree(t *testing.T) {
+	useSHA512(t)
+	leaves := []string{sha512Leaf("0"), sha512Leaf("1"), sha512Leaf("2")}
+
+	left, _ := hex.DecodeString(leaves[0])
+	right, _ := hex.DecodeString(leaves[1])
+	sum := sha512.Sum512(append(left, right...))
+	want := hex.EncodeToString(sum[:])
+	if got, err := HashPair(leaves[0], leaves[1]); err != nil || got != want {
+		t.Fatalf("HashPair = %s, %v; want %s", got, err, want)
+	}
+
+	root, err := BuildRoot(leaves)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	for i, leaf := range leaves {
+		proof, _, err := BuildProof(leaves, i)
+		if err != nil {
+			t.Fatalf("BuildProof(%d) failed: %v", i, err)
+		}
+		if ok, err := VerifyProof(leaf, i, len(leaves), proof, root); !ok || err != nil {
+			t.Errorf("VerifyProof(%d) = %v, %v", i, ok, err)
+		}
+	}
+}
+
+func TestSetHashAlg_SHA512RejectsSHA256Leaves(t *testing.T) {
+	useSHA512(t)
+	if _, err := BuildRoot(makeLeaves([]string{"a", "b"})); !errors.Is(err, ErrInvalidLeafFormat) {
+		t.Fatalf("expected ErrInvalidLeafFormat for 64-char leaves, got %v", err)
+	}
+}
+
+func TestSetHashAlg_Unsupported(t *testing.T) {
+	if err := SetHashAlg("sha1"); !errors.Is(err, ErrUnsupportedHashAlg) {
+		t.Fatalf("expected ErrUnsupportedHashAlg, got %v", err)
+	}
+	if HashAlg() != "sha256" {
+		t.Errorf("HashAlg = %s after a rejected switch", HashAlg())
+	}
+}
@@ -0,0 +1,23 @@
+package merkle
+
+import (
+	"encoding/hex"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+// LeafHashFromObject is the canonical leaf derivation: obj is encoded with
+// hash.Canonicalize (sorted keys, no insignificant whitespace, no HTML
+// escaping) and the leaf is the lowercase hex digest of those bytes under the
+// active hash algorithm. It is the same value the ledger's ComputeObjectHash
+// returns for the canonical bytes, so a register's object_hash_hex and its
+// leaf in the epoch tree are always identical.
+//
+// Returns hash.ErrCanonicalize if obj cannot be represented as canonical JSON.
+func LeafHashFromObject(obj any) (string, error) {
+	canonical, err := hash.Canonicalize(obj)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(activeScheme.Load().sum(canonical)), nil
+}
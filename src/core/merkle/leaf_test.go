@@ -0,0 +1,58 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+)
+
+func TestLeafHashFromObject(t *testing.T) {
+	obj := map[string]interface{}{"b": 2, "a": "<x>", "c": []int{1, 2}}
+
+	leaf, err := LeafHashFromObject(obj)
+	if err != nil {
+		t.Fatalf("LeafHashFromObject failed: %v", err)
+	}
+	canonical, err := hash.Canonicalize(obj)
+	if err != nil {
+		t.Fatalf("Canonicalize failed: %v", err)
+	}
+	if want := hash.Sha256Hex(canonical); leaf != want {
+		t.Fatalf("leaf = %s, want SHA-256 of canonical bytes %s", leaf, want)
+	}
+	if err := checkHash(leaf, "leaf"); err != nil {
+		t.Fatalf("leaf is not a valid leaf: %v", err)
+	}
+
+	// Key order and formatting do not change the leaf
+	reordered := struct {
+		A string `json:"a"`
+		B int    `json:"b"`
+		C []int  `json:"c"`
+	}{"<x>", 2, []int{1, 2}}
+	if other, _ := LeafHashFromObject(reordered); other != leaf {
+		t.Errorf("struct leaf = %s, want %s", other, leaf)
+	}
+}
+
+func TestLeafHashFromObject_Unencodable(t *testing.T) {
+	if _, err := LeafHashFromObject(func() {}); !errors.Is(err, hash.ErrCanonicalize) {
+		t.Fatalf("expected ErrCanonicalize, got %v", err)
+	}
+}
+
+func TestLeafHashFromObject_FollowsHashAlg(t *testing.T) {
+	if err := SetHashAlg("sha512"); err != nil {
+		t.Fatalf("SetHashAlg failed: %v", err)
+	}
+	t.Cleanup(func() { SetHashAlg("sha256") })
+
+	leaf, err := LeafHashFromObject(map[string]int{"a": 1})
+	if err != nil {
+		t.Fatalf("LeafHashFromObject failed: %v", err)
+	}
+	if len(leaf) != 128 {
+		t.Fatalf("sha512 leaf has %d hex chars, want 128", len(leaf))
+	}
+}
@@ -0,0 +1,77 @@
+package merkle
+
+import "fmt"
+
+// LevelTree is a Merkle tree that keeps every level, so one build can serve
+// any number of proofs in O(log n) each. It follows BuildRoot exactly: an odd
+// last node is paired with itself, and a single leaf is its own root.
+//
+// A LevelTree is immutable once built and safe for concurrent readers.
+type LevelTree struct {
+	levels [][]string // levels[0] are the leaves, the last level holds the root
+}
+
+// BuildLevelTree hashes leaves into a LevelTree. It rejects the same input as BuildRoot.
+func BuildLevelTree(leaves []string) (*LevelTree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyLeaves
+	}
+	for i, leaf := range leaves {
+		if err := checkHash(leaf, "leaf[%d] = %q", i, leaf); err != nil {
+			return nil, err
+		}
+	}
+
+	level := append([]string(nil), leaves...)
+	levels := [][]string{level}
+	for len(level) > 1 {
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, err := HashPair(level[i], right)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, parent)
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &LevelTree{levels: levels}, nil
+}
+
+// Len returns the number of leaves.
+func (t *LevelTree) Len() int {
+	return len(t.levels[0])
+}
+
+// Root returns the Merkle root.
+func (t *LevelTree) Root() string {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Proof returns the inclusion proof for the leaf at index, identical to what
+// BuildProof returns for the same leaves.
+func (t *LevelTree) Proof(index int) ([]ProofNode, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, fmt.Errorf("%w: index %d, total leaves %d", ErrInvalidIndex, index, t.Len())
+	}
+
+	proof := make([]ProofNode, 0, len(t.levels)-1)
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if index%2 == 0 {
+			sibling := level[index]
+			if index+1 < len(level) {
+				sibling = level[index+1]
+			}
+			proof = append(proof, ProofNode{Hash: sibling, Position: "right"})
+		} else {
+			proof = append(proof, ProofNode{Hash: level[index-1], Position: "left"})
+		}
+		index /= 2
+	}
+	return proof, nil
+}
@@ -0,0 +1,57 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestLevelTree_MatchesBuildProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("leaf-%d", i)
+		}
+		leaves := makeLeaves(vals)
+
+		tree, err := BuildLevelTree(leaves)
+		if err != nil {
+			t.Fatalf("n=%d: BuildLevelTree failed: %v", n, err)
+		}
+		root, err := BuildRoot(leaves)
+		if err != nil {
+			t.Fatalf("n=%d: BuildRoot failed: %v", n, err)
+		}
+		if tree.Root() != root || tree.Len() != n {
+			t.Fatalf("n=%d: Root/Len = %s/%d, want %s/%d", n, tree.Root(), tree.Len(), root, n)
+		}
+
+		for i := 0; i < n; i++ {
+			got, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("n=%d: Proof(%d) failed: %v", n, i, err)
+			}
+			want, _, _ := BuildProof(leaves, i)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("n=%d: Proof(%d) = %v, want %v", n, i, got, want)
+			}
+		}
+	}
+}
+
+func TestLevelTree_InvalidInput(t *testing.T) {
+	if _, err := BuildLevelTree(nil); !errors.Is(err, ErrEmptyLeaves) {
+		t.Errorf("expected ErrEmptyLeaves, got %v", err)
+	}
+	if _, err := BuildLevelTree([]string{"abc"}); !errors.Is(err, ErrInvalidLeafFormat) {
+		t.Errorf("expected ErrInvalidLeafFormat, got %v", err)
+	}
+
+	tree, _ := BuildLevelTree(makeLeaves([]string{"a", "b"}))
+	for _, index := range []int{-1, 2} {
+		if _, err := tree.Proof(index); !errors.Is(err, ErrInvalidIndex) {
+			t.Errorf("Proof(%d): expected ErrInvalidIndex, got %v", index, err)
+		}
+	}
+}
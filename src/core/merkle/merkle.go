@@ -1,11 +1,9 @@
 package merkle
 
 import (
-    "crypto/sha256"
     "encoding/hex"
     "errors"
     "fmt"
-    "regexp"
 )
 
 var (
@@ -23,10 +21,28 @@ var (
 
     // ErrInvalidTotalLeaves is returned when totalLeaves is invalid.
     ErrInvalidTotalLeaves = errors.New("invalid totalLeaves parameter")
+
+    // ErrNonCanonicalHash is returned when a hash is 64 valid hex chars but not
+    // all lowercase. Canon forbids it, so errors.Is also matches ErrInvalidLeafFormat;
+    // lowercasing the input fixes it.
+    ErrNonCanonicalHash = errors.New("hash must be lowercase")
 )
 
-// hashPattern validates SHA-256 hex strings (64 lowercase hex chars).
-var hashPattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
+// checkHash validates h as lowercase hex of the active hash width (64 chars for
+// sha256, 128 for sha512). The detail (format, args) names the offending input.
+// Uppercase hex yields ErrNonCanonicalHash; wrong length or non-hex yields
+// plain ErrInvalidLeafFormat.
+func checkHash(h string, format string, args ...interface{}) error {
+    scheme := activeScheme.Load()
+    if scheme.pattern.MatchString(h) {
+        return nil
+    }
+    detail := fmt.Sprintf(format, args...)
+    if scheme.anyCase.MatchString(h) {
+        return fmt.Errorf("%w (%w): %s", ErrNonCanonicalHash, ErrInvalidLeafFormat, detail)
+    }
+    return fmt.Errorf("%w: %s", ErrInvalidLeafFormat, detail)
+}
 
 // ProofNode represents a single node in a Merkle proof path.
 // Position indicates whether this hash should be concatenated on the "left" or "right"
@@ -42,8 +58,8 @@ func BuildRoot(leaves []string) (string, error) {
         return "", ErrEmptyLeaves
     }
     for i, leaf := range leaves {
-        if !hashPattern.MatchString(leaf) {
-            return "", fmt.Errorf("%w: leaf[%d] = %q", ErrInvalidLeafFormat, i, leaf)
+        if err := checkHash(leaf, "leaf[%d] = %q", i, leaf); err != nil {
+            return "", err
         }
     }
     if len(leaves) == 1 {
@@ -61,7 +77,7 @@ func BuildRoot(leaves []string) (string, error) {
             if i+1 < len(currentLevel) {
                 right = currentLevel[i+1]
             }
-            parent, err := hashPair(left, right)
+            parent, err := HashPair(left, right)
             if err != nil {
                 return "", err
             }
@@ -72,6 +88,26 @@ func BuildRoot(leaves []string) (string, error) {
     return currentLevel[0], nil
 }
 
+// BuildRootWithStats is BuildRoot that also reports the tree shape: depth is the
+// number of hashing levels (0 for a single leaf), which is also the proof length
+// for any leaf, and leafCount is len(leaves).
+func BuildRootWithStats(leaves []string) (root string, depth int, leafCount int, err error) {
+    root, err = BuildRoot(leaves)
+    if err != nil {
+        return "", 0, 0, err
+    }
+    return root, treeDepth(len(leaves)), len(leaves), nil
+}
+
+// treeDepth returns the number of hashing levels for n leaves, with odd duplication.
+func treeDepth(n int) int {
+    depth := 0
+    for ; n > 1; n = (n + 1) / 2 {
+        depth++
+    }
+    return depth
+}
+
 // BuildProof generates a Merkle proof for the leaf at the specified index.
 func BuildProof(leaves []string, index int) ([]ProofNode, string, error) {
     if len(leaves) == 0 {
@@ -81,8 +117,8 @@ func BuildProof(leaves []string, index int) ([]ProofNode, string, error) {
         return nil, "", fmt.Errorf("%w: index %d, total leaves %d", ErrInvalidIndex, index, len(leaves))
     }
     for i, leaf := range leaves {
-        if !hashPattern.MatchString(leaf) {
-            return nil, "", fmt.Errorf("%w: leaf[%d] = %q", ErrInvalidLeafFormat, i, leaf)
+        if err := checkHash(leaf, "leaf[%d] = %q", i, leaf); err != nil {
+            return nil, "", err
         }
     }
     if len(leaves) == 1 {
@@ -109,7 +145,7 @@ func BuildProof(leaves []string, index int) ([]ProofNode, string, error) {
                     proof = append(proof, ProofNode{Hash: left, Position: "left"})
                 }
             }
-            parent, err := hashPair(left, right)
+            parent, err := HashPair(left, right)
             if err != nil {
                 return nil, "", err
             }
@@ -123,49 +159,58 @@ func BuildProof(leaves []string, index int) ([]ProofNode, string, error) {
 
 // VerifyProof verifies a Merkle proof with strict binding to index/totalLeaves.
 func VerifyProof(leaf string, index int, totalLeaves int, proof []ProofNode, expectedRoot string) (bool, error) {
-    if !hashPattern.MatchString(leaf) {
-        return false, fmt.Errorf("%w: leaf = %q", ErrInvalidLeafFormat, leaf)
+    ok, _, err := VerifyProofDetailed(leaf, index, totalLeaves, proof, expectedRoot)
+    return ok, err
+}
+
+// VerifyProofDetailed is VerifyProof that also returns the root reconstructed
+// from the leaf and proof, so a failed check can show computed vs expected.
+// computedRoot is empty when the inputs are rejected before any hashing.
+func VerifyProofDetailed(leaf string, index int, totalLeaves int, proof []ProofNode, expectedRoot string) (ok bool, computedRoot string, err error) {
+    if err := checkHash(leaf, "leaf = %q", leaf); err != nil {
+        return false, "", err
     }
-    if !hashPattern.MatchString(expectedRoot) {
-        return false, fmt.Errorf("%w: expectedRoot = %q", ErrInvalidLeafFormat, expectedRoot)
+    if err := checkHash(expectedRoot, "expectedRoot = %q", expectedRoot); err != nil {
+        return false, "", err
     }
     if totalLeaves <= 0 {
-        return false, fmt.Errorf("%w: totalLeaves must be positive", ErrInvalidTotalLeaves)
+        return false, "", fmt.Errorf("%w: totalLeaves must be positive", ErrInvalidTotalLeaves)
     }
     if index < 0 || index >= totalLeaves {
-        return false, fmt.Errorf("%w: index %d, totalLeaves %d", ErrInvalidIndex, index, totalLeaves)
+        return false, "", fmt.Errorf("%w: index %d, totalLeaves %d", ErrInvalidIndex, index, totalLeaves)
     }
     if totalLeaves == 1 {
         if len(proof) != 0 {
-            return false, fmt.Errorf("%w: single leaf should have empty proof", ErrInvalidProof)
+            return false, "", fmt.Errorf("%w: single leaf should have empty proof", ErrInvalidProof)
         }
-        return leaf == expectedRoot, nil
+        return leaf == expectedRoot, leaf, nil
     }
 
     // Expected proof length = tree height
-    expectedLen := 0
-    for n := totalLeaves; n > 1; n = (n + 1) / 2 {
-        expectedLen++
-    }
+    expectedLen := treeDepth(totalLeaves)
     if len(proof) != expectedLen {
-        return false, fmt.Errorf("%w: proof length %d, expected %d for totalLeaves=%d", ErrInvalidProof, len(proof), expectedLen, totalLeaves)
+        return false, "", fmt.Errorf("%w: proof length %d, expected %d for totalLeaves=%d", ErrInvalidProof, len(proof), expectedLen, totalLeaves)
     }
 
     for i, node := range proof {
-        if !hashPattern.MatchString(node.Hash) {
-            return false, fmt.Errorf("%w: proof[%d].hash = %q", ErrInvalidLeafFormat, i, node.Hash)
+        if err := checkHash(node.Hash, "proof[%d].hash = %q", i, node.Hash); err != nil {
+            return false, "", err
         }
         if node.Position != "left" && node.Position != "right" {
-            return false, fmt.Errorf("%w: proof[%d].position must be 'left' or 'right', got %q", ErrInvalidProof, i, node.Position)
+            return false, "", fmt.Errorf("%w: proof[%d].position must be 'left' or 'right', got %q", ErrInvalidProof, i, node.Position)
+        }
+    }
+
+    if rejectInternalNodeCollisions.Load() {
+        if err := checkLeafCollision(leaf, proof); err != nil {
+            return false, "", err
         }
     }
 
-    currentHash := leaf
     curIndex := index
     curN := totalLeaves
 
-    for level := 0; level < len(proof); level++ {
-        node := proof[level]
+    root, err := walkProof(leaf, proof, func(level int, currentHash string, node ProofNode) error {
         expectedPos := "right"
         sibIndex := curIndex + 1
         if curIndex%2 == 1 {
@@ -173,11 +218,51 @@ func VerifyProof(leaf string, index int, totalLeaves int, proof []ProofNode, exp
             sibIndex = curIndex - 1
         }
         if node.Position != expectedPos {
-            return false, fmt.Errorf("%w: proof[%d].position %q != expected %q (index=%d levelN=%d)", ErrInvalidProof, level, node.Position, expectedPos, curIndex, curN)
+            return fmt.Errorf("%w: proof[%d].position %q != expected %q (index=%d levelN=%d)", ErrInvalidProof, level, node.Position, expectedPos, curIndex, curN)
         }
         if sibIndex < 0 || sibIndex >= curN {
             if node.Hash != currentHash {
-                return false, fmt.Errorf("%w: proof[%d] violates odd-duplication rule (expected sibling==current)", ErrInvalidProof, level)
+                return fmt.Errorf("%w: proof[%d] violates odd-duplication rule (expected sibling==current)", ErrInvalidProof, level)
+            }
+        }
+        curIndex = curIndex / 2
+        curN = (curN + 1) / 2
+        return nil
+    })
+    if err != nil {
+        return false, "", err
+    }
+    return root == expectedRoot, root, nil
+}
+
+// RootFromProof reconstructs the root implied by a leaf and its proof path,
+// applying each node on its declared side. It validates only hash formats and
+// positions: it does NOT check the path against an index or tree size, so the
+// result is only meaningful when compared with a trusted root. Use VerifyProof
+// for full validation.
+func RootFromProof(leaf string, proof []ProofNode) (string, error) {
+    if err := checkHash(leaf, "leaf = %q", leaf); err != nil {
+        return "", err
+    }
+    for i, node := range proof {
+        if err := checkHash(node.Hash, "proof[%d].hash = %q", i, node.Hash); err != nil {
+            return "", err
+        }
+        if node.Position != "left" && node.Position != "right" {
+            return "", fmt.Errorf("%w: proof[%d].position must be 'left' or 'right', got %q", ErrInvalidProof, i, node.Position)
+        }
+    }
+    return walkProof(leaf, proof, nil)
+}
+
+// walkProof hashes leaf up the proof path. visit, if set, sees each level's
+// running hash before it is combined and may abort the walk.
+func walkProof(leaf string, proof []ProofNode, visit func(level int, currentHash string, node ProofNode) error) (string, error) {
+    currentHash := leaf
+    for level, node := range proof {
+        if visit != nil {
+            if err := visit(level, currentHash, node); err != nil {
+                return "", err
             }
         }
         var left, right string
@@ -186,19 +271,28 @@ func VerifyProof(leaf string, index int, totalLeaves int, proof []ProofNode, exp
         } else {
             left, right = node.Hash, currentHash
         }
-        parent, err := hashPair(left, right)
+        parent, err := HashPair(left, right)
         if err != nil {
-            return false, err
+            return "", err
         }
         currentHash = parent
-        curIndex = curIndex / 2
-        curN = (curN + 1) / 2
     }
-    return currentHash == expectedRoot, nil
+    return currentHash, nil
 }
 
-// hashPair combines two hex-encoded hashes into a parent hash.
-func hashPair(leftHex, rightHex string) (string, error) {
+// HashPair combines two hex-encoded hashes into their parent hash:
+// SHA-256 over the 32 raw bytes of left followed by the 32 raw bytes of right.
+// Both inputs must be 64-character lowercase hex; anything else yields
+// ErrInvalidLeafFormat (or ErrNonCanonicalHash for uppercase hex).
+// Under SetHashAlg("sha512") the same holds with SHA-512 and 128-character hex.
+// Under SetPairMode(PairSorted) the smaller of the two is hashed first.
+func HashPair(leftHex, rightHex string) (string, error) {
+    if err := checkHash(leftHex, "left = %q", leftHex); err != nil {
+        return "", err
+    }
+    if err := checkHash(rightHex, "right = %q", rightHex); err != nil {
+        return "", err
+    }
     leftBytes, err := hex.DecodeString(leftHex)
     if err != nil {
         return "", fmt.Errorf("failed to decode left hash: %w", err)
@@ -207,8 +301,8 @@ func hashPair(leftHex, rightHex string) (string, error) {
     if err != nil {
         return "", fmt.Errorf("failed to decode right hash: %w", err)
     }
+    leftBytes, rightBytes = orderPair(leftBytes, rightBytes)
     combined := append(leftBytes, rightBytes...)
-    sum := sha256.Sum256(combined)
-    return hex.EncodeToString(sum[:]), nil
+    return hex.EncodeToString(activeScheme.Load().sum(combined)), nil
 }
 
@@ -3,6 +3,8 @@ package merkle
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -18,6 +20,42 @@ func makeLeaves(vals []string) []string {
 	return out
 }
 
+func TestHashPair_ReferenceVector(t *testing.T) {
+	// SHA-256("0") and SHA-256("1"); the parent is the 2-leaf root in testdata/vectors.json
+	left := "5feceb66ffc86f38d952786c6d696c79c2dbc239dd4e91b46729d73a27fb57e9"
+	right := "6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"
+
+	got, err := HashPair(left, right)
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	if want := "b9b10a1bc77d2a241d120324db7f3b81b2edb67eb8e9cf02af9c95d30329aef5"; got != want {
+		t.Errorf("HashPair = %s, want %s", got, want)
+	}
+
+	// Byte order matters: swapping the children gives a different parent
+	swapped, err := HashPair(right, left)
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	if want := "ee90f071cfb31af4d9230c8b9d11d0279e1e4f92992a860882aa338b3b60cef9"; swapped != want {
+		t.Errorf("HashPair(right, left) = %s, want %s", swapped, want)
+	}
+}
+
+func TestHashPair_InvalidInput(t *testing.T) {
+	valid := makeLeaves([]string{"a"})[0]
+	for _, tt := range []struct{ left, right string }{
+		{"abc", valid},
+		{valid, strings.Repeat("z", 64)},
+		{strings.ToUpper(valid), valid},
+	} {
+		if _, err := HashPair(tt.left, tt.right); !errors.Is(err, ErrInvalidLeafFormat) {
+			t.Errorf("HashPair(%q, %q): expected ErrInvalidLeafFormat, got %v", tt.left, tt.right, err)
+		}
+	}
+}
+
 func TestBuildRoot_EmptyLeaves(t *testing.T) {
 	_, err := BuildRoot(nil)
 	if err == nil {
@@ -256,3 +294,147 @@ func TestVerifyProof_OddDuplicationRuleBound(t *testing.T) {
 
 
 
+
+func TestRootFromProof_MatchesBuildRoot(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		vals := make([]string, n)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("leaf-%d", i)
+		}
+		leaves := makeLeaves(vals)
+
+		want, err := BuildRoot(leaves)
+		if err != nil {
+			t.Fatalf("BuildRoot error: %v", err)
+		}
+
+		for idx := range leaves {
+			proof, _, err := BuildProof(leaves, idx)
+			if err != nil {
+				t.Fatalf("BuildProof error: %v", err)
+			}
+			got, err := RootFromProof(leaves[idx], proof)
+			if err != nil {
+				t.Fatalf("RootFromProof error (n=%d idx=%d): %v", n, idx, err)
+			}
+			if got != want {
+				t.Fatalf("n=%d idx=%d: RootFromProof %s != BuildRoot %s", n, idx, got, want)
+			}
+		}
+	}
+}
+
+func TestRootFromProof_InvalidInputs(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B"})
+	proof, _, err := BuildProof(leaves, 0)
+	if err != nil {
+		t.Fatalf("BuildProof error: %v", err)
+	}
+
+	if _, err := RootFromProof("zz", proof); !errors.Is(err, ErrInvalidLeafFormat) {
+		t.Errorf("expected ErrInvalidLeafFormat for bad leaf, got %v", err)
+	}
+
+	badPos := []ProofNode{{Hash: proof[0].Hash, Position: "up"}}
+	if _, err := RootFromProof(leaves[0], badPos); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected ErrInvalidProof for bad position, got %v", err)
+	}
+}
+
+func TestBuildRootWithStats_Depth(t *testing.T) {
+	tests := []struct {
+		leaves int
+		depth  int
+	}{
+		{1, 0}, {2, 1}, {3, 2}, {4, 2}, {8, 3}, {9, 4},
+	}
+	for _, tt := range tests {
+		vals := make([]string, tt.leaves)
+		for i := range vals {
+			vals[i] = fmt.Sprintf("leaf-%d", i)
+		}
+		leaves := makeLeaves(vals)
+
+		root, depth, count, err := BuildRootWithStats(leaves)
+		if err != nil {
+			t.Fatalf("%d leaves: BuildRootWithStats error: %v", tt.leaves, err)
+		}
+		if depth != tt.depth || count != tt.leaves {
+			t.Errorf("%d leaves: depth/count = %d/%d, want %d/%d", tt.leaves, depth, count, tt.depth, tt.leaves)
+		}
+		if want, _ := BuildRoot(leaves); root != want {
+			t.Errorf("%d leaves: root %s, want %s", tt.leaves, root, want)
+		}
+		if proof, _, _ := BuildProof(leaves, tt.leaves-1); len(proof) != depth {
+			t.Errorf("%d leaves: proof length %d, depth %d", tt.leaves, len(proof), depth)
+		}
+	}
+
+	if _, _, _, err := BuildRootWithStats(nil); !errors.Is(err, ErrEmptyLeaves) {
+		t.Errorf("expected ErrEmptyLeaves, got %v", err)
+	}
+}
+
+func TestCheckHash_NonCanonicalVsMalformed(t *testing.T) {
+	valid := makeLeaves([]string{"A"})[0]
+
+	tests := []struct {
+		name         string
+		leaf         string
+		nonCanonical bool
+	}{
+		{name: "uppercase", leaf: strings.ToUpper(valid), nonCanonical: true},
+		{name: "mixed case", leaf: valid[:63] + "A", nonCanonical: true},
+		{name: "wrong length", leaf: valid[:62]},
+		{name: "uppercase wrong length", leaf: strings.ToUpper(valid[:62])},
+		{name: "non-hex", leaf: "zz" + valid[2:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BuildRoot([]string{valid, tt.leaf})
+			if !errors.Is(err, ErrInvalidLeafFormat) {
+				t.Fatalf("expected ErrInvalidLeafFormat, got %v", err)
+			}
+			if errors.Is(err, ErrNonCanonicalHash) != tt.nonCanonical {
+				t.Fatalf("errors.Is(ErrNonCanonicalHash) = %v, want %v (err: %v)", !tt.nonCanonical, tt.nonCanonical, err)
+			}
+
+			_, err = VerifyProof(tt.leaf, 0, 1, nil, valid)
+			if errors.Is(err, ErrNonCanonicalHash) != tt.nonCanonical {
+				t.Fatalf("VerifyProof: errors.Is(ErrNonCanonicalHash) = %v, want %v (err: %v)", !tt.nonCanonical, tt.nonCanonical, err)
+			}
+		})
+	}
+}
+
+func TestVerifyProofDetailed_ReportsComputedRoot(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C"})
+	proof, root, err := BuildProof(leaves, 1)
+	if err != nil {
+		t.Fatalf("BuildProof error: %v", err)
+	}
+
+	ok, computed, err := VerifyProofDetailed(leaves[1], 1, 3, proof, root)
+	if err != nil || !ok {
+		t.Fatalf("expected valid proof, ok=%v err=%v", ok, err)
+	}
+	if computed != root {
+		t.Errorf("computed root %s, want %s", computed, root)
+	}
+
+	// Against the wrong root the reconstructed root is still the real one
+	wrong := makeLeaves([]string{"other"})[0]
+	ok, computed, err = VerifyProofDetailed(leaves[1], 1, 3, proof, wrong)
+	if err != nil || ok {
+		t.Fatalf("expected mismatch without error, ok=%v err=%v", ok, err)
+	}
+	if computed != root {
+		t.Errorf("computed root %s, want %s", computed, root)
+	}
+
+	// Malformed input is rejected before hashing
+	_, computed, err = VerifyProofDetailed("zzz", 1, 3, proof, root)
+	if err == nil || computed != "" {
+		t.Errorf("expected error and no computed root, got %q, %v", computed, err)
+	}
+}
@@ -0,0 +1,68 @@
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrUnsupportedPairMode is returned by SetPairMode for an unknown PairMode.
+var ErrUnsupportedPairMode = errors.New("unsupported pair mode")
+
+// PairMode selects how HashPair orders the two children before hashing.
+type PairMode int32
+
+const (
+	// PairLeftRight hashes left||right in tree order (the canon default)
+	PairLeftRight PairMode = iota
+
+	// PairSorted hashes the lexicographically smaller child first, the
+	// sorted-pair convention of OpenZeppelin's MerkleProof and similar systems
+	PairSorted
+)
+
+// String returns the mode name used in errors and logs.
+func (m PairMode) String() string {
+	switch m {
+	case PairLeftRight:
+		return "left_right"
+	case PairSorted:
+		return "sorted"
+	}
+	return fmt.Sprintf("PairMode(%d)", int32(m))
+}
+
+// activePairMode is the mode every tree function uses (PairLeftRight unless SetPairMode changed it)
+var activePairMode atomic.Int32
+
+// SetPairMode selects the parent hash ordering for every subsequent call, for
+// interop with sorted-pair ecosystems. The ledger must keep PairLeftRight: its
+// roots commit to insertion order.
+//
+// PairSorted loses index information. Swapping two siblings leaves their parent
+// unchanged, so a root no longer commits to leaf positions and a proof's
+// positions and index are not bound by the hashes: VerifyProof still checks
+// them structurally, but any sibling order yields the same root. Roots and
+// proofs from one mode never verify in the other (except where every pair
+// happens to be in sorted order). The setting is process-wide, like SetHashAlg.
+func SetPairMode(mode PairMode) error {
+	if mode != PairLeftRight && mode != PairSorted {
+		return fmt.Errorf("%w: %v", ErrUnsupportedPairMode, mode)
+	}
+	activePairMode.Store(int32(mode))
+	return nil
+}
+
+// ActivePairMode returns the parent hash ordering in use.
+func ActivePairMode() PairMode {
+	return PairMode(activePairMode.Load())
+}
+
+// orderPair returns the children in the order the active mode hashes them.
+func orderPair(left, right []byte) ([]byte, []byte) {
+	if ActivePairMode() == PairSorted && bytes.Compare(left, right) > 0 {
+		return right, left
+	}
+	return left, right
+}
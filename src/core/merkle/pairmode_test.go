@@ -0,0 +1,98 @@
+package merkle
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+// usePairSorted switches to sorted-pair hashing for the duration of the test
+func usePairSorted(t *testing.T) {
+	t.Helper()
+	if err := SetPairMode(PairSorted); err != nil {
+		t.Fatalf("SetPairMode failed: %v", err)
+	}
+	t.Cleanup(func() { SetPairMode(PairLeftRight) })
+}
+
+// descendingLeaves returns leaves in reverse lexicographic order, so every
+// sibling pair is out of sorted order and the two modes must disagree.
+func descendingLeaves() []string {
+	leaves := makeLeaves([]string{"a", "b", "c", "d"})
+	sort.Sort(sort.Reverse(sort.StringSlice(leaves)))
+	return leaves
+}
+
+func TestPairSorted_OrderIndependentRoots(t *testing.T) {
+	usePairSorted(t)
+	l := makeLeaves([]string{"a", "b", "c", "d"})
+
+	ab, err := HashPair(l[0], l[1])
+	if err != nil {
+		t.Fatalf("HashPair failed: %v", err)
+	}
+	if ba, _ := HashPair(l[1], l[0]); ba != ab {
+		t.Fatalf("HashPair not symmetric: %s vs %s", ab, ba)
+	}
+
+	want, err := BuildRoot(l)
+	if err != nil {
+		t.Fatalf("BuildRoot failed: %v", err)
+	}
+	for _, order := range [][]string{
+		{l[1], l[0], l[3], l[2]},
+		{l[2], l[3], l[0], l[1]},
+		{l[3], l[2], l[1], l[0]},
+	} {
+		if got, _ := BuildRoot(order); got != want {
+			t.Errorf("root over %v = %s, want %s", order, got, want)
+		}
+	}
+
+	// Proofs still round-trip in sorted mode
+	proof, root, err := BuildProof(l, 2)
+	if err != nil {
+		t.Fatalf("BuildProof failed: %v", err)
+	}
+	if ok, err := VerifyProof(l[2], 2, len(l), proof, root); !ok || err != nil {
+		t.Fatalf("sorted round trip: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestPairMode_CrossModeVerificationFails(t *testing.T) {
+	leaves := descendingLeaves()
+
+	lrProof, lrRoot, err := BuildProof(leaves, 0)
+	if err != nil {
+		t.Fatalf("BuildProof failed: %v", err)
+	}
+
+	usePairSorted(t)
+	sortedProof, sortedRoot, err := BuildProof(leaves, 0)
+	if err != nil {
+		t.Fatalf("BuildProof failed: %v", err)
+	}
+	if sortedRoot == lrRoot {
+		t.Fatalf("modes produced the same root %s", lrRoot)
+	}
+	if ok, err := VerifyProof(leaves[0], 0, len(leaves), lrProof, lrRoot); ok || err != nil {
+		t.Errorf("left_right proof under sorted mode: ok=%v err=%v, want false, nil", ok, err)
+	}
+
+	SetPairMode(PairLeftRight)
+	if ok, err := VerifyProof(leaves[0], 0, len(leaves), sortedProof, sortedRoot); ok || err != nil {
+		t.Errorf("sorted proof under left_right mode: ok=%v err=%v, want false, nil", ok, err)
+	}
+	if ok, err := VerifyProof(leaves[0], 0, len(leaves), lrProof, lrRoot); !ok || err != nil {
+		t.Errorf("canon default no longer verifies: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestSetPairMode_Unsupported(t *testing.T) {
+	if err := SetPairMode(PairMode(7)); !errors.Is(err, ErrUnsupportedPairMode) {
+		t.Fatalf("expected ErrUnsupportedPairMode, got %v", err)
+	}
+	if ActivePairMode() != PairLeftRight {
+		t.Fatalf("rejected mode changed the active mode to %v", ActivePairMode())
+	}
+}
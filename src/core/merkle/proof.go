@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+// ErrUnsupportedProofVersion is returned when a proof envelope targets a canon version this binary does not implement.
+var ErrUnsupportedProofVersion = errors.New("unsupported proof canon version")
+
+// Proof is the self-describing envelope exchanged between systems: the leaf, its
+// position in the tree, the sibling path and the root it commits to, tagged with
+// the canon version whose hashing rules produced it.
+type Proof struct {
+	Version     string      `json:"canon"`
+	Leaf        string      `json:"leaf"`
+	Index       int         `json:"index"`
+	TotalLeaves int         `json:"total_leaves"`
+	Nodes       []ProofNode `json:"nodes"`
+	Root        string      `json:"root"`
+}
+
+// NewProof builds the envelope for leaves[index], stamped with the current canon version.
+func NewProof(leaves []string, index int) (Proof, error) {
+	nodes, root, err := BuildProof(leaves, index)
+	if err != nil {
+		return Proof{}, err
+	}
+	return Proof{
+		Version:     config.CanonVersion,
+		Leaf:        leaves[index],
+		Index:       index,
+		TotalLeaves: len(leaves),
+		Nodes:       nodes,
+		Root:        root,
+	}, nil
+}
+
+// Verify checks the envelope's path against its own Root.
+func (p Proof) Verify() (bool, error) {
+	return VerifyProof(p.Leaf, p.Index, p.TotalLeaves, p.Nodes, p.Root)
+}
+
+// MarshalProof serializes p, always embedding the current canon version.
+func MarshalProof(p Proof) ([]byte, error) {
+	p.Version = config.CanonVersion
+	if p.Nodes == nil {
+		p.Nodes = []ProofNode{}
+	}
+	return json.Marshal(p)
+}
+
+// UnmarshalProof parses a proof envelope and rejects any canon version other
+// than the one this binary implements. A missing tag is treated as unknown.
+func UnmarshalProof(data []byte) (Proof, error) {
+	var p Proof
+	if err := json.Unmarshal(data, &p); err != nil {
+		return Proof{}, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	if p.Version != config.CanonVersion {
+		return Proof{}, fmt.Errorf("%w: got %q, expected %q", ErrUnsupportedProofVersion, p.Version, config.CanonVersion)
+	}
+	return p, nil
+}
@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMarshalProof_RoundTrip(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+
+	proof, err := NewProof(leaves, 4)
+	if err != nil {
+		t.Fatalf("NewProof error: %v", err)
+	}
+
+	data, err := MarshalProof(proof)
+	if err != nil {
+		t.Fatalf("MarshalProof error: %v", err)
+	}
+	if !strings.Contains(string(data), `"canon":"v1.0"`) {
+		t.Fatalf("envelope missing canon tag: %s", data)
+	}
+
+	parsed, err := UnmarshalProof(data)
+	if err != nil {
+		t.Fatalf("UnmarshalProof error: %v", err)
+	}
+	if parsed.Leaf != proof.Leaf || parsed.Index != 4 || parsed.TotalLeaves != 5 || parsed.Root != proof.Root {
+		t.Fatalf("round-trip mismatch: %+v vs %+v", parsed, proof)
+	}
+	if len(parsed.Nodes) != len(proof.Nodes) {
+		t.Fatalf("nodes length = %d, want %d", len(parsed.Nodes), len(proof.Nodes))
+	}
+
+	ok, err := parsed.Verify()
+	if err != nil || !ok {
+		t.Fatalf("round-tripped proof failed verification: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestUnmarshalProof_UnknownVersion(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B"})
+	proof, err := NewProof(leaves, 0)
+	if err != nil {
+		t.Fatalf("NewProof error: %v", err)
+	}
+	data, err := MarshalProof(proof)
+	if err != nil {
+		t.Fatalf("MarshalProof error: %v", err)
+	}
+
+	for _, tampered := range []string{
+		strings.Replace(string(data), `"canon":"v1.0"`, `"canon":"v2.0"`, 1),
+		strings.Replace(string(data), `"canon":"v1.0",`, ``, 1),
+	} {
+		if _, err := UnmarshalProof([]byte(tampered)); !errors.Is(err, ErrUnsupportedProofVersion) {
+			t.Errorf("expected ErrUnsupportedProofVersion for %s, got %v", tampered, err)
+		}
+	}
+}
+
+func TestUnmarshalProof_Malformed(t *testing.T) {
+	if _, err := UnmarshalProof([]byte(`{not json`)); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof, got %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRange is returned when a leaf range is empty, reversed or out of bounds.
+var ErrInvalidRange = errors.New("invalid leaf range")
+
+// RangeProof proves that the consecutive leaves start..end (inclusive) belong to
+// a root. Nodes holds only the frontier siblings bounding the range, level by
+// level from the leaves up: at each level at most one "left" node (before the
+// range) and one "right" node (after it). Nodes inside the range are recomputed
+// from the leaves themselves, so the proof is far smaller than one proof per leaf.
+type RangeProof struct {
+	Nodes []ProofNode `json:"nodes"`
+}
+
+// BuildRangeProof generates a RangeProof for leaves[start..end] (inclusive).
+func BuildRangeProof(leaves []string, start, end int) (RangeProof, error) {
+	if len(leaves) == 0 {
+		return RangeProof{}, ErrEmptyLeaves
+	}
+	if err := checkRange(start, end, len(leaves)); err != nil {
+		return RangeProof{}, err
+	}
+	for i, leaf := range leaves {
+		if err := checkHash(leaf, "leaf[%d] = %q", i, leaf); err != nil {
+			return RangeProof{}, err
+		}
+	}
+
+	proof := RangeProof{Nodes: []ProofNode{}}
+	level := make([]string, len(leaves))
+	copy(level, leaves)
+	lo, hi := start, end
+
+	for len(level) > 1 {
+		if lo%2 == 1 {
+			proof.Nodes = append(proof.Nodes, ProofNode{Hash: level[lo-1], Position: "left"})
+		}
+		// A last node without a partner is paired with itself: no sibling to ship
+		if hi%2 == 0 && hi+1 < len(level) {
+			proof.Nodes = append(proof.Nodes, ProofNode{Hash: level[hi+1], Position: "right"})
+		}
+
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			right := level[i]
+			if i+1 < len(level) {
+				right = level[i+1]
+			}
+			parent, err := HashPair(level[i], right)
+			if err != nil {
+				return RangeProof{}, err
+			}
+			next = append(next, parent)
+		}
+		level = next
+		lo, hi = lo/2, hi/2
+	}
+	return proof, nil
+}
+
+// VerifyRangeProof verifies that rangeLeaves are the leaves start..end (inclusive)
+// of a tree of totalLeaves leaves with the given root. The proof must contain
+// exactly the frontier nodes BuildRangeProof emits for that range and tree size.
+func VerifyRangeProof(rangeLeaves []string, start, end, totalLeaves int, proof RangeProof, root string) (bool, error) {
+	if totalLeaves <= 0 {
+		return false, fmt.Errorf("%w: totalLeaves must be positive", ErrInvalidTotalLeaves)
+	}
+	if err := checkRange(start, end, totalLeaves); err != nil {
+		return false, err
+	}
+	if len(rangeLeaves) != end-start+1 {
+		return false, fmt.Errorf("%w: got %d leaves for range %d..%d", ErrInvalidRange, len(rangeLeaves), start, end)
+	}
+	if err := checkHash(root, "root = %q", root); err != nil {
+		return false, err
+	}
+	for i, leaf := range rangeLeaves {
+		if err := checkHash(leaf, "rangeLeaves[%d] = %q", i, leaf); err != nil {
+			return false, err
+		}
+	}
+	for i, node := range proof.Nodes {
+		if err := checkHash(node.Hash, "proof[%d].hash = %q", i, node.Hash); err != nil {
+			return false, err
+		}
+	}
+
+	// known holds the nodes lo..hi of the current level
+	known := make([]string, len(rangeLeaves))
+	copy(known, rangeLeaves)
+	lo, hi, n := start, end, totalLeaves
+	next := 0
+
+	take := func(position string) (string, error) {
+		if next >= len(proof.Nodes) {
+			return "", fmt.Errorf("%w: proof too short, missing %s node", ErrInvalidProof, position)
+		}
+		node := proof.Nodes[next]
+		if node.Position != position {
+			return "", fmt.Errorf("%w: proof[%d].position %q != expected %q", ErrInvalidProof, next, node.Position, position)
+		}
+		next++
+		return node.Hash, nil
+	}
+
+	for n > 1 {
+		if lo%2 == 1 {
+			left, err := take("left")
+			if err != nil {
+				return false, err
+			}
+			known = append([]string{left}, known...)
+			lo--
+		}
+		if hi%2 == 0 {
+			if hi+1 < n {
+				right, err := take("right")
+				if err != nil {
+					return false, err
+				}
+				known = append(known, right)
+			} else {
+				known = append(known, known[len(known)-1])
+			}
+			hi++
+		}
+
+		parents := make([]string, 0, len(known)/2)
+		for i := 0; i < len(known); i += 2 {
+			parent, err := HashPair(known[i], known[i+1])
+			if err != nil {
+				return false, err
+			}
+			parents = append(parents, parent)
+		}
+		known = parents
+		lo, hi, n = lo/2, hi/2, (n+1)/2
+	}
+
+	if next != len(proof.Nodes) {
+		return false, fmt.Errorf("%w: %d unused proof nodes", ErrInvalidProof, len(proof.Nodes)-next)
+	}
+	return known[0] == root, nil
+}
+
+// checkRange validates 0 <= start <= end < total
+func checkRange(start, end, total int) error {
+	if start < 0 || end < start || end >= total {
+		return fmt.Errorf("%w: range %d..%d, total leaves %d", ErrInvalidRange, start, end, total)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// rangeLeaves returns n distinct leaves
+func rangeLeaves(n int) []string {
+	vals := make([]string, n)
+	for i := range vals {
+		vals[i] = fmt.Sprintf("leaf-%d", i)
+	}
+	return makeLeaves(vals)
+}
+
+func TestRangeProof_RoundTrip(t *testing.T) {
+	leaves := rangeLeaves(11)
+	root, err := BuildRoot(leaves)
+	if err != nil {
+		t.Fatalf("BuildRoot error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end int
+		maxNodes   int
+	}{
+		{name: "mid-tree", start: 3, end: 6, maxNodes: 4},
+		{name: "right edge with odd duplication", start: 8, end: 10, maxNodes: 2},
+		{name: "full tree", start: 0, end: 10, maxNodes: 0},
+		{name: "single leaf", start: 5, end: 5, maxNodes: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof, err := BuildRangeProof(leaves, tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("BuildRangeProof error: %v", err)
+			}
+			if len(proof.Nodes) > tt.maxNodes {
+				t.Errorf("proof has %d nodes, want at most %d", len(proof.Nodes), tt.maxNodes)
+			}
+
+			ok, err := VerifyRangeProof(leaves[tt.start:tt.end+1], tt.start, tt.end, len(leaves), proof, root)
+			if err != nil || !ok {
+				t.Fatalf("VerifyRangeProof = %v, %v; want true", ok, err)
+			}
+
+			// A tampered leaf inside the range must not verify
+			tampered := append([]string(nil), leaves[tt.start:tt.end+1]...)
+			tampered[len(tampered)-1] = makeLeaves([]string{"evil"})[0]
+			if ok, _ := VerifyRangeProof(tampered, tt.start, tt.end, len(leaves), proof, root); ok {
+				t.Fatalf("tampered range verified")
+			}
+		})
+	}
+}
+
+func TestRangeProof_SmallerThanIndividualProofs(t *testing.T) {
+	leaves := rangeLeaves(256)
+	proof, err := BuildRangeProof(leaves, 100, 199)
+	if err != nil {
+		t.Fatalf("BuildRangeProof error: %v", err)
+	}
+
+	individual := 0
+	for i := 100; i <= 199; i++ {
+		p, _, err := BuildProof(leaves, i)
+		if err != nil {
+			t.Fatalf("BuildProof error: %v", err)
+		}
+		individual += len(p)
+	}
+	if len(proof.Nodes) >= individual/10 {
+		t.Errorf("range proof has %d nodes, individual proofs %d", len(proof.Nodes), individual)
+	}
+}
+
+func TestRangeProof_RejectsWrongPosition(t *testing.T) {
+	leaves := rangeLeaves(11)
+	root, _ := BuildRoot(leaves)
+	proof, err := BuildRangeProof(leaves, 3, 6)
+	if err != nil {
+		t.Fatalf("BuildRangeProof error: %v", err)
+	}
+
+	// Shifting the claimed range reuses the same nodes on the wrong sides
+	if ok, err := VerifyRangeProof(leaves[3:7], 4, 7, len(leaves), proof, root); ok || err == nil {
+		t.Fatalf("shifted range verified: ok=%v err=%v", ok, err)
+	}
+
+	proof.Nodes = append(proof.Nodes, proof.Nodes[0])
+	if _, err := VerifyRangeProof(leaves[3:7], 3, 6, len(leaves), proof, root); !errors.Is(err, ErrInvalidProof) {
+		t.Fatalf("expected ErrInvalidProof for extra nodes, got %v", err)
+	}
+}
+
+func TestRangeProof_InvalidRange(t *testing.T) {
+	leaves := rangeLeaves(5)
+	root, _ := BuildRoot(leaves)
+
+	for _, r := range [][2]int{{-1, 2}, {3, 2}, {0, 5}} {
+		if _, err := BuildRangeProof(leaves, r[0], r[1]); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("BuildRangeProof(%d, %d): expected ErrInvalidRange, got %v", r[0], r[1], err)
+		}
+		if _, err := VerifyRangeProof(leaves[:1], r[0], r[1], len(leaves), RangeProof{}, root); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("VerifyRangeProof(%d, %d): expected ErrInvalidRange, got %v", r[0], r[1], err)
+		}
+	}
+
+	if _, err := VerifyRangeProof(leaves[:2], 0, 2, len(leaves), RangeProof{}, root); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("expected ErrInvalidRange for a leaf count mismatch, got %v", err)
+	}
+	if _, err := BuildRangeProof(nil, 0, 0); !errors.Is(err, ErrEmptyLeaves) {
+		t.Errorf("expected ErrEmptyLeaves, got %v", err)
+	}
+}
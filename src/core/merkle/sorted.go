@@ -0,0 +1,55 @@
+package merkle
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Sorted-leaf trees, for interop partners that commit to a set of hashes rather
+// than a sequence.
+//
+// BuildRootSorted sorts a copy of the leaves lexicographically and then applies
+// exactly the BuildRoot rules (pairing, odd duplication, single-leaf root).
+// For any leaf set that is not already in sorted order the two functions produce
+// DIFFERENT roots: the canon ledger root commits to insertion order, the sorted
+// root does not. Never mix them in one verification path.
+
+// BuildRootSorted returns the root of the tree built over a sorted copy of leaves.
+// The input slice is not modified. The root is independent of input order.
+func BuildRootSorted(leaves []string) (string, error) {
+	return BuildRoot(sortedCopy(leaves))
+}
+
+// BuildProofSorted generates a proof for leaf in the sorted-leaf tree.
+//
+// Returns the proof, the leaf's index in sorted order (the index VerifyProofSorted
+// expects) and the sorted root. If leaf occurs more than once, the first sorted
+// occurrence is used.
+func BuildProofSorted(leaves []string, leaf string) ([]ProofNode, int, string, error) {
+	sorted := sortedCopy(leaves)
+
+	index := sort.SearchStrings(sorted, leaf)
+	if index >= len(sorted) || sorted[index] != leaf {
+		return nil, 0, "", fmt.Errorf("%w: leaf %q not in leaf set", ErrInvalidIndex, leaf)
+	}
+
+	proof, root, err := BuildProof(sorted, index)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	return proof, index, root, nil
+}
+
+// VerifyProofSorted verifies a proof produced by BuildProofSorted. sortedIndex is
+// the leaf's position after sorting, not its position in the caller's original
+// slice; the path rules are otherwise identical to VerifyProof.
+func VerifyProofSorted(leaf string, sortedIndex int, totalLeaves int, proof []ProofNode, expectedRoot string) (bool, error) {
+	return VerifyProof(leaf, sortedIndex, totalLeaves, proof, expectedRoot)
+}
+
+func sortedCopy(leaves []string) []string {
+	sorted := make([]string, len(leaves))
+	copy(sorted, leaves)
+	sort.Strings(sorted)
+	return sorted
+}
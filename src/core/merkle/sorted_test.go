@@ -0,0 +1,70 @@
+package merkle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBuildRootSorted_OrderIndependent(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E"})
+	reversed := make([]string, len(leaves))
+	for i, l := range leaves {
+		reversed[len(leaves)-1-i] = l
+	}
+
+	sortedA, err := BuildRootSorted(leaves)
+	if err != nil {
+		t.Fatalf("BuildRootSorted error: %v", err)
+	}
+	sortedB, err := BuildRootSorted(reversed)
+	if err != nil {
+		t.Fatalf("BuildRootSorted error: %v", err)
+	}
+	if sortedA != sortedB {
+		t.Fatalf("sorted roots differ for permuted input: %s vs %s", sortedA, sortedB)
+	}
+
+	canonA, _ := BuildRoot(leaves)
+	canonB, _ := BuildRoot(reversed)
+	if canonA == canonB {
+		t.Fatalf("canon roots should depend on leaf order")
+	}
+	if canonOfSorted, _ := BuildRoot(sortedCopy(leaves)); canonOfSorted != sortedA {
+		t.Fatalf("sorted root should equal the canon root of the pre-sorted leaves")
+	}
+
+	// Input must not be reordered in place
+	if reversed[0] != leaves[len(leaves)-1] {
+		t.Fatalf("BuildRootSorted modified its input")
+	}
+}
+
+func TestBuildProofSorted_Verifies(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C", "D", "E", "F", "G"})
+	root, err := BuildRootSorted(leaves)
+	if err != nil {
+		t.Fatalf("BuildRootSorted error: %v", err)
+	}
+
+	for _, leaf := range leaves {
+		proof, idx, gotRoot, err := BuildProofSorted(leaves, leaf)
+		if err != nil {
+			t.Fatalf("BuildProofSorted error: %v", err)
+		}
+		if gotRoot != root {
+			t.Fatalf("proof root %s != sorted root %s", gotRoot, root)
+		}
+		ok, err := VerifyProofSorted(leaf, idx, len(leaves), proof, root)
+		if err != nil || !ok {
+			t.Fatalf("VerifyProofSorted failed for %s: ok=%v err=%v", leaf, ok, err)
+		}
+	}
+}
+
+func TestBuildProofSorted_UnknownLeaf(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B"})
+	missing := makeLeaves([]string{"Z"})[0]
+	if _, _, _, err := BuildProofSorted(leaves, missing); !errors.Is(err, ErrInvalidIndex) {
+		t.Fatalf("expected ErrInvalidIndex, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package merkle
+
+import (
+	"fmt"
+)
+
+// subtree is a perfect subtree of 2^height leaves pending in a Tree frontier.
+type subtree struct {
+	hash   string
+	height int
+}
+
+// BuildRootStreaming computes the same root as BuildRoot, but consumes leaves
+// one at a time from next and keeps only O(log n) pending subtree roots in memory
+// (see Tree).
+//
+// next returns (leaf, true, nil) for each leaf and ("", false, nil) once exhausted;
+// a non-nil error aborts the build and is returned wrapped.
+func BuildRootStreaming(next func() (string, bool, error)) (string, int, error) {
+	var tree Tree
+
+	for {
+		leaf, ok, err := next()
+		if err != nil {
+			return "", tree.Len(), fmt.Errorf("leaf source failed after %d leaves: %w", tree.Len(), err)
+		}
+		if !ok {
+			break
+		}
+		if err := tree.AppendLeaf(leaf); err != nil {
+			return "", tree.Len(), err
+		}
+	}
+
+	root, err := tree.Root()
+	if err != nil {
+		return "", 0, err
+	}
+	return root, tree.Len(), nil
+}
+
+// SliceSource adapts a slice of leaves to the generator expected by BuildRootStreaming.
+func SliceSource(leaves []string) func() (string, bool, error) {
+	i := 0
+	return func() (string, bool, error) {
+		if i >= len(leaves) {
+			return "", false, nil
+		}
+		i++
+		return leaves[i-1], true, nil
+	}
+}
@@ -0,0 +1,84 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBuildRootStreaming_MatchesBuildRoot(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 15, 16, 17, 1000} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			vals := make([]string, n)
+			for i := range vals {
+				vals[i] = fmt.Sprintf("leaf-%d", i)
+			}
+			leaves := makeLeaves(vals)
+
+			want, err := BuildRoot(leaves)
+			if err != nil {
+				t.Fatalf("BuildRoot error: %v", err)
+			}
+
+			got, count, err := BuildRootStreaming(SliceSource(leaves))
+			if err != nil {
+				t.Fatalf("BuildRootStreaming error: %v", err)
+			}
+			if count != n {
+				t.Fatalf("count = %d, want %d", count, n)
+			}
+			if got != want {
+				t.Fatalf("streaming root %s != batch root %s", got, want)
+			}
+		})
+	}
+}
+
+func TestBuildRootStreaming_Empty(t *testing.T) {
+	_, _, err := BuildRootStreaming(SliceSource(nil))
+	if !errors.Is(err, ErrEmptyLeaves) {
+		t.Fatalf("expected ErrEmptyLeaves, got %v", err)
+	}
+}
+
+func TestBuildRootStreaming_InvalidLeaf(t *testing.T) {
+	leaves := append(makeLeaves([]string{"A"}), "zzz")
+	_, _, err := BuildRootStreaming(SliceSource(leaves))
+	if !errors.Is(err, ErrInvalidLeafFormat) {
+		t.Fatalf("expected ErrInvalidLeafFormat, got %v", err)
+	}
+}
+
+func TestBuildRootStreaming_SourceError(t *testing.T) {
+	boom := errors.New("boom")
+	calls := 0
+	_, count, err := BuildRootStreaming(func() (string, bool, error) {
+		calls++
+		if calls == 3 {
+			return "", false, boom
+		}
+		return makeLeaves([]string{"A"})[0], true, nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected wrapped source error, got %v", err)
+	}
+	if count != 2 || !strings.Contains(err.Error(), "after 2 leaves") {
+		t.Fatalf("unexpected count=%d err=%v", count, err)
+	}
+}
+
+func BenchmarkBuildRootStreaming1000(b *testing.B) {
+	vals := make([]string, 1000)
+	for i := range vals {
+		vals[i] = fmt.Sprintf("leaf-%d", i)
+	}
+	leaves := makeLeaves(vals)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := BuildRootStreaming(SliceSource(leaves)); err != nil {
+			b.Fatalf("BuildRootStreaming failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package merkle
+
+// Tree is an append-only Merkle tree that keeps only its right frontier: the
+// roots of the perfect subtrees covering the leaves so far, at most one per
+// height. AppendLeaf costs O(log n) hashes and Root folds the frontier in
+// O(log n), giving the same root BuildRoot would over the accumulated leaves.
+//
+// The zero value is an empty tree ready to use. A Tree is not safe for
+// concurrent use.
+type Tree struct {
+	frontier []subtree // strictly decreasing heights, left to right
+	count    int
+}
+
+// AppendLeaf adds leaf (64 lowercase hex) as the next leaf of the tree.
+// A rejected leaf leaves the tree unchanged.
+func (t *Tree) AppendLeaf(leaf string) error {
+	if err := checkHash(leaf, "leaf[%d] = %q", t.count, leaf); err != nil {
+		return err
+	}
+
+	// Merge equal-height subtrees: the frontier mirrors the binary digits of count
+	node := subtree{hash: leaf, height: 0}
+	merged := len(t.frontier)
+	for merged > 0 && t.frontier[merged-1].height == node.height {
+		parent, err := HashPair(t.frontier[merged-1].hash, node.hash)
+		if err != nil {
+			return err
+		}
+		node = subtree{hash: parent, height: node.height + 1}
+		merged--
+	}
+	t.frontier = append(t.frontier[:merged], node)
+	t.count++
+	return nil
+}
+
+// Len returns the number of leaves appended so far.
+func (t *Tree) Len() int {
+	return t.count
+}
+
+// Root returns the current Merkle root, or ErrEmptyLeaves for an empty tree.
+//
+// The odd-duplication rule is applied to a copy of the frontier: the rightmost
+// partial subtree is paired with itself until it reaches the height of its left
+// neighbour, exactly as BuildRoot duplicates the last node of every odd level.
+func (t *Tree) Root() (string, error) {
+	if t.count == 0 {
+		return "", ErrEmptyLeaves
+	}
+
+	node := t.frontier[len(t.frontier)-1]
+	for i := len(t.frontier) - 2; i >= 0; i-- {
+		left := t.frontier[i]
+		for node.height < left.height {
+			dup, err := HashPair(node.hash, node.hash)
+			if err != nil {
+				return "", err
+			}
+			node = subtree{hash: dup, height: node.height + 1}
+		}
+		parent, err := HashPair(left.hash, node.hash)
+		if err != nil {
+			return "", err
+		}
+		node = subtree{hash: parent, height: left.height + 1}
+	}
+	return node.hash, nil
+}
@@ -0,0 +1,62 @@
+package merkle
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTree_AppendLeafMatchesBuildRoot(t *testing.T) {
+	vals := make([]string, 16)
+	for i := range vals {
+		vals[i] = fmt.Sprintf("leaf-%d", i)
+	}
+	leaves := makeLeaves(vals)
+
+	var tree Tree
+	for n := 1; n <= len(leaves); n++ {
+		if err := tree.AppendLeaf(leaves[n-1]); err != nil {
+			t.Fatalf("AppendLeaf(%d) error: %v", n-1, err)
+		}
+		got, err := tree.Root()
+		if err != nil {
+			t.Fatalf("Root after %d leaves: %v", n, err)
+		}
+		want, err := BuildRoot(leaves[:n])
+		if err != nil {
+			t.Fatalf("BuildRoot(%d) error: %v", n, err)
+		}
+		if got != want {
+			t.Fatalf("n=%d: running root %s, BuildRoot %s", n, got, want)
+		}
+		if tree.Len() != n {
+			t.Fatalf("Len = %d, want %d", tree.Len(), n)
+		}
+	}
+}
+
+func TestTree_Empty(t *testing.T) {
+	var tree Tree
+	if _, err := tree.Root(); !errors.Is(err, ErrEmptyLeaves) {
+		t.Fatalf("expected ErrEmptyLeaves, got %v", err)
+	}
+}
+
+func TestTree_RejectedLeafLeavesTreeUnchanged(t *testing.T) {
+	leaves := makeLeaves([]string{"A", "B", "C"})
+	var tree Tree
+	for _, leaf := range leaves {
+		if err := tree.AppendLeaf(leaf); err != nil {
+			t.Fatalf("AppendLeaf error: %v", err)
+		}
+	}
+	before, _ := tree.Root()
+
+	if err := tree.AppendLeaf("zzz"); !errors.Is(err, ErrInvalidLeafFormat) {
+		t.Fatalf("expected ErrInvalidLeafFormat, got %v", err)
+	}
+	after, _ := tree.Root()
+	if after != before || tree.Len() != 3 {
+		t.Errorf("tree changed after rejected leaf: root %s -> %s, len %d", before, after, tree.Len())
+	}
+}
@@ -0,0 +1,87 @@
+package merkle
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// goldenVectors mirrors testdata/vectors.json, the published fixtures other
+// verifiers must reproduce bit-for-bit. Never regenerate them from this
+// package: a mismatch means the canon wire behavior changed.
+type goldenVectors struct {
+	Description string `json:"description"`
+	Vectors     []struct {
+		LeafCount int      `json:"leaf_count"`
+		Leaves    []string `json:"leaves"`
+		Root      string   `json:"root"`
+		Proofs    []struct {
+			Index int         `json:"index"`
+			Nodes []ProofNode `json:"nodes"`
+		} `json:"proofs"`
+	} `json:"vectors"`
+}
+
+func loadGoldenVectors(t *testing.T) goldenVectors {
+	t.Helper()
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("failed to read golden vectors: %v", err)
+	}
+	var golden goldenVectors
+	if err := json.Unmarshal(data, &golden); err != nil {
+		t.Fatalf("failed to parse golden vectors: %v", err)
+	}
+	return golden
+}
+
+func TestGoldenVectors(t *testing.T) {
+	golden := loadGoldenVectors(t)
+	if len(golden.Vectors) != 10 {
+		t.Fatalf("expected vectors for 1..10 leaves, got %d", len(golden.Vectors))
+	}
+
+	for i, v := range golden.Vectors {
+		t.Run(strconv.Itoa(v.LeafCount), func(t *testing.T) {
+			if v.LeafCount != i+1 || len(v.Leaves) != v.LeafCount {
+				t.Fatalf("malformed vector: leaf_count %d with %d leaves", v.LeafCount, len(v.Leaves))
+			}
+
+			// The leaves themselves are part of the fixture: SHA-256 of "0".."n-1"
+			vals := make([]string, v.LeafCount)
+			for j := range vals {
+				vals[j] = strconv.Itoa(j)
+			}
+			if want := makeLeaves(vals); !reflect.DeepEqual(v.Leaves, want) {
+				t.Fatalf("leaves = %v, want %v", v.Leaves, want)
+			}
+
+			root, err := BuildRoot(v.Leaves)
+			if err != nil {
+				t.Fatalf("BuildRoot error: %v", err)
+			}
+			if root != v.Root {
+				t.Errorf("BuildRoot = %s, golden %s", root, v.Root)
+			}
+
+			for _, p := range v.Proofs {
+				proof, prRoot, err := BuildProof(v.Leaves, p.Index)
+				if err != nil {
+					t.Fatalf("BuildProof(%d) error: %v", p.Index, err)
+				}
+				if prRoot != v.Root {
+					t.Errorf("BuildProof(%d) root = %s, golden %s", p.Index, prRoot, v.Root)
+				}
+				if !reflect.DeepEqual(proof, p.Nodes) {
+					t.Errorf("BuildProof(%d) = %+v, golden %+v", p.Index, proof, p.Nodes)
+				}
+				ok, err := VerifyProof(v.Leaves[p.Index], p.Index, v.LeafCount, p.Nodes, v.Root)
+				if !ok || err != nil {
+					t.Errorf("golden proof for index %d does not verify: ok=%v err=%v", p.Index, ok, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package sign
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// AlgEd25519 is the only detached-signature algorithm defined by Sign v1.0.
+const AlgEd25519 = "Ed25519"
+
+// ErrUnsupportedAlg is returned when a detached signature names an algorithm other than Ed25519.
+var ErrUnsupportedAlg = errors.New("unsupported signature algorithm")
+
+// DetachedSignature is a signature over the SHA-256 of a whole file, stored
+// separately from the file itself (e.g. policy.json + policy.json.sig).
+type DetachedSignature struct {
+	Alg     string `json:"alg"`      // Always "Ed25519"
+	HashHex string `json:"hash_hex"` // SHA-256 of the file contents (64 hex)
+	SigHex  string `json:"sig_hex"`  // Ed25519 signature over the raw hash bytes (128 hex)
+	PubHex  string `json:"pub_hex"`  // Signer public key (64 hex)
+}
+
+// SignFile hashes the file at path and signs the digest with the seed (64 hex).
+// The message signed is the raw 32-byte digest, exactly as in SignHashHex.
+func SignFile(path, seedHex string) (*DetachedSignature, error) {
+	hashHex, err := hashFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sigHex, pubHex, err := SignHashHex(hashHex, seedHex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DetachedSignature{Alg: AlgEd25519, HashHex: hashHex, SigHex: sigHex, PubHex: pubHex}, nil
+}
+
+// VerifyFile re-hashes the file at path and verifies sig against that digest.
+//
+// Returns (true, nil) if valid.
+// Returns (false, ErrVerificationFailed) if the file changed since signing or the signature mismatches.
+// Returns (false, error) for malformed signatures or I/O failures.
+func VerifyFile(path string, sig *DetachedSignature) (bool, error) {
+	if sig == nil {
+		return false, fmt.Errorf("%w: nil detached signature", ErrInvalidHex)
+	}
+	if sig.Alg != AlgEd25519 {
+		return false, fmt.Errorf("%w: %q", ErrUnsupportedAlg, sig.Alg)
+	}
+	if err := ValidateHashHex(sig.HashHex); err != nil {
+		return false, err
+	}
+
+	hashHex, err := hashFile(path)
+	if err != nil {
+		return false, err
+	}
+	if hashHex != sig.HashHex {
+		return false, fmt.Errorf("%w: file hash %s does not match signed hash %s", ErrVerificationFailed, hashHex, sig.HashHex)
+	}
+
+	return VerifyHashHex(hashHex, sig.SigHex, sig.PubHex)
+}
+
+// hashFile streams the file at path through SHA-256.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,87 @@
+package sign
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rotation_policy.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	return path
+}
+
+func TestSignFileAndVerifyFile_Success(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	path := writeTempFile(t, `{"policy_version":"1.0"}`)
+
+	sig, err := SignFile(path, seed)
+	if err != nil {
+		t.Fatalf("SignFile error: %v", err)
+	}
+	if sig.Alg != AlgEd25519 {
+		t.Fatalf("Alg = %q, want %q", sig.Alg, AlgEd25519)
+	}
+
+	pub, _, err := DeriveKeyPairFromSeedHex(seed)
+	if err != nil {
+		t.Fatalf("DeriveKeyPairFromSeedHex error: %v", err)
+	}
+	if sig.PubHex != pub {
+		t.Fatalf("PubHex = %s, want %s", sig.PubHex, pub)
+	}
+
+	ok, err := VerifyFile(path, sig)
+	if err != nil || !ok {
+		t.Fatalf("VerifyFile: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestVerifyFile_DetectsEditAfterSigning(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	path := writeTempFile(t, `{"policy_version":"1.0"}`)
+
+	sig, err := SignFile(path, seed)
+	if err != nil {
+		t.Fatalf("SignFile error: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"policy_version":"1.1"}`), 0644); err != nil {
+		t.Fatalf("failed to edit file: %v", err)
+	}
+
+	ok, err := VerifyFile(path, sig)
+	if ok {
+		t.Fatalf("expected verification to fail after edit")
+	}
+	if !errors.Is(err, ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed, got %v", err)
+	}
+}
+
+func TestVerifyFile_UnsupportedAlg(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	path := writeTempFile(t, "payload")
+
+	sig, err := SignFile(path, seed)
+	if err != nil {
+		t.Fatalf("SignFile error: %v", err)
+	}
+	sig.Alg = "RSA"
+
+	if ok, err := VerifyFile(path, sig); ok || !errors.Is(err, ErrUnsupportedAlg) {
+		t.Fatalf("expected ErrUnsupportedAlg, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestSignFile_MissingFile(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	if _, err := SignFile(filepath.Join(t.TempDir(), "absent"), seed); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+}
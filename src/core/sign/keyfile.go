@@ -0,0 +1,93 @@
+package sign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeyfileVersion is the only keyfile format version this package reads and writes.
+const KeyfileVersion = 1
+
+var (
+	// ErrKeyfileVersion is returned when a keyfile declares a version other than KeyfileVersion.
+	ErrKeyfileVersion = errors.New("unsupported keyfile version")
+
+	// ErrKeyfilePermissions is returned when a keyfile is readable by group or others.
+	ErrKeyfilePermissions = errors.New("keyfile permissions too open")
+)
+
+// Keyfile is the on-disk envelope of a signing seed:
+//
+//	{"v":1,"alg":"Ed25519","seed_hex":"<64 lowercase hex>"}
+//
+// The version and algorithm make the file self-describing, unlike a bare seed
+// in an environment variable.
+type Keyfile struct {
+	V       int    `json:"v"`
+	Alg     string `json:"alg"`
+	SeedHex string `json:"seed_hex"`
+}
+
+// SaveKeyfile validates seedHex and writes it to path as a version 1 keyfile,
+// readable by the owner only (0600), tightening the mode of an existing file.
+func SaveKeyfile(path, seedHex string) error {
+	if err := ValidateSeedHex(seedHex); err != nil {
+		return err
+	}
+	data, err := json.Marshal(Keyfile{V: KeyfileVersion, Alg: AlgEd25519, SeedHex: seedHex})
+	if err != nil {
+		return fmt.Errorf("failed to encode keyfile: %w", err)
+	}
+	data = append(data, '\n')
+	defer zeroize(data)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to restrict %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadKeyfile reads a keyfile written by SaveKeyfile and returns its seed as
+// 64 lowercase hex. It refuses files that group or others can read (on
+// platforms with Unix permissions), unknown versions, algorithms other than
+// Ed25519 and malformed seeds.
+func LoadKeyfile(path string) (seedHex string, err error) {
+	if err := checkKeyfileMode(path); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer zeroize(data)
+
+	var kf Keyfile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return "", fmt.Errorf("%s: invalid keyfile JSON: %w", path, err)
+	}
+	if kf.V != KeyfileVersion {
+		return "", fmt.Errorf("%w: %s has v=%d, expected %d", ErrKeyfileVersion, path, kf.V, KeyfileVersion)
+	}
+	if kf.Alg != AlgEd25519 {
+		return "", fmt.Errorf("%w: %s has alg %q", ErrUnsupportedAlg, path, kf.Alg)
+	}
+	if err := ValidateSeedHex(kf.SeedHex); err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return kf.SeedHex, nil
+}
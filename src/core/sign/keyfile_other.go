@@ -0,0 +1,9 @@
+//go:build !unix
+
+package sign
+
+// checkKeyfileMode is a no-op where Unix permission bits do not describe who can
+// read the file (e.g. Windows ACLs): protecting the keyfile is left to the platform.
+func checkKeyfileMode(path string) error {
+	return nil
+}
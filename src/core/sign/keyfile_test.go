@@ -0,0 +1,67 @@
+package sign
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyfile_RoundTrip(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	path := filepath.Join(t.TempDir(), "seal.key")
+
+	if err := SaveKeyfile(path, seed); err != nil {
+		t.Fatalf("SaveKeyfile error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read keyfile: %v", err)
+	}
+	if want := `{"v":1,"alg":"Ed25519","seed_hex":"` + seed + `"}` + "\n"; string(data) != want {
+		t.Fatalf("keyfile = %q, want %q", data, want)
+	}
+
+	got, err := LoadKeyfile(path)
+	if err != nil {
+		t.Fatalf("LoadKeyfile error: %v", err)
+	}
+	if got != seed {
+		t.Fatalf("seed = %s, want %s", got, seed)
+	}
+}
+
+func TestSaveKeyfile_RejectsBadSeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seal.key")
+	if err := SaveKeyfile(path, "ABC"); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("keyfile written for an invalid seed")
+	}
+}
+
+func TestLoadKeyfile_RejectsBadContent(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{"unknown version", `{"v":2,"alg":"Ed25519","seed_hex":"` + seed + `"}`, ErrKeyfileVersion},
+		{"missing version", `{"alg":"Ed25519","seed_hex":"` + seed + `"}`, ErrKeyfileVersion},
+		{"other alg", `{"v":1,"alg":"RSA","seed_hex":"` + seed + `"}`, ErrUnsupportedAlg},
+		{"bad seed", `{"v":1,"alg":"Ed25519","seed_hex":"00"}`, ErrInvalidHex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "seal.key")
+			if err := os.WriteFile(path, []byte(tt.body), 0600); err != nil {
+				t.Fatalf("failed to write keyfile: %v", err)
+			}
+			if _, err := LoadKeyfile(path); !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package sign
+
+import (
+	"fmt"
+	"os"
+)
+
+// checkKeyfileMode rejects a keyfile with any group or other permission bit set.
+func checkKeyfileMode(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("%w: %s has mode %04o, expected 0600 or stricter", ErrKeyfilePermissions, path, perm)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package sign
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKeyfile_RejectsLoosePermissions(t *testing.T) {
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	path := filepath.Join(t.TempDir(), "seal.key")
+	if err := SaveKeyfile(path, seed); err != nil {
+		t.Fatalf("SaveKeyfile error: %v", err)
+	}
+
+	for _, mode := range []os.FileMode{0644, 0640, 0604} {
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatalf("chmod failed: %v", err)
+		}
+		if _, err := LoadKeyfile(path); !errors.Is(err, ErrKeyfilePermissions) {
+			t.Errorf("mode %04o: expected ErrKeyfilePermissions, got %v", mode, err)
+		}
+	}
+
+	// Saving again tightens the existing file back to 0600
+	if err := SaveKeyfile(path, seed); err != nil {
+		t.Fatalf("SaveKeyfile error: %v", err)
+	}
+	if _, err := LoadKeyfile(path); err != nil {
+		t.Fatalf("LoadKeyfile after re-save: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrNonCanonicalSignature is returned in strict mode for a signature whose S
+// scalar is not reduced modulo the group order L (RFC 8032, section 5.1.7).
+var ErrNonCanonicalSignature = errors.New("non-canonical signature")
+
+// strictSignatures enables the S < L check in VerifyHashHex (off by default)
+var strictSignatures atomic.Bool
+
+// SetStrictSignatures turns the signature malleability check on or off for every
+// verification in the process. When on, VerifyHashHex rejects a signature with
+// S >= L with ErrNonCanonicalSignature before running ed25519.Verify, so the
+// same (key, hash) can never be accepted under two different signature
+// encodings regardless of the Go version's own checks. Off, verification is
+// exactly ed25519.Verify, which keeps every existing seal readable.
+func SetStrictSignatures(enabled bool) {
+	strictSignatures.Store(enabled)
+}
+
+// groupOrder is L = 2^252 + 27742317777372353535851937790883648493 in
+// little-endian byte order, as S is encoded in a signature.
+var groupOrder = [32]byte{
+	0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+	0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+}
+
+// SignatureMalleabilityCheck reports ErrNonCanonicalSignature unless the S half
+// of sig (64 bytes) is strictly less than L. It runs whether or not strict mode
+// is on, for callers that want the check on a single path.
+func SignatureMalleabilityCheck(sig []byte) error {
+	if len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: signature bytes=%d expected=%d", ErrInvalidLength, len(sig), ed25519.SignatureSize)
+	}
+	s := sig[32:]
+	// Compare as little-endian integers, most significant byte first
+	for i := 31; i >= 0; i-- {
+		if s[i] < groupOrder[i] {
+			return nil
+		}
+		if s[i] > groupOrder[i] {
+			break
+		}
+	}
+	return fmt.Errorf("%w: S is not reduced modulo the group order", ErrNonCanonicalSignature)
+}
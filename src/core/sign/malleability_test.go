@@ -0,0 +1,77 @@
+package sign
+
+import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// maulSignature returns sigHex with S replaced by S + L, the classic malleated
+// encoding of the same signature.
+func maulSignature(t *testing.T, sigHex string) string {
+	t.Helper()
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	le := func(b []byte) *big.Int {
+		be := make([]byte, len(b))
+		for i := range b {
+			be[len(b)-1-i] = b[i]
+		}
+		return new(big.Int).SetBytes(be)
+	}
+	s := new(big.Int).Add(le(sig[32:]), le(groupOrder[:]))
+	be := s.FillBytes(make([]byte, 32))
+	for i := range be {
+		sig[32+i] = be[31-i]
+	}
+	return hex.EncodeToString(sig)
+}
+
+func TestStrictSignatures_RejectsMauledS(t *testing.T) {
+	t.Cleanup(func() { SetStrictSignatures(false) })
+
+	seed := "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	hashHex := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	sigHex, pubHex, err := SignHashHex(hashHex, seed)
+	if err != nil {
+		t.Fatalf("SignHashHex error: %v", err)
+	}
+	mauled := maulSignature(t, sigHex)
+
+	for _, strict := range []bool{false, true} {
+		SetStrictSignatures(strict)
+
+		if ok, err := VerifyHashHex(hashHex, sigHex, pubHex); err != nil || !ok {
+			t.Fatalf("strict=%v: canonical signature = (%v, %v), want (true, nil)", strict, ok, err)
+		}
+
+		ok, err := VerifyHashHex(hashHex, mauled, pubHex)
+		if ok {
+			t.Fatalf("strict=%v: mauled signature accepted", strict)
+		}
+		if strict && !errors.Is(err, ErrNonCanonicalSignature) {
+			t.Fatalf("strict: expected ErrNonCanonicalSignature, got %v", err)
+		}
+		if !strict && !errors.Is(err, ErrVerificationFailed) {
+			t.Fatalf("default: expected stdlib ErrVerificationFailed, got %v", err)
+		}
+	}
+}
+
+func TestSignatureMalleabilityCheck_Boundary(t *testing.T) {
+	sig := make([]byte, 64)
+	copy(sig[32:], groupOrder[:])
+	if err := SignatureMalleabilityCheck(sig); !errors.Is(err, ErrNonCanonicalSignature) {
+		t.Fatalf("S = L: expected ErrNonCanonicalSignature, got %v", err)
+	}
+	sig[32]-- // L - 1
+	if err := SignatureMalleabilityCheck(sig); err != nil {
+		t.Fatalf("S = L-1: unexpected error %v", err)
+	}
+	if err := SignatureMalleabilityCheck(sig[:63]); !errors.Is(err, ErrInvalidLength) {
+		t.Fatalf("short signature: expected ErrInvalidLength, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsupportedKey is returned when a PEM file holds a key that is not Ed25519.
+var ErrUnsupportedKey = errors.New("unsupported key type")
+
+// LoadSeedFromPEM reads an Ed25519 private key from a PKCS#8 PEM file (e.g.
+// `openssl genpkey -algorithm ed25519`) and returns its seed as 64 lowercase hex,
+// the form SignHashHex accepts. The decoded key bytes are zeroed before returning.
+func LoadSeedFromPEM(path string) (seedHex string, err error) {
+	der, err := readPEMBlock(path, "PRIVATE KEY")
+	if err != nil {
+		return "", err
+	}
+	defer zeroize(der)
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to parse PKCS#8 private key: %w", path, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return "", fmt.Errorf("%w: %s holds a %T, expected Ed25519", ErrUnsupportedKey, path, key)
+	}
+	defer zeroize(priv)
+
+	seed := priv.Seed()
+	defer zeroize(seed)
+	return hex.EncodeToString(seed), nil
+}
+
+// LoadPublicKeyFromPEM reads an Ed25519 public key from a PKIX PEM file (e.g.
+// `openssl pkey -pubout`) and returns it as 64 lowercase hex.
+func LoadPublicKeyFromPEM(path string) (pubHex string, err error) {
+	der, err := readPEMBlock(path, "PUBLIC KEY")
+	if err != nil {
+		return "", err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return "", fmt.Errorf("%s: failed to parse PKIX public key: %w", path, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return "", fmt.Errorf("%w: %s holds a %T, expected Ed25519", ErrUnsupportedKey, path, key)
+	}
+	return hex.EncodeToString(pub), nil
+}
+
+// readPEMBlock returns the DER bytes of the first PEM block in path, which must be of blockType.
+func readPEMBlock(path, blockType string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	defer zeroize(data)
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+	if block.Type != blockType {
+		return nil, fmt.Errorf("%w: %s holds a %q PEM block, expected %q", ErrUnsupportedKey, path, block.Type, blockType)
+	}
+	return block.Bytes, nil
+}
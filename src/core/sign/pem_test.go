@@ -0,0 +1,106 @@
+package sign
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePEM encodes der as a PEM block of blockType in a temp file
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write PEM: %v", err)
+	}
+	return path
+}
+
+func TestLoadKeysFromPEM_RoundTrip(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey error: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey error: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey error: %v", err)
+	}
+
+	seedHex, err := LoadSeedFromPEM(writePEM(t, "PRIVATE KEY", privDER))
+	if err != nil {
+		t.Fatalf("LoadSeedFromPEM error: %v", err)
+	}
+	pubHex, err := LoadPublicKeyFromPEM(writePEM(t, "PUBLIC KEY", pubDER))
+	if err != nil {
+		t.Fatalf("LoadPublicKeyFromPEM error: %v", err)
+	}
+	if err := ValidateSeedHex(seedHex); err != nil {
+		t.Fatalf("seed not canonical hex: %v", err)
+	}
+	if err := ValidatePubKeyHex(pubHex); err != nil {
+		t.Fatalf("pubkey not canonical hex: %v", err)
+	}
+
+	hashHex := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	sigHex, signerPub, err := SignHashHex(hashHex, seedHex)
+	if err != nil {
+		t.Fatalf("SignHashHex error: %v", err)
+	}
+	if signerPub != pubHex {
+		t.Fatalf("seed derives %s, PEM public key is %s", signerPub, pubHex)
+	}
+	if ok, err := VerifyHashHex(hashHex, sigHex, pubHex); !ok || err != nil {
+		t.Fatalf("signature does not verify under PEM public key: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestLoadKeysFromPEM_RejectsNonEd25519(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey error: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey error: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey error: %v", err)
+	}
+
+	if _, err := LoadSeedFromPEM(writePEM(t, "PRIVATE KEY", privDER)); !errors.Is(err, ErrUnsupportedKey) {
+		t.Errorf("LoadSeedFromPEM: expected ErrUnsupportedKey, got %v", err)
+	}
+	if _, err := LoadPublicKeyFromPEM(writePEM(t, "PUBLIC KEY", pubDER)); !errors.Is(err, ErrUnsupportedKey) {
+		t.Errorf("LoadPublicKeyFromPEM: expected ErrUnsupportedKey, got %v", err)
+	}
+}
+
+func TestLoadKeysFromPEM_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage.pem")
+	if err := os.WriteFile(path, []byte("not a pem file"), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if _, err := LoadSeedFromPEM(path); err == nil {
+		t.Error("expected error for file without PEM block")
+	}
+
+	// A public key block where a private key is expected
+	if _, err := LoadSeedFromPEM(writePEM(t, "PUBLIC KEY", []byte{0})); !errors.Is(err, ErrUnsupportedKey) {
+		t.Errorf("expected ErrUnsupportedKey for wrong block type, got %v", err)
+	}
+	if _, err := LoadPublicKeyFromPEM(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
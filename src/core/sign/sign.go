@@ -6,7 +6,13 @@
 // - Public key is 32 bytes encoded as 64-char lowercase hex.
 // - Signature is 64 bytes encoded as 128-char lowercase hex.
 // - All encoding is lowercase hex; inputs are validated strictly.
+// - Signing is deterministic (pure Ed25519, RFC 8032): the same hash and seed
+//   always yield the same signature, so seals are reproducible.
 // - stdlib-only.
+//
+// Seed and private key bytes are zeroed as soon as signing or derivation
+// completes. Hex strings (seedHex, privHex) are immutable Go strings and cannot
+// be wiped here: keeping them short-lived is the caller's responsibility.
 package sign
 
 import (
@@ -76,11 +82,13 @@ func DeriveKeyPairFromSeedHex(seedHex string) (pubHex string, privHex string, er
 	if err != nil {
 		return "", "", fmt.Errorf("failed to decode seed hex: %w", err)
 	}
+	defer zeroize(seed)
 	if len(seed) != ed25519.SeedSize {
 		return "", "", fmt.Errorf("%w: seed bytes=%d expected=%d", ErrInvalidLength, len(seed), ed25519.SeedSize)
 	}
 
 	priv := ed25519.NewKeyFromSeed(seed)         // 64 bytes
+	defer zeroize(priv)
 	pub := priv.Public().(ed25519.PublicKey)     // 32 bytes
 	return hex.EncodeToString(pub), hex.EncodeToString(priv), nil
 }
@@ -110,21 +118,33 @@ func SignHashHex(hashHex string, seedHex string) (sigHex string, pubHex string,
 	if err != nil {
 		return "", "", fmt.Errorf("failed to decode seed hex: %w", err)
 	}
+	defer zeroize(seed)
 	if len(seed) != ed25519.SeedSize {
 		return "", "", fmt.Errorf("%w: seed bytes=%d expected=%d", ErrInvalidLength, len(seed), ed25519.SeedSize)
 	}
 
 	priv := ed25519.NewKeyFromSeed(seed)
+	defer zeroize(priv)
 	pub := priv.Public().(ed25519.PublicKey)
 
 	sig := ed25519.Sign(priv, msg) // 64 bytes
 	return hex.EncodeToString(sig), hex.EncodeToString(pub), nil
 }
 
+// SignHashHexDeterministic is SignHashHex under its canon guarantee: pure Ed25519
+// (RFC 8032) derives the nonce from the key and message, so signing the same hash
+// with the same seed always returns byte-identical signatures. Callers that rely
+// on reproducible seals should use this name; a randomized signing variant must
+// never be substituted behind it.
+func SignHashHexDeterministic(hashHex string, seedHex string) (sigHex string, pubHex string, err error) {
+	return SignHashHex(hashHex, seedHex)
+}
+
 // VerifyHashHex verifies a signature over a 32-byte hash (64 hex) using a public key (64 hex).
 //
 // Returns (true, nil) if valid.
 // Returns (false, ErrVerificationFailed) if syntactically valid inputs but signature mismatch.
+// Returns (false, ErrNonCanonicalSignature) for S >= L under SetStrictSignatures(true).
 // Returns (false, error) for malformed inputs.
 func VerifyHashHex(hashHex string, sigHex string, pubHex string) (bool, error) {
 	if err := ValidateHashHex(hashHex); err != nil {
@@ -161,9 +181,83 @@ func VerifyHashHex(hashHex string, sigHex string, pubHex string) (bool, error) {
 		return false, fmt.Errorf("%w: pubkey bytes=%d expected=%d", ErrInvalidLength, len(pub), ed25519.PublicKeySize)
 	}
 
+	if strictSignatures.Load() {
+		if err := SignatureMalleabilityCheck(sig); err != nil {
+			return false, err
+		}
+	}
+
 	ok := ed25519.Verify(ed25519.PublicKey(pub), msg, sig)
 	if !ok {
 		return false, ErrVerificationFailed
 	}
 	return true, nil
 }
+
+// VerifyHashHexAnyKey verifies a signature over a 32-byte hash (64 hex) against a
+// set of trusted public keys, e.g. the old and new key during a rotation grace period.
+// All keys are validated before any verification is attempted.
+//
+// Returns (matchedPub, true, nil) for the first key the signature verifies under.
+// Returns ("", false, ErrVerificationFailed) if no key matches.
+// Returns ("", false, error) for malformed inputs or an empty key set.
+func VerifyHashHexAnyKey(hashHex, sigHex string, pubHexes []string) (matchedPub string, ok bool, err error) {
+	if len(pubHexes) == 0 {
+		return "", false, fmt.Errorf("%w: no candidate public keys", ErrInvalidLength)
+	}
+	if err := ValidateHashHex(hashHex); err != nil {
+		return "", false, err
+	}
+	if err := ValidateSignatureHex(sigHex); err != nil {
+		return "", false, err
+	}
+	for i, pubHex := range pubHexes {
+		if err := ValidatePubKeyHex(pubHex); err != nil {
+			return "", false, fmt.Errorf("pubHexes[%d]: %w", i, err)
+		}
+	}
+
+	for _, pubHex := range pubHexes {
+		ok, err := VerifyHashHex(hashHex, sigHex, pubHex)
+		if ok {
+			return pubHex, true, nil
+		}
+		if !errors.Is(err, ErrVerificationFailed) {
+			return "", false, err
+		}
+	}
+	return "", false, ErrVerificationFailed
+}
+
+// PublicKeyFromSeedHex derives only the public key (64 hex) from a seed (64 hex).
+// The intermediate seed and private key bytes are zeroed before returning.
+func PublicKeyFromSeedHex(seedHex string) (pubHex string, err error) {
+	if err := ValidateSeedHex(seedHex); err != nil {
+		return "", err
+	}
+	seed, err := hex.DecodeString(seedHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode seed hex: %w", err)
+	}
+	defer zeroize(seed)
+	if len(seed) != ed25519.SeedSize {
+		return "", fmt.Errorf("%w: seed bytes=%d expected=%d", ErrInvalidLength, len(seed), ed25519.SeedSize)
+	}
+
+	priv := ed25519.NewKeyFromSeed(seed)
+	defer zeroize(priv)
+
+	pub := priv.Public().(ed25519.PublicKey) // copy, independent of priv
+	return hex.EncodeToString(pub), nil
+}
+
+// zeroize overwrites key material in place. It is a variable so tests can
+// observe which buffers are wiped.
+var zeroize = wipe
+
+// wipe sets every byte of b to zero.
+func wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
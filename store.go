@@ -0,0 +1,182 @@
+package ledger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+)
+
+// Default permissions for a new ledger directory and file
+const (
+	DefaultLedgerDirMode  os.FileMode = 0755
+	DefaultLedgerFileMode os.FileMode = 0644
+)
+
+// ledgerDirMode and ledgerFileMode are applied when FileStore creates the
+// ledger directory, the ledger file and its sidecars
+var ledgerDirMode, ledgerFileMode atomic.Uint32
+
+func init() {
+	SetLedgerFileMode(DefaultLedgerDirMode, DefaultLedgerFileMode)
+}
+
+// SetLedgerFileMode sets the permissions used when the ledger directory and
+// file are first created, e.g. 0700/0600 for ledgers that must not be world
+// readable. As with os.MkdirAll and os.OpenFile, the process umask still
+// applies, and existing directories and files are left as they are.
+func SetLedgerFileMode(dirMode, fileMode os.FileMode) {
+	ledgerDirMode.Store(uint32(dirMode.Perm()))
+	ledgerFileMode.Store(uint32(fileMode.Perm()))
+}
+
+// Store is the append-only backend behind the ledger.
+//
+// Append receives one JSON entry without a trailing newline and must persist it
+// atomically as a single line. Iterate calls fn with every stored line in append
+// order, including empty lines, so line numbers in errors match the backing file;
+// the slice passed to fn is only valid for the duration of the call. Iterate
+// returns fn's error unchanged.
+type Store interface {
+	Append(line []byte) error
+	Iterate(fn func(line []byte) error) error
+}
+
+// FileStore is the default JSONL backend: one entry per line in the file at Path.
+type FileStore struct {
+	Path string
+}
+
+// Append writes line plus newline in a single write so a crash can only ever
+// leave a torn final line (see RepairTruncatedTail), and syncs the file before
+// returning, so an entry handed to observers is already on disk. The write
+// happens under an advisory file lock so separate processes cannot interleave
+// partial lines.
+func (s *FileStore) Append(line []byte) error {
+	// Ensure ledger directory exists
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, os.FileMode(ledgerDirMode.Load())); err != nil {
+		return fmt.Errorf("%w: failed to create ledger directory: %v", ErrLedgerIO, err)
+	}
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, os.FileMode(ledgerFileMode.Load()))
+	if err != nil {
+		return fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+
+	// Serialise with other processes appending to the same file
+	if err := lockFile(file); err != nil {
+		return err
+	}
+	defer unlockFile(file)
+
+	buf := make([]byte, 0, len(line)+1)
+	buf = append(append(buf, line...), '\n')
+	if _, err := file.Write(buf); err != nil {
+		return fmt.Errorf("%w: failed to write entry: %v", ErrLedgerIO, err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("%w: failed to sync ledger: %v", ErrLedgerIO, err)
+	}
+
+	if indexing.Load() {
+		// The entry is durable; a failed index update only costs speed, so drop
+		// the sidecar and let queries fall back to a linear scan
+		info, err := file.Stat()
+		if err != nil || s.updateIndex(info.Size(), len(buf)) != nil {
+			os.Remove(IndexPath(s.Path))
+		}
+	}
+
+	return nil
+}
+
+// Iterate reads the file line by line. A missing file is an empty ledger.
+func (s *FileStore) Iterate(fn func(line []byte) error) error {
+	return s.iterateFrom(0, fn)
+}
+
+// maxLineBytes bounds a single line read by FileStore. It fits a register whose
+// payload is at the default size cap (base64 adds a third) many times over; a
+// longer line fails the scan with ErrLedgerIO instead of exhausting memory.
+const maxLineBytes = 16 << 20
+
+// iterateFrom reads the file line by line starting at byte offset, which must
+// be the start of a line (see Seek).
+func (s *FileStore) iterateFrom(offset int64, fn func(line []byte) error) error {
+	file, err := os.Open(s.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: failed to open ledger: %v", ErrLedgerIO, err)
+	}
+	defer file.Close()
+
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("%w: failed to seek ledger: %v", ErrLedgerIO, err)
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+	for scanner.Scan() {
+		if err := fn(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: failed to read ledger: %v", ErrLedgerIO, err)
+	}
+
+	return nil
+}
+
+// MemStore keeps the ledger in memory, for tests and for embedding the ledger
+// in services that persist entries elsewhere. The zero value is an empty ledger.
+type MemStore struct {
+	mu    sync.RWMutex
+	lines [][]byte
+}
+
+// NewMemStore returns an empty in-memory ledger.
+func NewMemStore() *MemStore {
+	return &MemStore{}
+}
+
+// Append stores a copy of line.
+func (s *MemStore) Append(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = append(s.lines, append([]byte(nil), line...))
+	return nil
+}
+
+// Iterate calls fn with every stored line in append order.
+func (s *MemStore) Iterate(fn func(line []byte) error) error {
+	s.mu.RLock()
+	lines := s.lines[:len(s.lines):len(s.lines)]
+	s.mu.RUnlock()
+
+	for _, line := range lines {
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of stored lines.
+func (s *MemStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.lines)
+}
@@ -0,0 +1,129 @@
+package ledger
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// setupMemLedger routes the ledger through a fresh MemStore for the duration of the test
+func setupMemLedger(t *testing.T) *MemStore {
+	t.Helper()
+	setupTestLedger(t)
+	mem := NewMemStore()
+	SetStore(mem)
+	t.Cleanup(func() { SetStore(nil) })
+	return mem
+}
+
+// backends runs fn once per Store implementation
+func backends(t *testing.T, fn func(t *testing.T)) {
+	t.Run("file", func(t *testing.T) {
+		setupTestLedger(t)
+		fn(t)
+	})
+	t.Run("mem", func(t *testing.T) {
+		setupMemLedger(t)
+		fn(t)
+	})
+}
+
+func TestStore_RegisterSealList(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		buildSealedLedger(t, 2, 3)
+		if err := AppendRegister(testHash(10), []byte(`{"k":"v"}`)); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+
+		all, err := ListRegistersSince(time.Time{})
+		if err != nil {
+			t.Fatalf("ListRegistersSince failed: %v", err)
+		}
+		if len(all) != 6 {
+			t.Fatalf("expected 6 registers, got %d", len(all))
+		}
+
+		pending, err := ListPendingRegisters()
+		if err != nil {
+			t.Fatalf("ListPendingRegisters failed: %v", err)
+		}
+		if len(pending) != 1 || pending[0].ObjectHashHex != testHash(10) {
+			t.Fatalf("unexpected pending registers: %+v", pending)
+		}
+
+		next, err := NextEpochID()
+		if err != nil || next != 2 {
+			t.Fatalf("NextEpochID = %d, %v; want 2", next, err)
+		}
+
+		reg, found, err := GetRegisterByHash(testHash(3))
+		if err != nil || !found || reg.ObjectHashHex != testHash(3) {
+			t.Fatalf("GetRegisterByHash = %+v, %v, %v", reg, found, err)
+		}
+
+		report, err := CheckIntegrity()
+		if err != nil || !report.Valid || report.Registers != 6 || report.Seals != 2 {
+			t.Fatalf("CheckIntegrity = %+v, %v", report, err)
+		}
+	})
+}
+
+func TestStore_SealRules(t *testing.T) {
+	backends(t, func(t *testing.T) {
+		if err := AppendSeal(validManifest()); !errors.Is(err, ErrNoRegistrations) {
+			t.Fatalf("expected ErrNoRegistrations, got %v", err)
+		}
+
+		if err := AppendRegisterIdempotent(testHash(1), nil); err != nil {
+			t.Fatalf("AppendRegisterIdempotent failed: %v", err)
+		}
+		if err := AppendRegisterIdempotent(testHash(1), nil); !errors.Is(err, ErrDuplicate) {
+			t.Fatalf("expected ErrDuplicate, got %v", err)
+		}
+
+		if err := AppendSeal(signedManifest(t)); err != nil {
+			t.Fatalf("AppendSeal failed: %v", err)
+		}
+		second := validManifest()
+		if err := AppendSeal(second); !errors.Is(err, ErrEpochOutOfSequence) {
+			t.Fatalf("expected ErrEpochOutOfSequence, got %v", err)
+		}
+		second.EpochID = 1
+		if err := AppendSeal(second); !errors.Is(err, ErrNoRegistrations) {
+			t.Fatalf("expected ErrNoRegistrations, got %v", err)
+		}
+	})
+}
+
+func TestMemStore_DoesNotTouchDisk(t *testing.T) {
+	mem := setupMemLedger(t)
+
+	if err := AppendRegister(validObjectHash(), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	if mem.Len() != 1 {
+		t.Fatalf("MemStore.Len = %d, want 1", mem.Len())
+	}
+	if _, err := os.Stat(GetLedgerPath()); !os.IsNotExist(err) {
+		t.Fatalf("ledger file should not exist with MemStore, stat err = %v", err)
+	}
+
+	// Switching back to the file backend sees the (empty) file ledger
+	SetStore(nil)
+	registers, err := ListRegistersSince(time.Time{})
+	if err != nil || len(registers) != 0 {
+		t.Fatalf("file backend should be empty, got %d registers, err=%v", len(registers), err)
+	}
+}
+
+func TestMemStore_CorruptLine(t *testing.T) {
+	mem := setupMemLedger(t)
+
+	if err := mem.Append([]byte("not json")); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+	if _, err := ListRegistersSince(time.Time{}); !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("expected ErrLedgerCorrupt, got %v", err)
+	}
+}
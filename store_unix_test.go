@@ -0,0 +1,61 @@
+//go:build unix
+
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestSetLedgerFileMode_Restricted(t *testing.T) {
+	setupTestLedger(t)
+	path := filepath.Join(t.TempDir(), "private", "ledger.jsonl")
+	SetLedgerPath(path)
+	SetLedgerFileMode(0700, 0600)
+	t.Cleanup(func() { SetLedgerFileMode(DefaultLedgerDirMode, DefaultLedgerFileMode) })
+
+	// Make the result independent of the test runner's umask
+	old := syscall.Umask(0)
+	t.Cleanup(func() { syscall.Umask(old) })
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat ledger: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("ledger mode = %o, want 600", got)
+	}
+	info, err = os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("stat ledger dir: %v", err)
+	}
+	if got := info.Mode().Perm(); got != 0700 {
+		t.Errorf("ledger dir mode = %o, want 700", got)
+	}
+}
+
+func TestSetLedgerFileMode_Defaults(t *testing.T) {
+	setupTestLedger(t)
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	SetLedgerPath(path)
+
+	old := syscall.Umask(0)
+	t.Cleanup(func() { syscall.Umask(old) })
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat ledger: %v", err)
+	}
+	if got := info.Mode().Perm(); got != DefaultLedgerFileMode {
+		t.Errorf("ledger mode = %o, want %o", got, DefaultLedgerFileMode)
+	}
+}
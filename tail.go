@@ -0,0 +1,128 @@
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+)
+
+// tailPollInterval is how often Tail checks the ledger for new lines (shortened in tests)
+var tailPollInterval = 250 * time.Millisecond
+
+// Tail streams registers to out, like `tail -f`.
+//
+// It first emits every existing register with a timestamp after from, then polls
+// the ledger and emits registers as they are appended, until ctx is cancelled.
+// Each poll reads only the lines appended since the previous one, from the byte
+// offset it stopped at; a line still being written is left for the next poll.
+// If the ledger is replaced or truncated (rotation), Tail starts over from the
+// first line of the new ledger, still filtering by from.
+//
+// Tail never closes out. It returns nil when ctx is cancelled, or an error if
+// the ledger is corrupt or cannot be read.
+func Tail(ctx context.Context, from time.Time, out chan<- RegisterEntry) error {
+	var cur tailCursor
+
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+
+	for {
+		poll, err := tailPoll(cur, from)
+		if err != nil {
+			return err
+		}
+
+		// Replaced or shrunk ledger: re-read from the top
+		if poll.rotated {
+			cur = tailCursor{}
+			continue
+		}
+		cur = poll.next
+
+		for _, reg := range poll.batch {
+			select {
+			case out <- reg:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// tailCursor is how far Tail has read. The zero value is the top of the ledger.
+type tailCursor struct {
+	offset  int64  // Byte offset just past the last consumed line
+	lineNum int    // Line number of that line
+	last    []byte // Copy of that line, nil at the top
+}
+
+// tailResult is one poll of the ledger
+type tailResult struct {
+	batch   []RegisterEntry // Registers after the cursor, dated after from
+	next    tailCursor      // Cursor for the next poll
+	rotated bool            // The cursor's line no longer matches, or the ledger shrank
+}
+
+// tailPoll reads the registers after cur. Only complete lines past the cursor
+// are read, plus the cursor's own line, which must still equal cur.last:
+// otherwise the ledger was rotated. The lock is released before the caller
+// sends, so a slow consumer never blocks writers.
+func tailPoll(cur tailCursor, from time.Time) (tailResult, error) {
+	ledgerMutex.RLock()
+	defer ledgerMutex.RUnlock()
+
+	res := tailResult{next: cur}
+	start, firstLine := int64(0), 1
+	if cur.last != nil {
+		start, firstLine = cur.offset-int64(len(cur.last))-1, cur.lineNum+1
+	}
+
+	pos, checkAnchor := start, cur.last != nil
+	iterate := func(visit func(line []byte) error) error {
+		return linesFrom(currentStore(), start, func(line []byte) error {
+			pos += int64(len(line)) + 1
+			if checkAnchor {
+				checkAnchor = false
+				if !bytes.Equal(line, cur.last) {
+					res.rotated = true
+					return errStopScan
+				}
+				return nil
+			}
+			return visit(line)
+		})
+	}
+	err := scanLinesWith(iterate, firstLine, nil, func(lineNum int, entryType string, line []byte) error {
+		res.next = tailCursor{offset: pos, lineNum: lineNum, last: append([]byte(nil), line...)}
+		if entryType != "register" {
+			return nil
+		}
+
+		reg, ts, err := parseRegister(lineNum, line)
+		if err != nil {
+			return err
+		}
+		if ts.After(from) {
+			res.batch = append(res.batch, reg)
+		}
+		return nil
+	})
+	if errors.Is(err, ErrOffsetOutOfRange) || checkAnchor {
+		// The ledger ends before the cursor's line
+		return tailResult{rotated: true}, nil
+	}
+	if err != nil {
+		return tailResult{}, err
+	}
+	if res.rotated {
+		return tailResult{rotated: true}, nil
+	}
+	return res, nil
+}
@@ -0,0 +1,191 @@
+package ledger
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// startTail runs Tail in the background with a short poll interval
+func startTail(t *testing.T, from time.Time) (<-chan RegisterEntry, context.CancelFunc, <-chan error) {
+	t.Helper()
+
+	prev := tailPollInterval
+	tailPollInterval = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan RegisterEntry)
+	done := make(chan error, 1)
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		done <- Tail(ctx, from, out)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+		tailPollInterval = prev
+	})
+	return out, cancel, done
+}
+
+// receive waits for the next register from Tail
+func receive(t *testing.T, out <-chan RegisterEntry) RegisterEntry {
+	t.Helper()
+	select {
+	case reg := <-out:
+		return reg
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for Tail")
+		return RegisterEntry{}
+	}
+}
+
+func TestTail_EmitsExistingThenAppended(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	out, cancel, done := startTail(t, time.Time{})
+	if reg := receive(t, out); reg.ObjectHashHex != testHash(0) {
+		t.Fatalf("existing register = %s, want %s", reg.ObjectHashHex, testHash(0))
+	}
+
+	for i := 1; i <= 2; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	for i := 1; i <= 2; i++ {
+		if reg := receive(t, out); reg.ObjectHashHex != testHash(i) {
+			t.Fatalf("register %d = %s, want %s", i, reg.ObjectHashHex, testHash(i))
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Tail returned %v after cancellation", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Tail did not stop after cancellation")
+	}
+}
+
+func TestTail_FiltersByFrom(t *testing.T) {
+	setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	cutoff := time.Now()
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	out, _, _ := startTail(t, cutoff)
+	if reg := receive(t, out); reg.ObjectHashHex != testHash(1) {
+		t.Fatalf("first emitted register = %s, want %s", reg.ObjectHashHex, testHash(1))
+	}
+}
+
+func TestTail_SurvivesRotation(t *testing.T) {
+	path := setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	out, _, _ := startTail(t, time.Time{})
+	receive(t, out)
+
+	// Rotate: the old file disappears and a new one starts from scratch
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("failed to remove ledger: %v", err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		if reg := receive(t, out); reg.ObjectHashHex != testHash(i) {
+			t.Fatalf("register after rotation = %s, want %s", reg.ObjectHashHex, testHash(i))
+		}
+	}
+}
+
+func TestTail_ReadsOnlyNewLines(t *testing.T) {
+	path := setupTestLedger(t)
+
+	for i := 0; i < 2; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	out, _, done := startTail(t, time.Time{})
+	receive(t, out)
+	receive(t, out)
+
+	// Damage line 1 in place: a poll that rescanned from the top would fail on it
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	end := bytes.IndexByte(data, '\n')
+	copy(data[:end], bytes.Repeat([]byte("x"), end))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+
+	if err := AppendRegister(testHash(2), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	select {
+	case reg := <-out:
+		if reg.ObjectHashHex != testHash(2) {
+			t.Fatalf("appended register = %s, want %s", reg.ObjectHashHex, testHash(2))
+		}
+	case err := <-done:
+		t.Fatalf("Tail re-read consumed lines and stopped: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for Tail")
+	}
+}
+
+func TestTail_SkipsLineStillBeingWritten(t *testing.T) {
+	path := setupTestLedger(t)
+
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	out, _, done := startTail(t, time.Time{})
+	receive(t, out)
+
+	// Half of the next line: Tail must wait for the rest, not fail on it
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	appendRaw(t, path, string(data[:len(data)/2]))
+	time.Sleep(4 * tailPollInterval)
+	appendRaw(t, path, string(data[len(data)/2:]))
+
+	select {
+	case reg := <-out:
+		if reg.ObjectHashHex != testHash(0) {
+			t.Fatalf("completed register = %s, want %s", reg.ObjectHashHex, testHash(0))
+		}
+	case err := <-done:
+		t.Fatalf("Tail failed on a partial line: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for Tail")
+	}
+}
@@ -0,0 +1,59 @@
+package ledger
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/config"
+)
+
+// CanonTimestampLayout is the one form timestamps are written in: UTC with a
+// literal "Z" and all nine fractional digits, e.g. 2026-01-10T08:00:00.500000000Z.
+// Fixed width means equal instants always produce equal strings, and string
+// order matches time order.
+const CanonTimestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+// NormalizeTimestamp returns t in CanonTimestampLayout. Every timestamp this
+// package writes goes through it.
+func NormalizeTimestamp(t time.Time) string {
+	return t.UTC().Format(CanonTimestampLayout)
+}
+
+// ParseCanonTimestamp parses any RFC3339 timestamp ("Z" or a numeric offset,
+// any fractional precision) and returns it in UTC, so NormalizeTimestamp of
+// the result is the canonical string. Entries written before timestamps were
+// normalized (trimmed fractions) read back unchanged.
+//
+// Returns ErrInvalidTimestamp if s is not RFC3339.
+func ParseCanonTimestamp(s string) (time.Time, error) {
+	ts, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTimestamp, err)
+	}
+	return ts.UTC(), nil
+}
+
+// rejectFutureTimestamps makes register listing treat future timestamps as corruption (off by default)
+var rejectFutureTimestamps atomic.Bool
+
+// SetRejectFutureTimestamps turns the future-timestamp guard on or off. When on,
+// ListRegistersSince (and sealing, which lists the pending registers) reports a
+// register stamped more than config.DefaultClockSkewSeconds after the current
+// time as a *CorruptError. Such a register comes from a broken clock or
+// tampering; left alone it would sort after every seal and stay pending forever.
+func SetRejectFutureTimestamps(enabled bool) {
+	rejectFutureTimestamps.Store(enabled)
+}
+
+// checkNotFuture returns a *CorruptError for a register stamped ts if the guard
+// is on and ts is beyond now plus the clock skew tolerance.
+func checkNotFuture(lineNum int, line []byte, ts time.Time) error {
+	if !rejectFutureTimestamps.Load() {
+		return nil
+	}
+	if limit := now().Add(config.DefaultClockSkewSeconds * time.Second); ts.After(limit) {
+		return corruptLine(lineNum, line, "timestamp %s is in the future (limit %s)", NormalizeTimestamp(ts), NormalizeTimestamp(limit))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTimestamp_EquivalentForms(t *testing.T) {
+	const want = "2026-01-10T12:00:00.500000000Z"
+
+	for _, s := range []string{
+		"2026-01-10T12:00:00.5Z",
+		"2026-01-10T12:00:00.500Z",
+		"2026-01-10T12:00:00.500000000Z",
+		"2026-01-10T12:00:00.5+00:00",
+		"2026-01-10T14:00:00.5+02:00",
+		"2026-01-10T07:00:00.50-05:00",
+	} {
+		ts, err := ParseCanonTimestamp(s)
+		if err != nil {
+			t.Fatalf("ParseCanonTimestamp(%q) failed: %v", s, err)
+		}
+		if got := NormalizeTimestamp(ts); got != want {
+			t.Errorf("%q normalizes to %q, want %q", s, got, want)
+		}
+		if ts.Location() != time.UTC {
+			t.Errorf("%q parsed in %v, want UTC", s, ts.Location())
+		}
+	}
+
+	// Whole seconds keep all nine fractional digits
+	whole := time.Date(2026, 1, 10, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+	if got := NormalizeTimestamp(whole); got != "2026-01-10T11:00:00.000000000Z" {
+		t.Errorf("NormalizeTimestamp(whole second) = %q", got)
+	}
+}
+
+func TestParseCanonTimestamp_Invalid(t *testing.T) {
+	for _, s := range []string{"", "yesterday", "2026-01-10 12:00:00Z", "2026-01-10T12:00:00"} {
+		if _, err := ParseCanonTimestamp(s); !errors.Is(err, ErrInvalidTimestamp) {
+			t.Errorf("ParseCanonTimestamp(%q): expected ErrInvalidTimestamp, got %v", s, err)
+		}
+	}
+}
+
+func TestAppendSeal_NormalizesTimestamp(t *testing.T) {
+	setupTestLedger(t)
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	m := signedManifest(t)
+	m.Timestamp = "2099-01-10T14:00:00.25+02:00"
+	if err := AppendSeal(m); err != nil {
+		t.Fatalf("AppendSeal failed: %v", err)
+	}
+	if got := readSeals(t)[0].Manifest.Timestamp; got != "2099-01-10T12:00:00.250000000Z" {
+		t.Errorf("stored seal timestamp = %q", got)
+	}
+}
+
+func TestSetRejectFutureTimestamps(t *testing.T) {
+	setupTestLedger(t)
+	stamped := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	setClock(t, stamped)
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	SetRejectFutureTimestamps(true)
+	t.Cleanup(func() { SetRejectFutureTimestamps(false) })
+
+	// Clock now runs behind the register: within the skew tolerance it is accepted
+	setClock(t, stamped.Add(-5*time.Minute))
+	if registers, err := ListRegistersSince(time.Time{}); err != nil || len(registers) != 1 {
+		t.Fatalf("slightly-future register: (%d, %v), want accepted", len(registers), err)
+	}
+
+	setClock(t, stamped.Add(-24*time.Hour))
+	_, err := ListRegistersSince(time.Time{})
+	var corrupt *CorruptError
+	if !errors.As(err, &corrupt) || !errors.Is(err, ErrLedgerCorrupt) {
+		t.Fatalf("far-future register: expected *CorruptError, got %v", err)
+	}
+	if corrupt.LineNum != 1 {
+		t.Errorf("LineNum = %d, want 1", corrupt.LineNum)
+	}
+
+	SetRejectFutureTimestamps(false)
+	if registers, err := ListRegistersSince(time.Time{}); err != nil || len(registers) != 1 {
+		t.Fatalf("guard off: (%d, %v), want accepted", len(registers), err)
+	}
+}
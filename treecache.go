@@ -0,0 +1,78 @@
+package ledger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+// ledgerVersion changes whenever this package writes to or switches the ledger,
+// invalidating the cached epoch tree. Writes by other processes are not seen.
+var ledgerVersion atomic.Uint64
+
+// bumpLedgerVersion invalidates the cached epoch tree.
+func bumpLedgerVersion() {
+	ledgerVersion.Add(1)
+}
+
+// epochTreeCache holds the last TreeFromLedger build
+var epochTreeCache struct {
+	mu        sync.Mutex
+	valid     bool
+	version   uint64
+	since     time.Time
+	hashAlg   string
+	pairMode  merkle.PairMode
+	tree      *merkle.LevelTree
+	registers []RegisterEntry
+}
+
+// TreeFromLedger builds the Merkle tree over the registers whose timestamp is
+// after since, taken in file order as ComputeEpochRoot does, and returns it with
+// those registers, so a caller can serve many proofs from one build: leaf i of
+// the tree is registers[i].ObjectHashHex. Selection is by timestamp, not by
+// position: a register appended later but stamped at or before since
+// (AppendRegisterWithTimestamp) is left out.
+//
+// The result is cached until the next append, rotation, repair or backend switch
+// made through this package, or until the Merkle hash algorithm or pair mode
+// changes (SetHashAlg, merkle.SetHashAlg, merkle.SetPairMode); repeated calls
+// with the same since in between return the same tree. The returned tree and registers are shared and must be
+// treated as read-only.
+//
+// Returns ErrNoRegistrations if no register is stamped after since, or a
+// *CorruptError for the first corrupt line under any CorruptionPolicy.
+func TreeFromLedger(since time.Time) (*merkle.LevelTree, []RegisterEntry, error) {
+	c := &epochTreeCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Read the version before scanning: an append racing the scan leaves the
+	// cache stale-tagged, never fresh-tagged with old data
+	version := ledgerVersion.Load()
+	hashAlg, pairMode := merkle.HashAlg(), merkle.ActivePairMode()
+	if c.valid && c.version == version && c.since.Equal(since) && c.hashAlg == hashAlg && c.pairMode == pairMode {
+		return c.tree, c.registers, nil
+	}
+
+	registers, err := listRegistersSinceFailFast(context.Background(), since)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(registers) == 0 {
+		return nil, nil, ErrNoRegistrations
+	}
+
+	tree, err := merkle.BuildLevelTree(registerLeaves(registers))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.valid, c.version, c.since = true, version, since
+	c.hashAlg, c.pairMode = hashAlg, pairMode
+	c.tree, c.registers = tree, registers
+	return tree, registers, nil
+}
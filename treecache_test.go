@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+)
+
+func TestTreeFromLedger_ProofsVerify(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2)
+	for i := 10; i < 15; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+	lastSealTS, err := getLastSealTimestamp()
+	if err != nil {
+		t.Fatalf("getLastSealTimestamp failed: %v", err)
+	}
+
+	tree, registers, err := TreeFromLedger(lastSealTS)
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+	root, count, err := ComputeEpochRoot(lastSealTS)
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if tree.Root() != root || tree.Len() != count || len(registers) != 5 {
+		t.Fatalf("tree root/len = %s/%d, want %s/%d (registers %d)", tree.Root(), tree.Len(), root, count, len(registers))
+	}
+
+	for i, reg := range registers {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatalf("Proof(%d) failed: %v", i, err)
+		}
+		if ok, err := merkle.VerifyProof(reg.ObjectHashHex, i, tree.Len(), proof, root); !ok || err != nil {
+			t.Errorf("proof for register %d does not verify: %v", i, err)
+		}
+	}
+}
+
+func TestTreeFromLedger_CacheInvalidatedByAppend(t *testing.T) {
+	setupTestLedger(t)
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	first, _, err := TreeFromLedger(time.Time{})
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+	again, _, err := TreeFromLedger(time.Time{})
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+	if again != first {
+		t.Errorf("expected the cached tree without an intervening append")
+	}
+
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	rebuilt, registers, err := TreeFromLedger(time.Time{})
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+	if rebuilt == first || rebuilt.Len() != 2 || len(registers) != 2 {
+		t.Errorf("append did not invalidate the cache: len %d", rebuilt.Len())
+	}
+}
+
+func TestTreeFromLedger_CacheInvalidatedByPairMode(t *testing.T) {
+	setupTestLedger(t)
+	for i := 0; i < 3; i++ {
+		if err := AppendRegister(testHash(i), nil); err != nil {
+			t.Fatalf("AppendRegister failed: %v", err)
+		}
+	}
+
+	first, _, err := TreeFromLedger(time.Time{})
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+
+	// No append in between: only the Merkle settings changed
+	if err := merkle.SetPairMode(merkle.PairSorted); err != nil {
+		t.Fatalf("SetPairMode failed: %v", err)
+	}
+	t.Cleanup(func() { merkle.SetPairMode(merkle.PairLeftRight) })
+
+	rebuilt, _, err := TreeFromLedger(time.Time{})
+	if err != nil {
+		t.Fatalf("TreeFromLedger failed: %v", err)
+	}
+	root, _, err := ComputeEpochRoot(time.Time{})
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if rebuilt == first || rebuilt.Root() != root {
+		t.Errorf("pair mode change did not invalidate the cache: root %s, want %s", rebuilt.Root(), root)
+	}
+}
+
+func TestTreeFromLedger_Empty(t *testing.T) {
+	setupTestLedger(t)
+
+	if _, _, err := TreeFromLedger(time.Time{}); !errors.Is(err, ErrNoRegistrations) {
+		t.Fatalf("expected ErrNoRegistrations, got %v", err)
+	}
+}
@@ -0,0 +1,300 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/hash"
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+var (
+	// ErrRegisterNotFound is returned when no register carries the requested hash
+	ErrRegisterNotFound = errors.New("register not found")
+
+	// ErrNotSealed is returned when a register exists but no seal covers its epoch yet
+	ErrNotSealed = errors.New("register not yet sealed")
+
+	// ErrLeafCountMismatch is returned when a tree size differs from a seal's signed leaf_count
+	ErrLeafCountMismatch = errors.New("leaf count mismatch")
+)
+
+// VerificationResult reports each step of verifying a register against its seal
+type VerificationResult struct {
+	ObjectHashHex  string `json:"object_hash_hex"`
+	EpochID        int    `json:"epoch_id"`        // Epoch of the covering seal
+	LeafIndex      int    `json:"leaf_index"`      // Position of the register within the epoch
+	TotalLeaves    int    `json:"total_leaves"`    // Registers covered by the seal
+	MerkleRoot     string `json:"merkle_root"`     // Root claimed by the seal manifest
+	InclusionValid bool   `json:"inclusion_valid"` // Proof from the register verifies against MerkleRoot
+	SignatureValid bool   `json:"signature_valid"` // Manifest signature verifies over MerkleRoot
+	Valid          bool   `json:"valid"`           // Both checks passed
+}
+
+// VerifyRegisterSealed answers "is this register sealed and valid?" in one call.
+//
+// It finds the first register with objectHashHex, takes the seal that closes its
+// epoch, builds the inclusion proof over that epoch's registers (file order),
+// verifies it against the seal's merkle_root and verifies the seal signature.
+//
+// Returns:
+//   - The VerificationResult; a tampered seal yields Valid=false, not an error
+//   - ErrInvalidHex if objectHashHex is malformed
+//   - ErrRegisterNotFound if the hash was never registered
+//   - ErrNotSealed if the register is still pending
+func VerifyRegisterSealed(objectHashHex string) (VerificationResult, error) {
+	loc, err := locateRegister(objectHashHex)
+	if err != nil {
+		return VerificationResult{}, err
+	}
+	if loc.covering == nil {
+		return VerificationResult{}, fmt.Errorf("%w: %s is pending in the current epoch", ErrNotSealed, objectHashHex)
+	}
+
+	m := loc.covering.Manifest
+	result := VerificationResult{
+		ObjectHashHex: objectHashHex,
+		EpochID:       m.EpochID,
+		LeafIndex:     loc.index,
+		TotalLeaves:   len(loc.epochLeaves),
+		MerkleRoot:    m.MerkleRoot,
+	}
+
+	proof, _, err := merkle.BuildProof(loc.epochLeaves, loc.index)
+	if err != nil {
+		return VerificationResult{}, err
+	}
+	// A malformed root is a failed check, not an I/O error
+	result.InclusionValid, _ = merkle.VerifyProof(objectHashHex, loc.index, len(loc.epochLeaves), proof, m.MerkleRoot)
+	result.InclusionValid = result.InclusionValid && m.CheckTreeSize(len(loc.epochLeaves)) == nil
+	result.SignatureValid, _ = VerifyManifestSignature(m)
+	result.Valid = result.InclusionValid && result.SignatureValid
+
+	return result, nil
+}
+
+// RegisterProof is the inclusion proof of a register in its epoch
+type RegisterProof struct {
+	ObjectHashHex string             `json:"object_hash_hex"`
+	EpochID       int                `json:"epoch_id"`     // Covering seal's epoch, or the epoch the register will be sealed in
+	Sealed        bool               `json:"sealed"`       // MerkleRoot is a signed seal root, so the proof never changes
+	LeafIndex     int                `json:"leaf_index"`   // Position of the register within the epoch
+	TotalLeaves   int                `json:"total_leaves"` // Registers in the epoch so far
+	Proof         []merkle.ProofNode `json:"proof"`
+	MerkleRoot    string             `json:"merkle_root"`
+}
+
+// ProveRegister builds the inclusion proof of the first register with
+// objectHashHex. For a sealed register the proof is against the covering seal's
+// merkle_root and is final. For a pending register it is against the root of
+// the registers pending right now, and changes with every append to the epoch.
+//
+// Returns ErrInvalidHex if objectHashHex is malformed, or ErrRegisterNotFound
+// if the hash was never registered.
+func ProveRegister(objectHashHex string) (RegisterProof, error) {
+	loc, err := locateRegister(objectHashHex)
+	if err != nil {
+		return RegisterProof{}, err
+	}
+
+	proof, root, err := merkle.BuildProof(loc.epochLeaves, loc.index)
+	if err != nil {
+		return RegisterProof{}, err
+	}
+
+	result := RegisterProof{
+		ObjectHashHex: objectHashHex,
+		EpochID:       nextEpochID(loc.lastSeal),
+		LeafIndex:     loc.index,
+		TotalLeaves:   len(loc.epochLeaves),
+		Proof:         proof,
+		MerkleRoot:    root,
+	}
+	if loc.covering != nil {
+		result.EpochID = loc.covering.Manifest.EpochID
+		result.Sealed = true
+		result.MerkleRoot = loc.covering.Manifest.MerkleRoot
+	}
+	return result, nil
+}
+
+// registerLocation is where locateRegister found a register
+type registerLocation struct {
+	epochLeaves []string   // Object hashes of the register's epoch, in file order
+	index       int        // The register's position in epochLeaves
+	covering    *SealEntry // Seal closing the epoch, nil while pending
+	lastSeal    *SealEntry // Last epoch boundary before the epoch, nil for the first
+}
+
+// locateRegister finds the first register with objectHashHex and the registers
+// of its epoch. For a pending register the epoch runs to the end of the ledger.
+func locateRegister(objectHashHex string) (registerLocation, error) {
+	if oh := activeObjectHash.Load(); !oh.pattern.MatchString(objectHashHex) {
+		return registerLocation{}, fmt.Errorf("%w: object_hash_hex must be %d lowercase hex chars, got %q", ErrInvalidHex, oh.hexLen, objectHashHex)
+	}
+
+	loc := registerLocation{index: -1}
+	err := scanLedger(func(lineNum int, entryType string, line []byte) error {
+		switch {
+		case entryType == "register":
+			reg, _, err := parseRegister(lineNum, line)
+			if err != nil {
+				return err
+			}
+			if loc.index < 0 && reg.ObjectHashHex == objectHashHex {
+				loc.index = len(loc.epochLeaves)
+			}
+			loc.epochLeaves = append(loc.epochLeaves, reg.ObjectHashHex)
+
+		case entryType == "seal" && loc.index >= 0:
+			seal, _, err := parseSeal(lineNum, line)
+			if err != nil {
+				return err
+			}
+			loc.covering = &seal
+			return errStopScan
+
+		case isEpochBoundary(entryType):
+			seal, _, err := parseEpochBoundary(lineNum, entryType, line)
+			if err != nil {
+				return err
+			}
+			loc.epochLeaves, loc.lastSeal = nil, &seal
+		}
+		return nil
+	})
+	if err != nil {
+		return registerLocation{}, err
+	}
+
+	if loc.index < 0 {
+		return registerLocation{}, fmt.Errorf("%w: %s", ErrRegisterNotFound, objectHashHex)
+	}
+	return loc, nil
+}
+
+// Manifest signature versions (Manifest.SigVersion)
+const (
+	// SigVersionRoot signs the 32 raw bytes of merkle_root alone. Seals written
+	// before sig_version existed carry it implicitly.
+	SigVersionRoot = 0
+
+	// SigVersionDigest signs SigningDigest, which also binds the epoch,
+	// timestamp, canon, prev_seal_root, leaf_count and policy_hash to the signature.
+	SigVersionDigest = 1
+)
+
+// ErrUnsupportedSigVersion is returned for a manifest sig_version this binary does not know
+var ErrUnsupportedSigVersion = errors.New("unsupported manifest sig_version")
+
+// signedFields is what a SigVersionDigest signature commits to, canonicalized
+type signedFields struct {
+	Canon        string `json:"canon"`
+	EpochID      int    `json:"epoch_id"`
+	LeafCount    int    `json:"leaf_count"`
+	MerkleRoot   string `json:"merkle_root"`
+	PolicyHash   string `json:"policy_hash"`
+	PrevSealRoot string `json:"prev_seal_root"`
+	SigVersion   int    `json:"sig_version"`
+	Timestamp    string `json:"timestamp"`
+}
+
+// SigningDigest returns the 64 hex SHA-256 of the canonical JSON of the
+// manifest fields a SigVersionDigest signature covers. The timestamp enters
+// normalized, so AppendSeal normalizing it does not break the signature.
+func (m Manifest) SigningDigest() (string, error) {
+	ts, err := ParseCanonTimestamp(m.Timestamp)
+	if err != nil {
+		return "", fmt.Errorf("manifest timestamp: %w", err)
+	}
+	canon, err := hash.Canonicalize(signedFields{
+		Canon:        m.Canon,
+		EpochID:      m.EpochID,
+		LeafCount:    m.LeafCount,
+		MerkleRoot:   m.MerkleRoot,
+		PolicyHash:   m.PolicyHash,
+		PrevSealRoot: m.PrevSealRoot,
+		SigVersion:   m.SigVersion,
+		Timestamp:    NormalizeTimestamp(ts),
+	})
+	if err != nil {
+		return "", err
+	}
+	return hash.Sha256Hex(canon), nil
+}
+
+// Sign returns a copy of m signed with seedHex under SigVersionDigest, with
+// Signature and PublicKey filled in. Every other field must already hold its
+// final value: changing one afterwards invalidates the signature.
+func (m Manifest) Sign(seedHex string) (Manifest, error) {
+	m.SigVersion = SigVersionDigest
+	digest, err := m.SigningDigest()
+	if err != nil {
+		return Manifest{}, err
+	}
+	sigHex, pubHex, err := sign.SignHashHex(digest, seedHex)
+	if err != nil {
+		return Manifest{}, err
+	}
+	m.Signature, m.PublicKey = sigHex, pubHex
+	return m, nil
+}
+
+// VerifyManifestSignature verifies the manifest's signature under its
+// PublicKey (see VerifySelfSignature). It reports the same results as
+// sign.VerifyHashHex: (false, sign.ErrVerificationFailed) for a well-formed
+// but wrong signature.
+func VerifyManifestSignature(m Manifest) (bool, error) {
+	return m.VerifySelfSignature()
+}
+
+// VerifySelfSignature checks that Signature is PublicKey's signature over this
+// manifest's own content, so a signature lifted from another seal (splicing)
+// fails even though every field is well-formed. Under SigVersionDigest the
+// message is SigningDigest; under SigVersionRoot it is the 32 raw bytes of
+// MerkleRoot and the other fields are not covered. Results are those of
+// sign.VerifyHashHex, or ErrUnsupportedSigVersion.
+func (m Manifest) VerifySelfSignature() (bool, error) {
+	switch m.SigVersion {
+	case SigVersionRoot:
+		return sign.VerifyHashHex(m.MerkleRoot, m.Signature, m.PublicKey)
+	case SigVersionDigest:
+		digest, err := m.SigningDigest()
+		if err != nil {
+			return false, err
+		}
+		return sign.VerifyHashHex(digest, m.Signature, m.PublicKey)
+	default:
+		return false, fmt.Errorf("%w: %d", ErrUnsupportedSigVersion, m.SigVersion)
+	}
+}
+
+// CheckTreeSize checks that a proof's totalLeaves is the tree size this seal
+// signed (LeafCount). A proof against MerkleRoot is only sound for that size:
+// with a smaller one, an internal node of the real tree can pose as a leaf and
+// verify with a truncated proof, since leaves and nodes are both bare hashes.
+//
+// Returns ErrLeafCountMismatch for any other size. A SigVersionRoot manifest
+// carries no LeafCount and binds no size, so every size passes; callers that
+// need the guarantee must require SigVersionDigest.
+func (m Manifest) CheckTreeSize(totalLeaves int) error {
+	if m.SigVersion == SigVersionRoot && m.LeafCount == 0 {
+		return nil
+	}
+	if totalLeaves != m.LeafCount {
+		return fmt.Errorf("%w: proof claims %d leaves, seal for epoch %d signed %d", ErrLeafCountMismatch, totalLeaves, m.EpochID, m.LeafCount)
+	}
+	return nil
+}
+
+// enforceSealSignature makes AppendSeal verify manifest signatures (off by default)
+var enforceSealSignature atomic.Bool
+
+// SetSealSignatureEnforcement turns signature checking in AppendSeal on or off.
+// When on, a manifest whose signature does not verify (see VerifySelfSignature)
+// is rejected with sign.ErrVerificationFailed and never written.
+func SetSealSignatureEnforcement(enabled bool) {
+	enforceSealSignature.Store(enabled)
+}
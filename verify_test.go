@@ -0,0 +1,293 @@
+package ledger
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/olsencastillo051172/forged-lro/src/core/merkle"
+	"github.com/olsencastillo051172/forged-lro/src/core/sign"
+)
+
+func TestVerifyRegisterSealed_Sealed(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2, 3)
+
+	// testHash(3) is the second register of epoch 1
+	result, err := VerifyRegisterSealed(testHash(3))
+	if err != nil {
+		t.Fatalf("VerifyRegisterSealed failed: %v", err)
+	}
+	if !result.Valid || !result.InclusionValid || !result.SignatureValid {
+		t.Fatalf("expected a valid result, got %+v", result)
+	}
+	if result.EpochID != 1 || result.LeafIndex != 1 || result.TotalLeaves != 3 {
+		t.Errorf("EpochID/LeafIndex/TotalLeaves = %d/%d/%d, want 1/1/3", result.EpochID, result.LeafIndex, result.TotalLeaves)
+	}
+	if seals := readSeals(t); result.MerkleRoot != seals[1].Manifest.MerkleRoot {
+		t.Errorf("MerkleRoot = %s, want %s", result.MerkleRoot, seals[1].Manifest.MerkleRoot)
+	}
+}
+
+func TestVerifyRegisterSealed_Pending(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 2)
+	if err := AppendRegister(testHash(10), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	if _, err := VerifyRegisterSealed(testHash(10)); !errors.Is(err, ErrNotSealed) {
+		t.Fatalf("expected ErrNotSealed, got %v", err)
+	}
+	if _, err := VerifyRegisterSealed(testHash(11)); !errors.Is(err, ErrRegisterNotFound) {
+		t.Fatalf("expected ErrRegisterNotFound, got %v", err)
+	}
+	if _, err := VerifyRegisterSealed("XYZ"); !errors.Is(err, ErrInvalidHex) {
+		t.Fatalf("expected ErrInvalidHex, got %v", err)
+	}
+}
+
+func TestVerifyRegisterSealed_TamperedSeal(t *testing.T) {
+	path := setupTestLedger(t)
+	buildSealedLedger(t, 3)
+
+	// Swap the seal's root for another hash, keeping the original signature
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	var seal SealEntry
+	if err := json.Unmarshal([]byte(lines[3]), &seal); err != nil || seal.Type != "seal" {
+		t.Fatalf("expected seal on line 4, got %q", lines[3])
+	}
+	seal.Manifest.MerkleRoot = testHash(42)
+	rewritten, err := json.Marshal(seal)
+	if err != nil {
+		t.Fatalf("failed to marshal seal: %v", err)
+	}
+	lines[3] = string(rewritten)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite ledger: %v", err)
+	}
+
+	result, err := VerifyRegisterSealed(testHash(1))
+	if err != nil {
+		t.Fatalf("VerifyRegisterSealed failed: %v", err)
+	}
+	if result.Valid || result.InclusionValid || result.SignatureValid {
+		t.Fatalf("expected tampered seal to fail both checks, got %+v", result)
+	}
+}
+
+func TestVerifyManifestSignature(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1)
+	m := readSeals(t)[0].Manifest
+
+	if ok, err := VerifyManifestSignature(m); !ok || err != nil {
+		t.Fatalf("expected valid signature, ok=%v err=%v", ok, err)
+	}
+
+	m.MerkleRoot = testHash(99)
+	if ok, err := VerifyManifestSignature(m); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for another root, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestManifestVerifySelfSignature(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1, 1)
+	seals := readSeals(t)
+	m := seals[0].Manifest
+
+	if ok, err := m.VerifySelfSignature(); !ok || err != nil {
+		t.Fatalf("expected valid self-signature, ok=%v err=%v", ok, err)
+	}
+
+	// Splice the second seal's genuine signature onto the first seal's root
+	spliced := m
+	spliced.Signature = seals[1].Manifest.Signature
+	if ok, err := spliced.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a spliced signature, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAppendSeal_SignatureEnforcement(t *testing.T) {
+	setupTestLedger(t)
+	t.Cleanup(func() { SetSealSignatureEnforcement(false) })
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// Well-formed, but signed over a different root
+	bad := signedManifest(t)
+	bad.Signature, _, _ = sign.SignHashHex(testHash(99), testSeedHex)
+
+	SetSealSignatureEnforcement(true)
+	if err := AppendSeal(bad); !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Fatalf("enforcement on: expected ErrVerificationFailed, got %v", err)
+	}
+	if seals := readSeals(t); len(seals) != 0 {
+		t.Fatalf("rejected seal was written: %+v", seals)
+	}
+	if err := AppendSeal(signedManifest(t)); err != nil {
+		t.Fatalf("enforcement on: valid seal rejected: %v", err)
+	}
+
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	bad = signedManifest(t)
+	bad.Signature, _, _ = sign.SignHashHex(testHash(99), testSeedHex)
+	SetSealSignatureEnforcement(false)
+	if err := AppendSeal(bad); err != nil {
+		t.Fatalf("enforcement off: expected the seal to be written, got %v", err)
+	}
+}
+
+func TestProveRegister(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 3)
+	appendN(t, 10, 2)
+	seals := readSeals(t)
+
+	sealed, err := ProveRegister(testHash(1))
+	if err != nil {
+		t.Fatalf("ProveRegister(sealed) failed: %v", err)
+	}
+	if !sealed.Sealed || sealed.EpochID != 0 || sealed.MerkleRoot != seals[0].Manifest.MerkleRoot || sealed.LeafIndex != 1 || sealed.TotalLeaves != 3 {
+		t.Fatalf("sealed proof = %+v", sealed)
+	}
+	if ok, err := merkle.VerifyProof(testHash(1), 1, 3, sealed.Proof, sealed.MerkleRoot); !ok {
+		t.Fatalf("sealed proof does not verify: %v", err)
+	}
+
+	pending, err := ProveRegister(testHash(11))
+	if err != nil {
+		t.Fatalf("ProveRegister(pending) failed: %v", err)
+	}
+	root, _, err := ComputeEpochRoot(mustLastSealTimestamp(t))
+	if err != nil {
+		t.Fatalf("ComputeEpochRoot failed: %v", err)
+	}
+	if pending.Sealed || pending.EpochID != 1 || pending.MerkleRoot != root || pending.LeafIndex != 1 || pending.TotalLeaves != 2 {
+		t.Fatalf("pending proof = %+v, want root %s", pending, root)
+	}
+	if ok, err := merkle.VerifyProof(testHash(11), 1, 2, pending.Proof, pending.MerkleRoot); !ok {
+		t.Fatalf("pending proof does not verify: %v", err)
+	}
+
+	if _, err := ProveRegister(testHash(99)); !errors.Is(err, ErrRegisterNotFound) {
+		t.Fatalf("expected ErrRegisterNotFound, got %v", err)
+	}
+}
+
+func TestVerifySelfSignature_CoversPrevSealRoot(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1, 1)
+	m := readSeals(t)[1].Manifest
+	if m.SigVersion != SigVersionDigest || m.PrevSealRoot == "" {
+		t.Fatalf("expected a digest-signed, anchored seal, got %+v", m)
+	}
+
+	// Re-anchoring the seal elsewhere must break its signature
+	reanchored := m
+	reanchored.PrevSealRoot = testHash(99)
+	if ok, err := reanchored.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a rewritten prev_seal_root, ok=%v err=%v", ok, err)
+	}
+
+	// So must downgrading it to a root-only signature
+	downgraded := m
+	downgraded.SigVersion = SigVersionRoot
+	if ok, err := downgraded.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a downgraded sig_version, ok=%v err=%v", ok, err)
+	}
+
+	unknown := m
+	unknown.SigVersion = 7
+	if ok, err := unknown.VerifySelfSignature(); ok || !errors.Is(err, ErrUnsupportedSigVersion) {
+		t.Errorf("expected ErrUnsupportedSigVersion, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestVerifySelfSignature_RootOnlySealStillVerifies(t *testing.T) {
+	setupTestLedger(t)
+	if err := AppendRegister(testHash(0), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+	// A seal written before sig_version existed: signed over merkle_root only
+	m := signedManifest(t)
+	m.SigVersion = SigVersionRoot
+	m.Signature, m.PublicKey, _ = sign.SignHashHex(m.MerkleRoot, testSeedHex)
+
+	SetSealSignatureEnforcement(true)
+	t.Cleanup(func() { SetSealSignatureEnforcement(false) })
+	if err := AppendSeal(m); err != nil {
+		t.Fatalf("AppendSeal of a root-only seal failed: %v", err)
+	}
+	if ok, err := VerifyManifestSignature(readSeals(t)[0].Manifest); !ok || err != nil {
+		t.Fatalf("expected the root-only seal to verify, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAppendSeal_DigestSignedNeedsAnchor(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 1)
+	if err := AppendRegister(testHash(1), nil); err != nil {
+		t.Fatalf("AppendRegister failed: %v", err)
+	}
+
+	// Stamping prev_seal_root after signing would leave a seal that never verifies
+	m := signedManifest(t)
+	m.PrevSealRoot = ""
+	m, _ = m.Sign(testSeedHex)
+	if err := AppendSeal(m); !errors.Is(err, ErrBrokenAnchor) {
+		t.Fatalf("expected ErrBrokenAnchor, got %v", err)
+	}
+}
+
+func TestCheckTreeSize_InternalNodeForgery(t *testing.T) {
+	setupTestLedger(t)
+	buildSealedLedger(t, 4)
+	m := readSeals(t)[0].Manifest
+	if m.LeafCount != 4 {
+		t.Fatalf("leaf_count = %d, want 4", m.LeafCount)
+	}
+
+	// The node over registers 0 and 1 posing as leaf 0 of a 2-leaf tree
+	left, _ := merkle.HashPair(testHash(0), testHash(1))
+	right, _ := merkle.HashPair(testHash(2), testHash(3))
+	forged := []merkle.ProofNode{{Hash: right, Position: "right"}}
+	if ok, err := merkle.VerifyProof(left, 0, 2, forged, m.MerkleRoot); !ok || err != nil {
+		t.Fatalf("expected the forgery to be a valid 2-leaf proof, ok=%v err=%v", ok, err)
+	}
+
+	if err := m.CheckTreeSize(2); !errors.Is(err, ErrLeafCountMismatch) {
+		t.Fatalf("expected ErrLeafCountMismatch for the forged size, got %v", err)
+	}
+	if err := m.CheckTreeSize(4); err != nil {
+		t.Fatalf("signed size rejected: %v", err)
+	}
+
+	// The size cannot be lowered without breaking the signature
+	m.LeafCount = 2
+	if ok, err := m.VerifySelfSignature(); ok || !errors.Is(err, sign.ErrVerificationFailed) {
+		t.Errorf("expected ErrVerificationFailed for a rewritten leaf_count, ok=%v err=%v", ok, err)
+	}
+}
+
+func TestAppendSeal_DigestSignedLeafCount(t *testing.T) {
+	setupTestLedger(t)
+	appendN(t, 0, 3)
+
+	m := signedManifest(t)
+	m.LeafCount = 2
+	m, _ = m.Sign(testSeedHex)
+	if err := AppendSeal(m); !errors.Is(err, ErrLeafCountMismatch) {
+		t.Fatalf("expected ErrLeafCountMismatch, got %v", err)
+	}
+}